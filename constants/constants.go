@@ -1,10 +1,44 @@
 package constants
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 var (
 	FactoryAddress = common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984")
 	AddressZero    = common.HexToAddress("0x0000000000000000000000000000000000000000")
 	EmptyHook      = "0x0000000000000000000000000000000000000000"
 	EmptyBytes     = "0x"
+
+	// MsgSender is resolved to msg.sender by the position manager and the router when used as a recipient
+	MsgSender = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	// AddressThis is resolved to the address of the position manager or the router when used as a recipient
+	AddressThis = common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	// Permit2Address is the address of the Permit2 contract, the same on every chain
+	Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+)
+
+const (
+	// MaxLPFee is the maximum LP fee in hundredths of a bip, i.e. 100%
+	MaxLPFee int64 = 1000000
+	// DynamicFeeFlag as the pool key fee marks a pool whose LP fee is managed by its hook
+	DynamicFeeFlag int64 = 0x800000
+	// OverrideFeeFlag is set on the fee returned by beforeSwap when the hook overrides the LP fee
+	OverrideFeeFlag int64 = 0x400000
+)
+
+var (
+	NegativeOne = big.NewInt(-1)
+	Zero        = big.NewInt(0)
+	One         = big.NewInt(1)
+
+	// used in liquidity amount math
+	Q96  = new(big.Int).Exp(big.NewInt(2), big.NewInt(96), nil)
+	Q192 = new(big.Int).Exp(Q96, big.NewInt(2), nil)
+
+	PercentZero = core.NewFraction(big.NewInt(0), big.NewInt(1))
 )
@@ -0,0 +1,350 @@
+package entities
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrUnknownTypedDataType = errors.New("unknown EIP-712 type")
+	ErrInvalidTypedData     = errors.New("invalid EIP-712 typed data")
+	ErrInvalidSignature     = errors.New("invalid signature")
+)
+
+// TypedDataField is a member of an EIP-712 struct type
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain is the EIP-712 domain, fields left empty are not part of the domain
+type TypedDataDomain struct {
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+}
+
+/**
+ * TypedData is EIP-712 typed data as signed by eth_signTypedData_v4. Message holds the values of the fields of the
+ * primary type keyed by field name: integers as *big.Int, addresses as common.Address, bytes as []byte, structs as
+ * map[string]interface{} and arrays as slices. It marshals to the JSON expected by wallets.
+ */
+type TypedData struct {
+	// The struct types, without EIP712Domain
+	Types       map[string][]TypedDataField
+	PrimaryType string
+	Domain      TypedDataDomain
+	Message     map[string]interface{}
+}
+
+// fields returns the EIP712Domain type of the fields that are set
+func (d TypedDataDomain) fields() []TypedDataField {
+	var fields []TypedDataField
+	if d.Name != "" {
+		fields = append(fields, TypedDataField{"name", "string"})
+	}
+	if d.Version != "" {
+		fields = append(fields, TypedDataField{"version", "string"})
+	}
+	if d.ChainId != nil {
+		fields = append(fields, TypedDataField{"chainId", "uint256"})
+	}
+	if d.VerifyingContract != (common.Address{}) {
+		fields = append(fields, TypedDataField{"verifyingContract", "address"})
+	}
+	return fields
+}
+
+func (d TypedDataDomain) values() map[string]interface{} {
+	return map[string]interface{}{
+		"name":              d.Name,
+		"version":           d.Version,
+		"chainId":           d.ChainId,
+		"verifyingContract": d.VerifyingContract,
+	}
+}
+
+// DomainSeparator returns the hash of the domain
+func (t *TypedData) DomainSeparator() (common.Hash, error) {
+	types := typedDataTypes{"EIP712Domain": t.Domain.fields()}
+	return types.hashStruct("EIP712Domain", t.Domain.values())
+}
+
+// HashStruct returns the EIP-712 hash of the message
+func (t *TypedData) HashStruct() (common.Hash, error) {
+	if _, ok := t.Types[t.PrimaryType]; !ok {
+		return common.Hash{}, fmt.Errorf("%w: %s", ErrUnknownTypedDataType, t.PrimaryType)
+	}
+	return typedDataTypes(t.Types).hashStruct(t.PrimaryType, t.Message)
+}
+
+// Digest returns the hash that is signed, keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func (t *TypedData) Digest() (common.Hash, error) {
+	domainSeparator, err := t.DomainSeparator()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	structHash, err := t.HashStruct()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], structHash[:]), nil
+}
+
+// MarshalJSON returns the typed data in the format of eth_signTypedData_v4, integers are decimal strings
+func (t *TypedData) MarshalJSON() ([]byte, error) {
+	types := make(map[string][]TypedDataField, len(t.Types)+1)
+	for name, fields := range t.Types {
+		types[name] = fields
+	}
+	domainFields := t.Domain.fields()
+	types["EIP712Domain"] = domainFields
+
+	domainValues := t.Domain.values()
+	domain := make(map[string]interface{}, len(domainFields))
+	for _, field := range domainFields {
+		domain[field.Name] = jsonTypedValue(domainValues[field.Name])
+	}
+
+	return json.Marshal(struct {
+		Types       map[string][]TypedDataField `json:"types"`
+		PrimaryType string                      `json:"primaryType"`
+		Domain      map[string]interface{}      `json:"domain"`
+		Message     interface{}                 `json:"message"`
+	}{types, t.PrimaryType, domain, jsonTypedValue(t.Message)})
+}
+
+func jsonTypedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return v.String()
+		}
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = jsonTypedValue(elem)
+		}
+		return out
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice {
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = jsonTypedValue(rv.Index(i).Interface())
+		}
+		return out
+	}
+	return value
+}
+
+// typedDataTypes are the struct types of typed data keyed by name
+type typedDataTypes map[string][]TypedDataField
+
+// baseType strips the array suffixes of a type, e.g. PermitDetails[] is an array of PermitDetails
+func baseType(typ string) string {
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		return typ[:i]
+	}
+	return typ
+}
+
+// dependencies adds the struct types referenced by the type, including itself, to found
+func (types typedDataTypes) dependencies(typ string, found map[string]bool) {
+	typ = baseType(typ)
+	if found[typ] {
+		return
+	}
+	fields, ok := types[typ]
+	if !ok {
+		return
+	}
+	found[typ] = true
+	for _, field := range fields {
+		types.dependencies(field.Type, found)
+	}
+}
+
+// encodeType returns the type followed by the types it references sorted by name, e.g.
+// PermitSingle(PermitDetails details,address spender,uint256 sigDeadline)PermitDetails(...)
+func (types typedDataTypes) encodeType(primaryType string) string {
+	found := make(map[string]bool)
+	types.dependencies(primaryType, found)
+	delete(found, primaryType)
+	deps := make([]string, 0, len(found))
+	for dep := range found {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+
+	var b strings.Builder
+	for _, typ := range append([]string{primaryType}, deps...) {
+		b.WriteString(typ)
+		b.WriteByte('(')
+		for i, field := range types[typ] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(field.Type)
+			b.WriteByte(' ')
+			b.WriteString(field.Name)
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+func (types typedDataTypes) hashStruct(typ string, data map[string]interface{}) (common.Hash, error) {
+	fields := types[typ]
+	typeHash := crypto.Keccak256Hash([]byte(types.encodeType(typ)))
+	encoded := make([]byte, 0, 32*(len(fields)+1))
+	encoded = append(encoded, typeHash[:]...)
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return common.Hash{}, fmt.Errorf("%w: missing %s of %s", ErrInvalidTypedData, field.Name, typ)
+		}
+		word, err := types.encodeValue(field.Type, value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("%s.%s: %w", typ, field.Name, err)
+		}
+		encoded = append(encoded, word...)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// encodeValue returns the 32 bytes a value is encoded to in the hashed struct
+func (types typedDataTypes) encodeValue(typ string, value interface{}) ([]byte, error) {
+	// arrays are hashed as the concatenation of the encoding of their elements
+	if strings.HasSuffix(typ, "]") {
+		elemType := typ[:strings.LastIndexByte(typ, '[')]
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%w: %T as %s", ErrInvalidTypedData, value, typ)
+		}
+		encoded := make([]byte, 0, 32*v.Len())
+		for i := 0; i < v.Len(); i++ {
+			word, err := types.encodeValue(elemType, v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, word...)
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+
+	if _, ok := types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T as %s", ErrInvalidTypedData, value, typ)
+		}
+		hash, err := types.hashStruct(typ, data)
+		return hash[:], err
+	}
+
+	switch typ {
+	case "string":
+		switch v := value.(type) {
+		case string:
+			return crypto.Keccak256([]byte(v)), nil
+		case []byte:
+			return crypto.Keccak256(v), nil
+		}
+		return nil, fmt.Errorf("%w: %T as %s", ErrInvalidTypedData, value, typ)
+	case "bytes":
+		abiType, _ := abi.NewType("bytes", "", nil)
+		b, err := toAbiValue(abiType, value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b.Bytes()), nil
+	}
+
+	// atomic types are encoded as their abi encoding
+	abiType, err := abi.NewType(typ, "", nil)
+	if err != nil || abiType.T == abi.TupleTy || abiType.T == abi.SliceTy || abiType.T == abi.ArrayTy {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTypedDataType, typ)
+	}
+	v, err := toAbiValue(abiType, value)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: abiType}}.Pack(v.Interface())
+}
+
+// Signer signs EIP-712 typed data, e.g. with a private key, a wallet or a remote signing service
+type Signer interface {
+	// Address returns the address of the account the signatures recover to
+	Address() common.Address
+	// SignTypedData returns the 65 bytes r ‖ s ‖ v signature of the digest of the typed data, v being 27 or 28
+	SignTypedData(data *TypedData) ([]byte, error)
+}
+
+// PrivateKeySigner signs typed data with a secp256k1 private key
+type PrivateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key}
+}
+
+func (s *PrivateKeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *PrivateKeySigner) SignTypedData(data *TypedData) ([]byte, error) {
+	digest, err := data.Digest()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(digest[:], s.key)
+	if err != nil {
+		return nil, err
+	}
+	// contracts expect the recovery id of ecrecover
+	signature[64] += 27
+	return signature, nil
+}
+
+/**
+ * Returns the address that signed the typed data
+ * @param data The signed typed data
+ * @param signature The 65 bytes r ‖ s ‖ v signature, v may be 0, 1, 27 or 28
+ */
+func RecoverTypedDataSigner(data *TypedData, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, crypto.SignatureLength, len(signature))
+	}
+	digest, err := data.Digest()
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := append([]byte{}, signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
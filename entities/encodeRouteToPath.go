@@ -15,7 +15,8 @@ type PathKey struct {
 }
 
 func EncodeRouteToPath(route *Route, exactOutput bool) ([]PathKey, error) {
-	pools := route.Pools
+	// copy, so that reversing does not reorder the pools of the route
+	pools := append([]*Pool{}, route.Pools...)
 
 	startingCurrency := route.PathInput
 	if exactOutput {
@@ -32,11 +33,11 @@ func EncodeRouteToPath(route *Route, exactOutput bool) ([]PathKey, error) {
 		}
 
 		pathKey := PathKey{
-			IntermediateCurrency: nextCurrency.Address,
+			IntermediateCurrency: currencyAddress(nextCurrency),
 			Fee:                  pool.Fee,
 			TickSpacing:          pool.TickSpacing,
 			Hooks:                pool.Hooks,
-			HookData:             []byte("0x"),
+			HookData:             []byte{},
 		}
 
 		pathKeys = append(pathKeys, pathKey)
@@ -7,7 +7,7 @@ import (
 )
 
 func AmountWithPathCurrency(amount *core.CurrencyAmount, pool *Pool) (*core.CurrencyAmount, error) {
-	pathCurrency := getPathCurrency(amount.Currency.Wrapped(), pool)
+	pathCurrency := getPathCurrency(amount.Currency, pool)
 	if pathCurrency == nil {
 		return &core.CurrencyAmount{}, fmt.Errorf("expected currency %s to be either %s or %s", amount.Currency.Symbol(), pool.Currency0.Symbol(), pool.Currency1.Symbol())
 	}
@@ -19,11 +19,14 @@ func AmountWithPathCurrency(amount *core.CurrencyAmount, pool *Pool) (*core.Curr
 	return &fractionalAmount, nil
 }
 
-func getPathCurrency(currency *core.Token, pool *Pool) *core.Token {
-	if pool.InvolvesToken(currency) {
+func getPathCurrency(currency core.Currency, pool *Pool) core.Currency {
+	// return currency if the currency matches a currency of the pool
+	if pool.InvolvesCurrency(currency) {
 		return currency
-	} else if pool.InvolvesToken(currency.Wrapped()) {
+		// return currency.Wrapped() if pool involves wrapped currency
+	} else if pool.InvolvesCurrency(currency.Wrapped()) {
 		return currency.Wrapped()
+		// return native currency if pool involves native version of wrapped currency (only applies to V4)
 	} else if pool.Currency0.Wrapped().Equal(currency) {
 		return pool.Currency0
 	} else if pool.Currency1.Wrapped().Equal(currency) {
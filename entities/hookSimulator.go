@@ -0,0 +1,208 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	v4utils "github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrUnsupportedHook            = errors.New("unsupported hook")
+	ErrHookVetoedSwap             = errors.New("hook vetoed swap")
+	ErrHookDeltaExceedsSwapAmount = errors.New("hook delta exceeds swap amount")
+	ErrInvalidLPFeeOverride       = errors.New("invalid lp fee override")
+	ErrNilHookSimulator           = errors.New("nil hook simulator")
+)
+
+// SwapParams mirrors IPoolManager.SwapParams as seen by a hook. AmountSpecified follows the
+// v4 sign convention: negative for an exact input swap, positive for an exact output swap.
+type SwapParams struct {
+	ZeroForOne        bool
+	AmountSpecified   *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+// BalanceDelta is the pair of currency deltas of a swap from the swapper's perspective,
+// negative amounts are owed to the pool and positive amounts are owed to the swapper.
+type BalanceDelta struct {
+	Amount0 *big.Int
+	Amount1 *big.Int
+}
+
+// BeforeSwapResult is the simulated return value of a hook's beforeSwap call
+type BeforeSwapResult struct {
+	// DeltaSpecified and DeltaUnspecified form the BeforeSwapDelta. They are only applied
+	// when the hook address has the beforeSwapReturnsDelta flag.
+	DeltaSpecified   *big.Int
+	DeltaUnspecified *big.Int
+	// LPFeeOverride replaces the LP fee of a dynamic fee pool for this swap when it has
+	// constants.OverrideFeeFlag set
+	LPFeeOverride int64
+}
+
+// HookSimulator reproduces the swap-affecting behaviour of a deployed hook contract so that
+// pools using it can be quoted off-chain. BeforeSwap is only called when the hook address has
+// the beforeSwap flag and AfterSwap only when it has the afterSwap flag. Returning an error
+// (e.g. ErrHookVetoedSwap) from either call aborts the swap, the same way a reverting hook would.
+type HookSimulator interface {
+	BeforeSwap(pool *Pool, params SwapParams) (*BeforeSwapResult, error)
+	// AfterSwap receives the delta of the swap itself and returns the hook's delta in the
+	// unspecified currency, which is only applied with the afterSwapReturnsDelta flag
+	AfterSwap(pool *Pool, params SwapParams, delta BalanceDelta) (*big.Int, error)
+}
+
+// NeutralHookSimulator simulates hooks whose swap callbacks do not change the swap outcome,
+// e.g. hooks that only emit events or record oracle observations
+type NeutralHookSimulator struct{}
+
+func (NeutralHookSimulator) BeforeSwap(pool *Pool, params SwapParams) (*BeforeSwapResult, error) {
+	return &BeforeSwapResult{}, nil
+}
+
+func (NeutralHookSimulator) AfterSwap(pool *Pool, params SwapParams, delta BalanceDelta) (*big.Int, error) {
+	return nil, nil
+}
+
+// HookSimulators maps hook addresses to the simulators of the hooks deployed at them. It is a
+// plain map owned by the caller: pools only ever use their own HookSimulator, Configure is the
+// way to set it from the map. Like any map it must not be written while it is being read.
+type HookSimulators map[common.Address]HookSimulator
+
+// Register makes sim the simulator of the given hook address
+func (s HookSimulators) Register(hooks common.Address, sim HookSimulator) error {
+	if sim == nil {
+		return ErrNilHookSimulator
+	}
+	s[hooks] = sim
+	return nil
+}
+
+// RegisterNeutral marks the given hook address as swap-neutral
+func (s HookSimulators) RegisterNeutral(hooks common.Address) error {
+	return s.Register(hooks, NeutralHookSimulator{})
+}
+
+// Configure sets the HookSimulator of the pool to the simulator registered for its hook address.
+// Pools whose hook has no swap callbacks need none, for other pools it returns ErrUnsupportedHook
+// when no simulator is registered.
+func (s HookSimulators) Configure(pool *Pool) error {
+	if sim, ok := s[pool.Hooks]; ok {
+		pool.HookSimulator = sim
+		return nil
+	}
+	if pool.hookImpactsSwap() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedHook, pool.Hooks.Hex())
+	}
+	return nil
+}
+
+func (p *Pool) hookSimulator() (HookSimulator, error) {
+	if p.HookSimulator == nil {
+		return nil, ErrUnsupportedHook
+	}
+	return p.HookSimulator, nil
+}
+
+func (p *Pool) hasHookPermission(option v4utils.HookOption) bool {
+	return v4utils.HasHookAddressPermission(p.Hooks, option)
+}
+
+// hookedSwap runs a swap through the pool's hook simulator the way PoolManager.swap runs it
+// through Hooks.beforeSwap and Hooks.afterSwap. Amounts in and out use the same convention as
+// computeSwap: a positive amountSpecified is an exact input.
+func (p *Pool) hookedSwap(sim HookSimulator, zeroForOne bool, amountSpecified, sqrtPriceLimitX96 *big.Int) (*SwapResult, error) {
+	params := SwapParams{
+		ZeroForOne:        zeroForOne,
+		AmountSpecified:   new(big.Int).Neg(amountSpecified),
+		SqrtPriceLimitX96: sqrtPriceLimitX96,
+	}
+	exactInput := params.AmountSpecified.Sign() < 0
+
+	amountToSwap := params.AmountSpecified
+	// an unknown LP fee only matters when the hook does not override it
+	lpFee, lpFeeErr := p.currentLPFee()
+	if lpFeeErr != nil && !errors.Is(lpFeeErr, ErrLPFeeNotSet) {
+		return nil, lpFeeErr
+	}
+	hookDeltaSpecified := big.NewInt(0)
+	hookDeltaUnspecified := big.NewInt(0)
+
+	if p.hasHookPermission(v4utils.BeforeSwap) {
+		result, err := sim.BeforeSwap(p, params)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			// only dynamic fee pools let their hook override the LP fee
+			if p.IsDynamicFee() && result.LPFeeOverride&constants.OverrideFeeFlag != 0 {
+				lpFee, lpFeeErr = result.LPFeeOverride&^constants.OverrideFeeFlag, nil
+				if lpFee < 0 || lpFee > constants.MaxLPFee {
+					return nil, ErrInvalidLPFeeOverride
+				}
+			}
+			if p.hasHookPermission(v4utils.BeforeSwapReturnsDelta) {
+				if result.DeltaSpecified != nil && result.DeltaSpecified.Sign() != 0 {
+					hookDeltaSpecified = result.DeltaSpecified
+					amountToSwap = new(big.Int).Add(amountToSwap, hookDeltaSpecified)
+					// the hook delta must not flip the direction of the swap
+					if (exactInput && amountToSwap.Sign() > 0) || (!exactInput && amountToSwap.Sign() < 0) {
+						return nil, ErrHookDeltaExceedsSwapAmount
+					}
+				}
+				if result.DeltaUnspecified != nil {
+					hookDeltaUnspecified = result.DeltaUnspecified
+				}
+			}
+		}
+	}
+
+	if lpFeeErr != nil {
+		return nil, lpFeeErr
+	}
+
+	// the pool is left untouched when the hook took the whole amount
+	swapResult, err := p.computeSwap(zeroForOne, new(big.Int).Neg(amountToSwap), sqrtPriceLimitX96, lpFee)
+	if err != nil {
+		return nil, err
+	}
+
+	// rebuild the swap delta in v4 terms
+	specifiedIsCurrency0 := exactInput == zeroForOne
+	specifiedDelta := new(big.Int).Add(amountToSwap, swapResult.RemainingTargetAmount)
+	unspecifiedDelta := new(big.Int).Neg(swapResult.AmountCalculated)
+	swapDelta := toBalanceDelta(specifiedIsCurrency0, specifiedDelta, unspecifiedDelta)
+
+	if p.hasHookPermission(v4utils.AfterSwap) {
+		afterSwapDelta, err := sim.AfterSwap(p, params, swapDelta)
+		if err != nil {
+			return nil, err
+		}
+		if afterSwapDelta != nil && p.hasHookPermission(v4utils.AfterSwapReturnsDelta) {
+			hookDeltaUnspecified = new(big.Int).Add(hookDeltaUnspecified, afterSwapDelta)
+		}
+	}
+
+	// the hook's deltas are owed by the swapper on top of the pool's
+	specifiedDelta.Sub(specifiedDelta, hookDeltaSpecified)
+	unspecifiedDelta.Sub(unspecifiedDelta, hookDeltaUnspecified)
+
+	return &SwapResult{
+		AmountCalculated:      new(big.Int).Neg(unspecifiedDelta),
+		SqrtRatioX96:          swapResult.SqrtRatioX96,
+		Liquidity:             swapResult.Liquidity,
+		RemainingTargetAmount: new(big.Int).Sub(specifiedDelta, params.AmountSpecified),
+		CurrentTick:           swapResult.CurrentTick,
+		CrossInitTickLoops:    swapResult.CrossInitTickLoops,
+	}, nil
+}
+
+func toBalanceDelta(specifiedIsCurrency0 bool, specified, unspecified *big.Int) BalanceDelta {
+	if specifiedIsCurrency0 {
+		return BalanceDelta{Amount0: new(big.Int).Set(specified), Amount1: new(big.Int).Set(unspecified)}
+	}
+	return BalanceDelta{Amount0: new(big.Int).Set(unspecified), Amount1: new(big.Int).Set(specified)}
+}
@@ -0,0 +1,252 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// beforeSwap and beforeSwapReturnsDelta
+	testBeforeSwapDeltaHook = common.HexToAddress("0x0000000000000000000000000000000000000088")
+	// afterSwap and afterSwapReturnsDelta
+	testAfterSwapDeltaHook = common.HexToAddress("0x0000000000000000000000000000000000000044")
+	// beforeSwap only
+	testBeforeSwapHook = common.HexToAddress("0x0000000000000000000000000000000000000080")
+)
+
+// stubHookSimulator returns fixed results and records the calls it gets
+type stubHookSimulator struct {
+	before    BeforeSwapResult
+	beforeErr error
+	after     *big.Int
+
+	beforeCalls int
+	afterDelta  *BalanceDelta
+}
+
+func (s *stubHookSimulator) BeforeSwap(pool *Pool, params SwapParams) (*BeforeSwapResult, error) {
+	s.beforeCalls++
+	if s.beforeErr != nil {
+		return nil, s.beforeErr
+	}
+	result := s.before
+	return &result, nil
+}
+
+func (s *stubHookSimulator) AfterSwap(pool *Pool, params SwapParams, delta BalanceDelta) (*big.Int, error) {
+	s.afterDelta = &delta
+	return s.after, nil
+}
+
+func hookedTestPool(t *testing.T, fee int64, hooks common.Address, sim HookSimulator) *Pool {
+	t.Helper()
+	pool := testPoolWithLiquidity(t, fee, hooks)
+	pool.HookSimulator = sim
+	return pool
+}
+
+// plainOutput quotes the input on the pool without a hook
+func plainOutput(t *testing.T, fee int64, amountIn int64) *big.Int {
+	t.Helper()
+	result, err := testPoolWithLiquidity(t, fee, common.Address{}).GetOutputAmount(core.FromRawAmount(testToken0, big.NewInt(amountIn)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.ReturnedAmount.Quotient()
+}
+
+func expectAmount(t *testing.T, name string, got, want *big.Int) {
+	t.Helper()
+	if got.Cmp(want) != 0 {
+		t.Errorf("%s %s, want %s", name, got, want)
+	}
+}
+
+func TestHookedSwapBeforeSwapDelta(t *testing.T) {
+	const amountIn = 1000000
+	inputAmount := core.FromRawAmount(testToken0, big.NewInt(amountIn))
+
+	t.Run("specified", func(t *testing.T) {
+		// the hook takes 40% of the input, the pool swaps the rest
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaSpecified: big.NewInt(400000)}}
+		result, err := hookedTestPool(t, 3000, testBeforeSwapDeltaHook, sim).GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectAmount(t, "output", result.ReturnedAmount.Quotient(), plainOutput(t, 3000, amountIn-400000))
+		expectAmount(t, "remaining input", result.RemainingAmountIn.Quotient(), big.NewInt(0))
+	})
+	t.Run("unspecified", func(t *testing.T) {
+		// the hook takes 1000 of the output
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaUnspecified: big.NewInt(1000)}}
+		result, err := hookedTestPool(t, 3000, testBeforeSwapDeltaHook, sim).GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectAmount(t, "output", result.ReturnedAmount.Quotient(), new(big.Int).Sub(plainOutput(t, 3000, amountIn), big.NewInt(1000)))
+	})
+	t.Run("exact output", func(t *testing.T) {
+		// the hook pays 400 of the output, the pool swaps the rest
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaSpecified: big.NewInt(-400)}}
+		result, err := hookedTestPool(t, 3000, testBeforeSwapDeltaHook, sim).GetInputAmount(core.FromRawAmount(testToken1, big.NewInt(1000)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := testPoolWithLiquidity(t, 3000, common.Address{}).GetInputAmount(core.FromRawAmount(testToken1, big.NewInt(600)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectAmount(t, "input", result.ReturnedAmount.Quotient(), plain.ReturnedAmount.Quotient())
+	})
+	t.Run("ignored without the returns delta flag", func(t *testing.T) {
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaSpecified: big.NewInt(400000), DeltaUnspecified: big.NewInt(1000)}}
+		result, err := hookedTestPool(t, 3000, testBeforeSwapHook, sim).GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectAmount(t, "output", result.ReturnedAmount.Quotient(), plainOutput(t, 3000, amountIn))
+		if sim.beforeCalls != 1 {
+			t.Errorf("beforeSwap called %d times", sim.beforeCalls)
+		}
+	})
+	t.Run("exceeds swap amount", func(t *testing.T) {
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaSpecified: big.NewInt(amountIn + 1)}}
+		if _, err := hookedTestPool(t, 3000, testBeforeSwapDeltaHook, sim).GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrHookDeltaExceedsSwapAmount) {
+			t.Errorf("returned %v", err)
+		}
+	})
+	t.Run("whole amount", func(t *testing.T) {
+		// the hook fills the swap itself, e.g. a custom curve, and leaves the pool untouched
+		sim := &stubHookSimulator{before: BeforeSwapResult{DeltaSpecified: big.NewInt(amountIn), DeltaUnspecified: big.NewInt(-990000)}}
+		pool := hookedTestPool(t, 3000, testBeforeSwapDeltaHook, sim)
+		result, err := pool.GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectAmount(t, "output", result.ReturnedAmount.Quotient(), big.NewInt(990000))
+		expectAmount(t, "price", result.NewPoolState.SqrtRatioX96, pool.SqrtRatioX96)
+		expectAmount(t, "liquidity", result.NewPoolState.Liquidity, pool.Liquidity)
+	})
+}
+
+func TestHookedSwapAfterSwapDelta(t *testing.T) {
+	const amountIn = 1000000
+	sim := &stubHookSimulator{after: big.NewInt(500)}
+	result, err := hookedTestPool(t, 3000, testAfterSwapDeltaHook, sim).GetOutputAmount(core.FromRawAmount(testToken0, big.NewInt(amountIn)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := plainOutput(t, 3000, amountIn)
+	expectAmount(t, "output", result.ReturnedAmount.Quotient(), new(big.Int).Sub(plain, big.NewInt(500)))
+	if sim.beforeCalls != 0 {
+		t.Error("beforeSwap called without its flag")
+	}
+	// afterSwap sees the delta of the pool swap, negative for what the swapper owes
+	if sim.afterDelta == nil {
+		t.Fatal("afterSwap not called")
+	}
+	expectAmount(t, "amount0", sim.afterDelta.Amount0, big.NewInt(-amountIn))
+	expectAmount(t, "amount1", sim.afterDelta.Amount1, plain)
+}
+
+func TestHookedSwapVeto(t *testing.T) {
+	sim := &stubHookSimulator{beforeErr: ErrHookVetoedSwap}
+	pool := hookedTestPool(t, 3000, testBeforeSwapHook, sim)
+	if _, err := pool.GetOutputAmount(core.FromRawAmount(testToken0, big.NewInt(1000)), nil); !errors.Is(err, ErrHookVetoedSwap) {
+		t.Errorf("exact input returned %v", err)
+	}
+	if _, err := pool.GetInputAmount(core.FromRawAmount(testToken1, big.NewInt(1000)), nil); !errors.Is(err, ErrHookVetoedSwap) {
+		t.Errorf("exact output returned %v", err)
+	}
+}
+
+func TestHookedSwapLPFeeOverride(t *testing.T) {
+	const amountIn = 1000000
+	inputAmount := core.FromRawAmount(testToken0, big.NewInt(amountIn))
+	override := overrideFeeSimulator{lpFee: 10000}
+
+	// a static fee pool keeps its fee
+	result, err := hookedTestPool(t, 3000, testBeforeSwapHook, override).GetOutputAmount(inputAmount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectAmount(t, "static fee pool output", result.ReturnedAmount.Quotient(), plainOutput(t, 3000, amountIn))
+
+	dynamic := hookedTestPool(t, constants.DynamicFeeFlag, testBeforeSwapHook, override)
+	if err := dynamic.SetLPFee(3000); err != nil {
+		t.Fatal(err)
+	}
+	result, err = dynamic.GetOutputAmount(inputAmount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectAmount(t, "dynamic fee pool output", result.ReturnedAmount.Quotient(), plainOutput(t, 10000, amountIn))
+
+	// the fee is only overridden with the override flag
+	dynamic.HookSimulator = &stubHookSimulator{before: BeforeSwapResult{LPFeeOverride: 10000}}
+	result, err = dynamic.GetOutputAmount(inputAmount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectAmount(t, "output without the override flag", result.ReturnedAmount.Quotient(), plainOutput(t, 3000, amountIn))
+
+	dynamic.HookSimulator = overrideFeeSimulator{lpFee: constants.MaxLPFee + 1}
+	if _, err := dynamic.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrInvalidLPFeeOverride) {
+		t.Errorf("fee above 100%% returned %v", err)
+	}
+}
+
+func TestHookSimulators(t *testing.T) {
+	inputAmount := core.FromRawAmount(testToken0, big.NewInt(1000000))
+
+	pool := testPoolWithLiquidity(t, 3000, testBeforeSwapHook)
+	if _, err := pool.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrUnsupportedHook) {
+		t.Errorf("swap without a simulator returned %v", err)
+	}
+
+	simulators := HookSimulators{}
+	if err := simulators.Configure(pool); !errors.Is(err, ErrUnsupportedHook) {
+		t.Errorf("configuring an unregistered hook returned %v", err)
+	}
+	if err := simulators.Register(testBeforeSwapHook, nil); !errors.Is(err, ErrNilHookSimulator) {
+		t.Errorf("registering nil returned %v", err)
+	}
+	if err := simulators.RegisterNeutral(testBeforeSwapHook); err != nil {
+		t.Fatal(err)
+	}
+	if err := simulators.Configure(pool); err != nil {
+		t.Fatal(err)
+	}
+	result, err := pool.GetOutputAmount(inputAmount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectAmount(t, "neutral hook output", result.ReturnedAmount.Quotient(), plainOutput(t, 3000, 1000000))
+
+	// pools are not affected by simulators registered after they were configured, or by other pools
+	if err := simulators.Register(testBeforeSwapHook, &stubHookSimulator{beforeErr: ErrHookVetoedSwap}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.GetOutputAmount(inputAmount, nil); err != nil {
+		t.Errorf("configured pool returned %v", err)
+	}
+	other := testPoolWithLiquidity(t, 3000, testBeforeSwapHook)
+	if _, err := other.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrUnsupportedHook) {
+		t.Errorf("unconfigured pool returned %v", err)
+	}
+
+	// hooks without swap callbacks need no simulator
+	initializeHook := common.HexToAddress("0x0000000000000000000000000000000000002000")
+	plain := testPoolWithLiquidity(t, 3000, initializeHook)
+	if err := simulators.Configure(plain); err != nil || plain.HookSimulator != nil {
+		t.Errorf("configuring a hook without swap callbacks returned %v", err)
+	}
+	if _, err := plain.GetOutputAmount(inputAmount, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,331 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidNonce = errors.New("nonce is not a uint256")
+
+const PERMIT_DETAILS_STRUCT = "(address token,uint160 amount,uint48 expiration,uint48 nonce)"
+const PERMIT_SINGLE_STRUCT = "(" + PERMIT_DETAILS_STRUCT + " details,address spender,uint256 sigDeadline)"
+const PERMIT_BATCH_STRUCT = "(" + PERMIT_DETAILS_STRUCT + "[] details,address spender,uint256 sigDeadline)"
+const ALLOWANCE_TRANSFER_DETAILS_STRUCT = "(address from,address to,uint160 amount,address token)"
+
+// PermitDetails is the Permit2 allowance of one token
+type PermitDetails struct {
+	Token common.Address
+	// The maximum amount the spender may transfer, a uint160
+	Amount *big.Int
+	// The timestamp at which the allowance expires, a uint48
+	Expiration *big.Int
+	// The nonce of the allowance of the owner, token and spender, a uint48
+	Nonce *big.Int
+}
+
+// PermitSingle is a Permit2 permit of an allowance of one token
+type PermitSingle struct {
+	Details PermitDetails
+	Spender common.Address
+	// The timestamp after which the signature is no longer valid
+	SigDeadline *big.Int
+}
+
+// PermitBatch is a Permit2 permit of the allowances of several tokens to one spender
+type PermitBatch struct {
+	Details     []PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// AllowanceTransferDetails is one transfer of a Permit2 batched transferFrom
+type AllowanceTransferDetails struct {
+	From   common.Address
+	To     common.Address
+	Amount *big.Int
+	Token  common.Address
+}
+
+// Permit2Permit is a PermitSingle with the signature of its owner
+type Permit2Permit struct {
+	PermitSingle
+	Signature []byte
+}
+
+// TokenPermissions is the token and maximum amount of a SignatureTransfer permit
+type TokenPermissions struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+/**
+ * PermitTransferFrom is a SignatureTransfer permit of a single transfer of one token. The spender is not part of the
+ * calldata, Permit2 checks the signature against msg.sender.
+ */
+type PermitTransferFrom struct {
+	Permitted TokenPermissions
+	Spender   common.Address
+	// An unordered nonce, see NonceBitmapPosition
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// PermitBatchTransferFrom is a SignatureTransfer permit of a single transfer of each of several tokens
+type PermitBatchTransferFrom struct {
+	Permitted []TokenPermissions
+	Spender   common.Address
+	Nonce     *big.Int
+	Deadline  *big.Int
+}
+
+var (
+	permitDetailsType = []TypedDataField{
+		{"token", "address"},
+		{"amount", "uint160"},
+		{"expiration", "uint48"},
+		{"nonce", "uint48"},
+	}
+	tokenPermissionsType = []TypedDataField{
+		{"token", "address"},
+		{"amount", "uint256"},
+	}
+
+	permitSingleTypes = map[string][]TypedDataField{
+		"PermitSingle": {
+			{"details", "PermitDetails"},
+			{"spender", "address"},
+			{"sigDeadline", "uint256"},
+		},
+		"PermitDetails": permitDetailsType,
+	}
+	permitBatchTypes = map[string][]TypedDataField{
+		"PermitBatch": {
+			{"details", "PermitDetails[]"},
+			{"spender", "address"},
+			{"sigDeadline", "uint256"},
+		},
+		"PermitDetails": permitDetailsType,
+	}
+	permitTransferFromTypes = map[string][]TypedDataField{
+		"PermitTransferFrom": {
+			{"permitted", "TokenPermissions"},
+			{"spender", "address"},
+			{"nonce", "uint256"},
+			{"deadline", "uint256"},
+		},
+		"TokenPermissions": tokenPermissionsType,
+	}
+	permitBatchTransferFromTypes = map[string][]TypedDataField{
+		"PermitBatchTransferFrom": {
+			{"permitted", "TokenPermissions[]"},
+			{"spender", "address"},
+			{"nonce", "uint256"},
+			{"deadline", "uint256"},
+		},
+		"TokenPermissions": tokenPermissionsType,
+	}
+)
+
+/**
+ * Returns the EIP-712 domain of Permit2, which has no version
+ * @param permit2Address The address of Permit2, usually constants.Permit2Address
+ * @param chainId The id of the chain
+ */
+func Permit2Domain(permit2Address common.Address, chainId *big.Int) TypedDataDomain {
+	return TypedDataDomain{Name: "Permit2", ChainId: chainId, VerifyingContract: permit2Address}
+}
+
+func (d PermitDetails) typedValue() map[string]interface{} {
+	return map[string]interface{}{
+		"token":      d.Token,
+		"amount":     d.Amount,
+		"expiration": d.Expiration,
+		"nonce":      d.Nonce,
+	}
+}
+
+func (p TokenPermissions) typedValue() map[string]interface{} {
+	return map[string]interface{}{
+		"token":  p.Token,
+		"amount": p.Amount,
+	}
+}
+
+// PermitSingleTypedData returns the AllowanceTransfer typed data the owner signs to permit the allowance
+func PermitSingleTypedData(permit PermitSingle, permit2Address common.Address, chainId *big.Int) *TypedData {
+	return &TypedData{
+		Types:       permitSingleTypes,
+		PrimaryType: "PermitSingle",
+		Domain:      Permit2Domain(permit2Address, chainId),
+		Message: map[string]interface{}{
+			"details":     permit.Details.typedValue(),
+			"spender":     permit.Spender,
+			"sigDeadline": permit.SigDeadline,
+		},
+	}
+}
+
+// PermitBatchTypedData returns the AllowanceTransfer typed data the owner signs to permit the allowances
+func PermitBatchTypedData(permit PermitBatch, permit2Address common.Address, chainId *big.Int) *TypedData {
+	details := make([]interface{}, len(permit.Details))
+	for i, d := range permit.Details {
+		details[i] = d.typedValue()
+	}
+	return &TypedData{
+		Types:       permitBatchTypes,
+		PrimaryType: "PermitBatch",
+		Domain:      Permit2Domain(permit2Address, chainId),
+		Message: map[string]interface{}{
+			"details":     details,
+			"spender":     permit.Spender,
+			"sigDeadline": permit.SigDeadline,
+		},
+	}
+}
+
+// PermitTransferFromTypedData returns the SignatureTransfer typed data the owner signs to permit the transfer
+func PermitTransferFromTypedData(permit PermitTransferFrom, permit2Address common.Address, chainId *big.Int) *TypedData {
+	return &TypedData{
+		Types:       permitTransferFromTypes,
+		PrimaryType: "PermitTransferFrom",
+		Domain:      Permit2Domain(permit2Address, chainId),
+		Message: map[string]interface{}{
+			"permitted": permit.Permitted.typedValue(),
+			"spender":   permit.Spender,
+			"nonce":     permit.Nonce,
+			"deadline":  permit.Deadline,
+		},
+	}
+}
+
+// PermitBatchTransferFromTypedData returns the SignatureTransfer typed data the owner signs to permit the transfers
+func PermitBatchTransferFromTypedData(permit PermitBatchTransferFrom, permit2Address common.Address, chainId *big.Int) *TypedData {
+	permitted := make([]interface{}, len(permit.Permitted))
+	for i, p := range permit.Permitted {
+		permitted[i] = p.typedValue()
+	}
+	return &TypedData{
+		Types:       permitBatchTransferFromTypes,
+		PrimaryType: "PermitBatchTransferFrom",
+		Domain:      Permit2Domain(permit2Address, chainId),
+		Message: map[string]interface{}{
+			"permitted": permitted,
+			"spender":   permit.Spender,
+			"nonce":     permit.Nonce,
+			"deadline":  permit.Deadline,
+		},
+	}
+}
+
+/**
+ * Signs the permit and returns it ready for the PERMIT2_PERMIT command of the router, see SwapOptions.InputTokenPermit
+ * @param permit The permit, its spender is usually the router
+ * @param permit2Address The address of Permit2
+ * @param chainId The id of the chain
+ * @param signer The owner of the tokens
+ */
+func SignPermitSingle(permit PermitSingle, permit2Address common.Address, chainId *big.Int, signer Signer) (*Permit2Permit, error) {
+	signature, err := signer.SignTypedData(PermitSingleTypedData(permit, permit2Address, chainId))
+	if err != nil {
+		return nil, err
+	}
+	return &Permit2Permit{PermitSingle: permit, Signature: signature}, nil
+}
+
+// BatchPermitOptions is a signed PermitBatch submitted to Permit2 through the position manager
+type BatchPermitOptions struct {
+	Owner       common.Address
+	PermitBatch PermitBatch
+	Signature   []byte
+}
+
+/**
+ * Signs the permit and returns it ready for AddLiquidityOptions.BatchPermit
+ * @param permit The permit, its spender must be the position manager
+ * @param permit2Address The address of Permit2
+ * @param chainId The id of the chain
+ * @param signer The owner of the tokens
+ */
+func SignPermitBatch(permit PermitBatch, permit2Address common.Address, chainId *big.Int, signer Signer) (*BatchPermitOptions, error) {
+	signature, err := signer.SignTypedData(PermitBatchTypedData(permit, permit2Address, chainId))
+	if err != nil {
+		return nil, err
+	}
+	return &BatchPermitOptions{Owner: signer.Address(), PermitBatch: permit, Signature: signature}, nil
+}
+
+/**
+ * Splits an unordered nonce, as used by SignatureTransfer and the position NFT permits, into its position in the
+ * nonce bitmap of the owner
+ * @returns wordPos The index of the bitmap word, the nonce shifted right by 8
+ * @returns bitPos The bit of the nonce in the word, its lowest 8 bits
+ * @returns ErrInvalidNonce when the nonce is negative or does not fit in a uint256
+ */
+func NonceBitmapPosition(nonce *big.Int) (wordPos *big.Int, bitPos uint, err error) {
+	if nonce.Sign() < 0 || nonce.BitLen() > 256 {
+		return nil, 0, ErrInvalidNonce
+	}
+	return new(big.Int).Rsh(nonce, 8), uint(new(big.Int).And(nonce, big.NewInt(0xff)).Uint64()), nil
+}
+
+// NonceFromBitmapPosition returns the unordered nonce at the bit of the bitmap word
+func NonceFromBitmapPosition(wordPos *big.Int, bitPos uint) *big.Int {
+	nonce := new(big.Int).Lsh(wordPos, 8)
+	return nonce.Or(nonce, big.NewInt(int64(bitPos&0xff)))
+}
+
+// IsNonceUsed reports whether the bit of a nonce is set in the bitmap word returned by nonceBitmap or nonces
+func IsNonceUsed(bitmap *big.Int, bitPos uint) bool {
+	return bitmap.Bit(int(bitPos&0xff)) == 1
+}
+
+/**
+ * Returns the lowest unused nonce of a bitmap word
+ * @param wordPos The index of the word
+ * @param bitmap The word returned by nonceBitmap of Permit2 or nonces of the position manager
+ * @returns false when every nonce of the word is used
+ */
+func NextUnusedNonce(wordPos, bitmap *big.Int) (*big.Int, bool) {
+	for bitPos := uint(0); bitPos < 256; bitPos++ {
+		if !IsNonceUsed(bitmap, bitPos) {
+			return NonceFromBitmapPosition(wordPos, bitPos), true
+		}
+	}
+	return nil, false
+}
+
+var (
+	permit2Allowance   = mustNewMethod("allowance", []string{"address", "address", "address"}, []string{"uint160", "uint48", "uint48"})
+	permit2NonceBitmap = mustNewMethod("nonceBitmap", []string{"address", "uint256"}, []string{"uint256"})
+)
+
+// EncodePermit2Allowance encodes the allowance view of Permit2, whose nonce is the next AllowanceTransfer nonce
+func EncodePermit2Allowance(owner, token, spender common.Address) ([]byte, error) {
+	return encodeMethodCall(permit2Allowance, owner, token, spender)
+}
+
+// DecodePermit2AllowanceResult returns the amount, expiration and nonce of the allowance
+func DecodePermit2AllowanceResult(data []byte) (amount, expiration, nonce *big.Int, err error) {
+	r, err := unpackResult(permit2Allowance, data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	amount, expiration, nonce = r.bigInt(0), r.bigInt(1), r.bigInt(2)
+	return amount, expiration, nonce, r.err
+}
+
+// EncodePermit2NonceBitmap encodes the nonceBitmap view of Permit2 for a word of the unordered nonces of the owner
+func EncodePermit2NonceBitmap(owner common.Address, wordPos *big.Int) ([]byte, error) {
+	return encodeMethodCall(permit2NonceBitmap, owner, wordPos)
+}
+
+func DecodePermit2NonceBitmapResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(permit2NonceBitmap, data)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := r.bigInt(0)
+	return bitmap, r.err
+}
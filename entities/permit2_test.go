@@ -0,0 +1,221 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var testSpender = common.HexToAddress("0x66a9893cC07D91D95644AEDD05D03f95e1dBA8Af")
+
+// gethDigest hashes the typed data with the EIP-712 implementation of go-ethereum
+func gethDigest(t *testing.T, typedData apitypes.TypedData) common.Hash {
+	t.Helper()
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return common.BytesToHash(digest)
+}
+
+func expectDigest(t *testing.T, data *TypedData, want common.Hash) {
+	t.Helper()
+	digest, err := data.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != want {
+		t.Errorf("digest %s, want %s", digest, want)
+	}
+}
+
+// expectJSONDigest checks the JSON given to wallets hashes to the digest that is signed
+func expectJSONDigest(t *testing.T, data *TypedData) {
+	t.Helper()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal(encoded, &typedData); err != nil {
+		t.Fatal(err)
+	}
+	expectDigest(t, data, gethDigest(t, typedData))
+}
+
+func testPermitBatch() PermitBatch {
+	return PermitBatch{
+		Details: []PermitDetails{
+			{Token: testToken0.Address, Amount: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1)), Expiration: big.NewInt(1700000000), Nonce: big.NewInt(0)},
+			{Token: testUSDC, Amount: big.NewInt(123456789), Expiration: big.NewInt(1800000000), Nonce: big.NewInt(7)},
+		},
+		Spender:     testSpender,
+		SigDeadline: big.NewInt(1700003600),
+	}
+}
+
+func TestPermitBatchDigestMatchesGeth(t *testing.T) {
+	permit := testPermitBatch()
+	details := make([]interface{}, len(permit.Details))
+	for i, d := range permit.Details {
+		details[i] = map[string]interface{}{
+			"token":      d.Token.Hex(),
+			"amount":     d.Amount.String(),
+			"expiration": d.Expiration.String(),
+			"nonce":      d.Nonce.String(),
+		}
+	}
+	want := gethDigest(t, apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitBatch": {
+				{Name: "details", Type: "PermitDetails[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitBatch",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           math.NewHexOrDecimal256(1),
+			VerifyingContract: constants.Permit2Address.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"details":     details,
+			"spender":     permit.Spender.Hex(),
+			"sigDeadline": permit.SigDeadline.String(),
+		},
+	})
+	expectDigest(t, PermitBatchTypedData(permit, constants.Permit2Address, big.NewInt(1)), want)
+}
+
+func TestTypedDataJSONMatchesGeth(t *testing.T) {
+	chainId := big.NewInt(1)
+	batch := testPermitBatch()
+	tests := map[string]*TypedData{
+		"PermitSingle": PermitSingleTypedData(PermitSingle{Details: batch.Details[1], Spender: batch.Spender, SigDeadline: batch.SigDeadline},
+			constants.Permit2Address, chainId),
+		"PermitBatch": PermitBatchTypedData(batch, constants.Permit2Address, chainId),
+		"PermitTransferFrom": PermitTransferFromTypedData(PermitTransferFrom{
+			Permitted: TokenPermissions{Token: testUSDC, Amount: big.NewInt(1000)},
+			Spender:   testSpender, Nonce: big.NewInt(1 << 40), Deadline: big.NewInt(1700003600),
+		}, constants.Permit2Address, chainId),
+		"PermitBatchTransferFrom": PermitBatchTransferFromTypedData(PermitBatchTransferFrom{
+			Permitted: []TokenPermissions{{Token: testToken0.Address, Amount: big.NewInt(1)}, {Token: testUSDC, Amount: big.NewInt(2)}},
+			Spender:   testSpender, Nonce: big.NewInt(3), Deadline: big.NewInt(1700003600),
+		}, constants.Permit2Address, chainId),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			expectJSONDigest(t, data)
+		})
+	}
+}
+
+func TestSignAndRecoverTypedData(t *testing.T) {
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewPrivateKeySigner(key)
+
+	batch, err := SignPermitBatch(testPermitBatch(), constants.Permit2Address, big.NewInt(1), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := PermitBatchTypedData(testPermitBatch(), constants.Permit2Address, big.NewInt(1))
+	if v := batch.Signature[64]; v != 27 && v != 28 {
+		t.Errorf("signature v %d", v)
+	}
+	recovered, err := RecoverTypedDataSigner(data, batch.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != signer.Address() {
+		t.Errorf("recovered %s, want %s", recovered, signer.Address())
+	}
+	// the recovery id of crypto.Sign is accepted too
+	signature := append([]byte{}, batch.Signature...)
+	signature[64] -= 27
+	if recovered, err := RecoverTypedDataSigner(data, signature); err != nil || recovered != signer.Address() {
+		t.Errorf("recovered %s, %v with a 0/1 recovery id", recovered, err)
+	}
+
+	// the signature is bound to the chain and the message
+	other := PermitBatchTypedData(testPermitBatch(), constants.Permit2Address, big.NewInt(10))
+	if recovered, err := RecoverTypedDataSigner(other, batch.Signature); err == nil && recovered == signer.Address() {
+		t.Error("signature recovered on another chain")
+	}
+
+	if _, err := RecoverTypedDataSigner(data, batch.Signature[:64]); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("short signature returned %v", err)
+	}
+}
+
+func TestNonceBitmapPosition(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		nonce   *big.Int
+		wordPos *big.Int
+		bitPos  uint
+	}{
+		{big.NewInt(0), big.NewInt(0), 0},
+		{big.NewInt(255), big.NewInt(0), 255},
+		{big.NewInt(256), big.NewInt(1), 0},
+		{big.NewInt(0x1234), big.NewInt(0x12), 0x34},
+		// above 64 bits
+		{new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 70), big.NewInt(5)), new(big.Int).Lsh(big.NewInt(1), 62), 5},
+		{maxUint256, new(big.Int).Rsh(maxUint256, 8), 255},
+	}
+	for _, tt := range tests {
+		wordPos, bitPos, err := NonceBitmapPosition(tt.nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wordPos.Cmp(tt.wordPos) != 0 || bitPos != tt.bitPos {
+			t.Errorf("nonce %s: word %s bit %d, want %s %d", tt.nonce, wordPos, bitPos, tt.wordPos, tt.bitPos)
+		}
+		if nonce := NonceFromBitmapPosition(wordPos, bitPos); nonce.Cmp(tt.nonce) != 0 {
+			t.Errorf("nonce %s from its position is %s", tt.nonce, nonce)
+		}
+	}
+
+	for _, nonce := range []*big.Int{big.NewInt(-1), big.NewInt(-256), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if _, _, err := NonceBitmapPosition(nonce); !errors.Is(err, ErrInvalidNonce) {
+			t.Errorf("nonce %s returned %v", nonce, err)
+		}
+	}
+}
+
+func TestNextUnusedNonce(t *testing.T) {
+	wordPos := new(big.Int).Lsh(big.NewInt(1), 100)
+	bitmap := big.NewInt(0b1011)
+	nonce, ok := NextUnusedNonce(wordPos, bitmap)
+	if !ok {
+		t.Fatal("no unused nonce")
+	}
+	if _, bitPos, _ := NonceBitmapPosition(nonce); bitPos != 2 || IsNonceUsed(bitmap, bitPos) {
+		t.Errorf("next unused nonce %s at bit %d", nonce, bitPos)
+	}
+	full := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	if _, ok := NextUnusedNonce(wordPos, full); ok {
+		t.Error("unused nonce in a full word")
+	}
+}
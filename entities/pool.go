@@ -4,23 +4,30 @@ import (
 	"errors"
 	"math/big"
 
-	v3constants "github.com/KyberNetwork/pancake-v3-sdk/constants"
-	v3sdk "github.com/KyberNetwork/pancake-v3-sdk/entities"
-	v3utils "github.com/KyberNetwork/pancake-v3-sdk/utils"
 	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	v4utils "github.com/dangthanhduong01/uniswapv4-sdk/utils"
 	core "github.com/daoleno/uniswap-sdk-core/entities"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 )
 
 var (
 	ErrFeeTooHigh         = errors.New("fee too high")
 	ErrInvalidSqrtRatio96 = errors.New("invalid sqrtRatioX96")
 	ErrHookNotEnabled     = errors.New("hook not enabled")
+	ErrNotDynamicFee      = errors.New("pool does not have a dynamic fee")
+	ErrLPFeeNotSet        = errors.New("LP fee of dynamic fee pool not set")
+
+	ErrTokenNotInvolved          = errors.New("token not involved in pool")
+	ErrPriceLimitAlreadyExceeded = errors.New("price limit already exceeded")
+	ErrPriceLimitOutOfBounds     = errors.New("price limit out of bounds")
+	ErrInvalidFeeForExactOut     = errors.New("invalid fee for exact out")
 )
 
+// LPFeeProvider returns the LP fee currently charged by a dynamic fee pool, e.g. read from
+// StateView.getSlot0 or computed by a fee oracle
+type LPFeeProvider func(pool *Pool) (int64, error)
+
 type StepComputations struct {
 	SqrtPriceStartX96 *big.Int `json:"sqrtPriceStartX96"`
 	TickNext          int      `json:"tickNext"`
@@ -31,29 +38,32 @@ type StepComputations struct {
 	FeeAmount         *big.Int `json:"feeAmount"`
 }
 
-type PoolKey struct {
-	Currency0   common.Address
-	Currency1   common.Address
-	Fee         int64
-	TickSpacing int64
-	Hooks       common.Address
-}
-
 type Pool struct {
-	Currency0        *core.Token
-	Currency1        *core.Token
-	Fee              int64
+	Currency0 core.Currency
+	Currency1 core.Currency
+	// Fee is the fee of the pool key, either a static LP fee or constants.DynamicFeeFlag
+	Fee int64
+	// LPFee is the LP fee charged on swaps. It equals Fee for static fee pools and must be kept
+	// up to date with SetLPFee for dynamic fee pools, unless LPFeeProvider is set
+	LPFee int64
+	// LPFeeProvider, when set, is asked for the current LP fee of a dynamic fee pool on every swap
+	LPFeeProvider    LPFeeProvider
 	TickSpacing      int64
 	SqrtRatioX96     *big.Int
 	Hooks            common.Address
 	Liquidity        *big.Int
 	TickCurrent      int
-	TickDataProvider v3sdk.TickDataProvider
+	TickDataProvider TickDataProvider
 	PoolKey          PoolKey
-	PoolId           []byte
+	PoolId           PoolId
+	// HookSimulator simulates the hook's swap callbacks. Swaps fail with ErrUnsupportedHook
+	// when it is nil and the hook has swap callbacks, see HookSimulators.Configure.
+	HookSimulator HookSimulator
 
 	token0Price *core.Price
 	token1Price *core.Price
+	// lpFeeSet is false until the LP fee of a dynamic fee pool is known
+	lpFeeSet bool
 }
 
 type SwapResult struct {
@@ -79,100 +89,52 @@ type GetInputAmountResult struct {
 	CrossInitTickLoops int
 }
 
-func GetPoolKey(currencyA, currencyB *core.Token,
-	fee int64, tickSpacing int64, hooks common.Address) (*PoolKey, error) {
-	token0 := currencyA
-	token1 := currencyB
-	isSorted, err := currencyA.SortsBefore(currencyB)
+// sortCurrencies orders two currencies the way the PoolManager expects them, native first
+func sortCurrencies(currencyA, currencyB core.Currency) (core.Currency, core.Currency, error) {
+	isSorted, err := v4utils.SortsBefore(currencyA, currencyB)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if !isSorted {
-		token0 = currencyB
-		token1 = currencyA
+		return currencyB, currencyA, nil
 	}
+	return currencyA, currencyB, nil
+}
 
-	currency0Addr := constants.AddressZero
-	currency1Addr := constants.AddressZero
-
-	if !token0.IsNative() {
-		currency0Addr = token0.Wrapped().Address
-	}
-	if !token1.IsNative() {
-		currency1Addr = token1.Wrapped().Address
+func GetPoolKey(currencyA, currencyB core.Currency,
+	fee int64, tickSpacing int64, hooks common.Address) (*PoolKey, error) {
+	currency0, currency1, err := sortCurrencies(currencyA, currencyB)
+	if err != nil {
+		return nil, err
 	}
 
 	return &PoolKey{
-		currency0Addr,
-		currency1Addr,
+		currencyAddress(currency0),
+		currencyAddress(currency1),
 		fee,
 		tickSpacing,
 		hooks,
 	}, nil
 }
 
-func GetPoolId(currencyA *core.Token, currencyB *core.Token,
-	fee int64, tickSpacing int64, hooks common.Address) ([]byte, error) {
-	isSorted, err := currencyA.SortsBefore(currencyB)
+func GetPoolId(currencyA, currencyB core.Currency,
+	fee int64, tickSpacing int64, hooks common.Address) (PoolId, error) {
+	poolKey, err := GetPoolKey(currencyA, currencyB, fee, tickSpacing, hooks)
 	if err != nil {
-		return nil, err
+		return PoolId{}, err
 	}
-	token0 := currencyA
-	token1 := currencyB
-	if !isSorted {
-		token0 = currencyB
-		token1 = currencyA
-	}
-
-	currency0Addr := constants.AddressZero
-	currency1Addr := constants.AddressZero
-
-	if !token0.IsNative() {
-		currency0Addr = token0.Wrapped().Address
-	}
-	if !token1.IsNative() {
-		currency1Addr = token1.Wrapped().Address
-	}
-
-	salt := crypto.Keccak256(abiEncode(currency0Addr, currency1Addr, fee, tickSpacing, hooks))
-
-	return salt, nil
+	return poolKey.ToId()
 }
 
-func abiEncode(addressA, addressB common.Address, fee int64, tickSpacing int64, hooks common.Address) []byte {
-	addressTy, _ := abi.NewType("address", "address", nil)
-	uint256Ty, _ := abi.NewType("uint256", "uint256", nil)
-
-	arguments := abi.Arguments{
-		{Type: addressTy},
-		{Type: addressTy},
-		{Type: uint256Ty},
-		{Type: uint256Ty},
-		{Type: addressTy},
-	}
-
-	bytes, _ := arguments.Pack(
-		addressA,
-		addressB,
-		big.NewInt(fee),
-		tickSpacing,
-		hooks,
-	)
-	return bytes
-}
-
-func NewPool(tokenA, tokenB *core.Token,
+func NewPool(currencyA, currencyB core.Currency,
 	fee int64, tickSpacing int64,
 	hooks common.Address, sqrtRatioX96 *big.Int, liquidity *big.Int,
-	tickCurrent int, ticks v3sdk.TickDataProvider) (*Pool, error) {
-	if fee >= 1000000 {
-		return nil, ErrFeeTooHigh
-	}
-	tickCurrentSqrtRatioX96, err := v3utils.GetSqrtRatioAtTick(tickCurrent)
+	tickCurrent int, ticks TickDataProvider) (*Pool, error) {
+	tickCurrentSqrtRatioX96, err := v4utils.GetSqrtRatioAtTick(tickCurrent)
 	if err != nil {
 		return nil, err
 	}
-	nextTickSqrtRatioX96, err := v3utils.GetSqrtRatioAtTick(tickCurrent + 1)
+	nextTickSqrtRatioX96, err := v4utils.GetSqrtRatioAtTick(tickCurrent + 1)
 	if err != nil {
 		return nil, err
 	}
@@ -181,29 +143,38 @@ func NewPool(tokenA, tokenB *core.Token,
 		return nil, ErrInvalidSqrtRatio96
 	}
 
-	token0 := tokenA
-	token1 := tokenB
-	isSorted, err := tokenA.SortsBefore(tokenB)
+	currency0, currency1, err := sortCurrencies(currencyA, currencyB)
 	if err != nil {
 		return nil, err
 	}
-	if !isSorted {
-		token0 = tokenB
-		token1 = tokenA
-	}
-	poolKey, err := GetPoolKey(token0, token1, fee, tickSpacing, hooks)
+	poolKey, err := GetPoolKey(currency0, currency1, fee, tickSpacing, hooks)
 	if err != nil {
 		return nil, err
 	}
-	poolId, err := GetPoolId(token0, token1, fee, tickSpacing, hooks)
+	if err := poolKey.Validate(); err != nil {
+		return nil, err
+	}
+	poolId, err := poolKey.ToId()
 	if err != nil {
 		return nil, err
 	}
 
+	if ticks == nil {
+		ticks = NoTickDataProvider{}
+	}
+
+	// the LP fee of dynamic fee pools is unknown until SetLPFee
+	lpFee := fee
+	if isDynamicFee(fee) {
+		lpFee = 0
+	}
+
 	return &Pool{
-		Currency0:        token0,
-		Currency1:        token1,
+		Currency0:        currency0,
+		Currency1:        currency1,
 		Fee:              fee,
+		LPFee:            lpFee,
+		TickSpacing:      tickSpacing,
 		SqrtRatioX96:     sqrtRatioX96,
 		Liquidity:        liquidity,
 		TickCurrent:      tickCurrent,
@@ -211,14 +182,69 @@ func NewPool(tokenA, tokenB *core.Token,
 		Hooks:            hooks,
 		PoolKey:          *poolKey,
 		PoolId:           poolId,
+		lpFeeSet:         !isDynamicFee(fee),
 	}, nil
 }
 
-func (p *Pool) Token0() *core.Token {
+// IsDynamicFee returns true if the pool's LP fee is managed by its hook
+func (p *Pool) IsDynamicFee() bool {
+	return isDynamicFee(p.Fee)
+}
+
+// SetLPFee sets the current LP fee of a dynamic fee pool, as reported by StateView.getSlot0
+func (p *Pool) SetLPFee(lpFee int64) error {
+	if !p.IsDynamicFee() {
+		return ErrNotDynamicFee
+	}
+	if lpFee < 0 || lpFee > constants.MaxLPFee {
+		return ErrFeeTooHigh
+	}
+	p.LPFee = lpFee
+	p.lpFeeSet = true
+	return nil
+}
+
+// currentLPFee returns the LP fee a swap would be charged before any hook override, or ErrLPFeeNotSet
+// for a dynamic fee pool with neither a fee set nor an LPFeeProvider
+func (p *Pool) currentLPFee() (int64, error) {
+	if !p.IsDynamicFee() {
+		return p.LPFee, nil
+	}
+	if p.LPFeeProvider == nil {
+		if !p.lpFeeSet {
+			return 0, ErrLPFeeNotSet
+		}
+		return p.LPFee, nil
+	}
+	lpFee, err := p.LPFeeProvider(p)
+	if err != nil {
+		return 0, err
+	}
+	if lpFee < 0 || lpFee > constants.MaxLPFee {
+		return 0, ErrFeeTooHigh
+	}
+	return lpFee, nil
+}
+
+// nextPoolState returns a copy of the pool moved to the state a swap left it in. The pool key,
+// id and the swap simulation settings carry over without being recomputed.
+func (p *Pool) nextPoolState(swapResult *SwapResult) *Pool {
+	pool := *p
+	pool.SqrtRatioX96 = swapResult.SqrtRatioX96
+	pool.Liquidity = swapResult.Liquidity
+	pool.TickCurrent = swapResult.CurrentTick
+	pool.token0Price = nil
+	pool.token1Price = nil
+	return &pool
+}
+
+// Token0 is kept for backwards compatibility with the v2/v3 sdks, it returns currency0
+func (p *Pool) Token0() core.Currency {
 	return p.Currency0
 }
 
-func (p *Pool) Token1() *core.Token {
+// Token1 is kept for backwards compatibility with the v2/v3 sdks, it returns currency1
+func (p *Pool) Token1() core.Currency {
 	return p.Currency1
 }
 
@@ -227,16 +253,21 @@ func (p *Pool) Token1() *core.Token {
  * @param currency The currency to check
  * @returns True if currency is either currency0 or currency1
  */
-func (p *Pool) InvolvesToken(currency *core.Token) bool {
-	return p.Currency0.Equal(currency) || p.Currency1.Equal(currency)
+func (p *Pool) InvolvesCurrency(currency core.Currency) bool {
+	return currency.Equal(p.Currency0) || currency.Equal(p.Currency1)
+}
+
+// InvolvesToken is kept for backwards compatibility with the v2/v3 sdks, see InvolvesCurrency
+func (p *Pool) InvolvesToken(currency core.Currency) bool {
+	return p.InvolvesCurrency(currency)
 }
 
 /**
  * v4-only involvesToken convenience method, used for mixed route ETH <-> WETH connection only
  * @param currency
  */
-func (p *Pool) V4InvolvesToken(currency *core.Token) bool {
-	return p.InvolvesToken(currency) ||
+func (p *Pool) V4InvolvesToken(currency core.Currency) bool {
+	return p.InvolvesCurrency(currency) ||
 		currency.Wrapped().Equal(p.Currency0) ||
 		currency.Wrapped().Equal(p.Currency1) ||
 		currency.Wrapped().Equal(p.Currency0.Wrapped()) ||
@@ -251,7 +282,7 @@ func (p *Pool) Token0Price() *core.Price {
 	if p.token0Price != nil {
 		return p.token0Price
 	}
-	p.token0Price = core.NewPrice(p.Currency0, p.Currency1, v3constants.Q192, new(big.Int).Mul(p.SqrtRatioX96, p.SqrtRatioX96))
+	p.token0Price = core.NewPrice(p.Currency0, p.Currency1, constants.Q192, new(big.Int).Mul(p.SqrtRatioX96, p.SqrtRatioX96))
 	return p.token0Price
 }
 
@@ -259,15 +290,15 @@ func (p *Pool) Token1Price() *core.Price {
 	if p.token1Price != nil {
 		return p.token1Price
 	}
-	p.token1Price = core.NewPrice(p.Currency1, p.Currency0, new(big.Int).Mul(p.SqrtRatioX96, p.SqrtRatioX96), v3constants.Q192)
+	p.token1Price = core.NewPrice(p.Currency1, p.Currency0, new(big.Int).Mul(p.SqrtRatioX96, p.SqrtRatioX96), constants.Q192)
 	return p.token1Price
 }
 
-func (p *Pool) PriceOf(token *core.Token) (*core.Price, error) {
-	if !p.InvolvesToken(token) {
-		return nil, v3sdk.ErrTokenNotInvolved
+func (p *Pool) PriceOf(currency core.Currency) (*core.Price, error) {
+	if !p.InvolvesCurrency(currency) {
+		return nil, ErrTokenNotInvolved
 	}
-	if p.Currency0.Equal(token) {
+	if currency.Equal(p.Currency0) {
 		return p.Token0Price(), nil
 	}
 	return p.Token1Price(), nil
@@ -278,8 +309,8 @@ func (p *Pool) ChainID() uint {
 }
 
 func (p *Pool) GetOutputAmount(inputAmount *core.CurrencyAmount, sqrtPriceLimitX96 *big.Int) (*GetOutputAmountResult, error) {
-	if !(inputAmount.Currency.IsToken() && p.InvolvesToken(inputAmount.Currency.Wrapped())) {
-		return nil, v3sdk.ErrTokenNotInvolved
+	if !p.InvolvesCurrency(inputAmount.Currency) {
+		return nil, ErrTokenNotInvolved
 	}
 	zeroForOne := inputAmount.Currency.Equal(p.Currency0)
 
@@ -287,30 +318,17 @@ func (p *Pool) GetOutputAmount(inputAmount *core.CurrencyAmount, sqrtPriceLimitX
 	if err != nil {
 		return nil, err
 	}
-	var outputToken *core.Token
+	var outputToken core.Currency
 	if zeroForOne {
 		outputToken = p.Currency1
 	} else {
 		outputToken = p.Currency0
 	}
 
-	pool, err := NewPool(
-		p.Currency0,
-		p.Currency1,
-		p.Fee,
-		p.TickSpacing,
-		p.Hooks,
-		swapResult.SqrtRatioX96,
-		swapResult.Liquidity,
-		swapResult.CurrentTick,
-		p.TickDataProvider,
-	)
-	if err != nil {
-		return nil, err
-	}
+	pool := p.nextPoolState(swapResult)
 
 	return &GetOutputAmountResult{
-		ReturnedAmount:     core.FromRawAmount(outputToken, new(big.Int).Mul(swapResult.AmountCalculated, v3constants.NegativeOne)),
+		ReturnedAmount:     core.FromRawAmount(outputToken, new(big.Int).Neg(swapResult.AmountCalculated)),
 		RemainingAmountIn:  core.FromRawAmount(inputAmount.Currency, swapResult.RemainingTargetAmount),
 		NewPoolState:       pool,
 		CrossInitTickLoops: swapResult.CrossInitTickLoops,
@@ -318,36 +336,23 @@ func (p *Pool) GetOutputAmount(inputAmount *core.CurrencyAmount, sqrtPriceLimitX
 }
 
 func (p *Pool) GetInputAmount(outputAmount *core.CurrencyAmount, sqrtPriceLimitX96 *big.Int) (*GetInputAmountResult, error) {
-	if !(outputAmount.Currency.IsToken() && p.InvolvesToken(outputAmount.Currency.Wrapped())) {
-		return nil, v3sdk.ErrTokenNotInvolved
+	if !p.InvolvesCurrency(outputAmount.Currency) {
+		return nil, ErrTokenNotInvolved
 	}
 	zeroForOne := outputAmount.Currency.Equal(p.Currency1)
-	swapResult, err := p.swap(zeroForOne, new(big.Int).Mul(outputAmount.Quotient(), v3constants.NegativeOne), sqrtPriceLimitX96)
+	swapResult, err := p.swap(zeroForOne, new(big.Int).Neg(outputAmount.Quotient()), sqrtPriceLimitX96)
 	if err != nil {
 		return nil, err
 	}
 
-	var inputToken *core.Token
+	var inputToken core.Currency
 	if zeroForOne {
 		inputToken = p.Currency0
 	} else {
 		inputToken = p.Currency1
 	}
 
-	pool, err := NewPool(
-		p.Currency0,
-		p.Currency1,
-		p.Fee,
-		p.TickSpacing,
-		p.Hooks,
-		swapResult.SqrtRatioX96,
-		swapResult.Liquidity,
-		swapResult.CurrentTick,
-		p.TickDataProvider,
-	)
-	if err != nil {
-		return nil, err
-	}
+	pool := p.nextPoolState(swapResult)
 
 	// return core.FromRawAmount(inputToken, swapResult.AmountCalculated), pool, nil
 	return &GetInputAmountResult{
@@ -360,158 +365,228 @@ func (p *Pool) GetInputAmount(outputAmount *core.CurrencyAmount, sqrtPriceLimitX
 
 func (p *Pool) swap(zeroForOne bool, amountSpecified, sqrtPriceLimitX96 *big.Int) (*SwapResult, error) {
 	if !p.hookImpactsSwap() {
-		return p.v3Swap(zeroForOne, amountSpecified, sqrtPriceLimitX96)
-
-	} else {
-		return nil, errors.New("unsupported hook")
+		lpFee, err := p.currentLPFee()
+		if err != nil {
+			return nil, err
+		}
+		return p.computeSwap(zeroForOne, amountSpecified, sqrtPriceLimitX96, lpFee)
 	}
+	sim, err := p.hookSimulator()
+	if err != nil {
+		return nil, err
+	}
+	return p.hookedSwap(sim, zeroForOne, amountSpecified, sqrtPriceLimitX96)
 }
 
-func (p *Pool) v3Swap(zeroForOne bool, amountSpecified, sqrtPriceLimitX96 *big.Int) (*SwapResult, error) {
-	var err error
+// computeSwap runs the swap loop of Pool.swap in v4-core. The amounts use the sdk convention:
+// a positive amountSpecified is an exact input and a negative AmountCalculated is an output.
+func (p *Pool) computeSwap(zeroForOne bool, amountSpecified, sqrtPriceLimitX96 *big.Int, lpFee int64) (*SwapResult, error) {
+	// v4-core signs the amount specified the other way around, negative for an exact input
+	exactInput := amountSpecified.Sign() >= 0
+
+	// a swap fee of 100% makes exact output swaps impossible since the input is entirely consumed by the fee
+	if lpFee >= v4utils.MaxSwapFee && !exactInput {
+		return nil, ErrInvalidFeeForExactOut
+	}
+
+	if amountSpecified.Sign() == 0 {
+		return &SwapResult{
+			AmountCalculated:      big.NewInt(0),
+			SqrtRatioX96:          p.SqrtRatioX96,
+			Liquidity:             p.Liquidity,
+			CurrentTick:           p.TickCurrent,
+			RemainingTargetAmount: big.NewInt(0),
+		}, nil
+	}
+
+	// the prices are converted once here and the amounts once in computeSwapU256, the swap loop does not allocate
+	var sqrtPriceX96, sqrtPriceLimit uint256.Int
+	if sqrtPriceX96.SetFromBig(p.SqrtRatioX96) {
+		return nil, v4utils.ErrUint256Overflow
+	}
 	if sqrtPriceLimitX96 == nil {
 		if zeroForOne {
-			sqrtPriceLimitX96 = new(big.Int).Add(v3utils.MinSqrtRatio, v3constants.One)
+			sqrtPriceLimit.AddUint64(minSqrtRatioU256, 1)
 		} else {
-			sqrtPriceLimitX96 = new(big.Int).Sub(v3utils.MaxSqrtRatio, v3constants.One)
+			sqrtPriceLimit.SubUint64(maxSqrtRatioU256, 1)
 		}
+	} else if sqrtPriceLimitX96.Sign() < 0 || sqrtPriceLimit.SetFromBig(sqrtPriceLimitX96) {
+		return nil, ErrPriceLimitOutOfBounds
 	}
 
 	if zeroForOne {
-		if sqrtPriceLimitX96.Cmp(v3utils.MinSqrtRatio) < 0 {
-			return nil, v3sdk.ErrSqrtPriceLimitX96TooLow
+		if !sqrtPriceLimit.Lt(&sqrtPriceX96) {
+			return nil, ErrPriceLimitAlreadyExceeded
 		}
-		if sqrtPriceLimitX96.Cmp(p.SqrtRatioX96) >= 0 {
-			return nil, v3sdk.ErrSqrtPriceLimitX96TooHigh
+		if !sqrtPriceLimit.Gt(minSqrtRatioU256) {
+			return nil, ErrPriceLimitOutOfBounds
 		}
 	} else {
-		if sqrtPriceLimitX96.Cmp(v3utils.MaxSqrtRatio) > 0 {
-			return nil, v3sdk.ErrSqrtPriceLimitX96TooHigh
+		if !sqrtPriceLimit.Gt(&sqrtPriceX96) {
+			return nil, ErrPriceLimitAlreadyExceeded
 		}
-		if sqrtPriceLimitX96.Cmp(p.SqrtRatioX96) <= 0 {
-			return nil, v3sdk.ErrSqrtPriceLimitX96TooLow
+		if !sqrtPriceLimit.Lt(maxSqrtRatioU256) {
+			return nil, ErrPriceLimitOutOfBounds
 		}
 	}
+	return p.computeSwapU256(zeroForOne, exactInput, amountSpecified, &sqrtPriceX96, &sqrtPriceLimit, lpFee)
+}
 
-	exactInput := amountSpecified.Cmp(v3constants.Zero) >= 0
+var (
+	minSqrtRatioU256 = uint256.MustFromBig(v4utils.MinSqrtRatio)
+	maxSqrtRatioU256 = uint256.MustFromBig(v4utils.MaxSqrtRatio)
+)
+
+// swapStateU256 and stepComputationsU256 hold the swap loop state in fixed width integers so that
+// the loop does not allocate
+type swapStateU256 struct {
+	amountSpecifiedRemaining uint256.Int // v4 sign convention, two's complement
+	amountCalculated         uint256.Int // v4 sign convention, two's complement
+	sqrtPriceX96             uint256.Int
+	tick                     int
+	liquidity                uint256.Int
+}
 
-	state := struct {
-		amountSpecifiedRemaining *big.Int
-		amountCalculated         *big.Int
-		sqrtPriceX96             *big.Int
-		tick                     int
-		liquidity                *big.Int
-	}{
-		amountSpecifiedRemaining: amountSpecified,
-		amountCalculated:         v3constants.Zero,
-		sqrtPriceX96:             p.SqrtRatioX96,
-		tick:                     p.TickCurrent,
-		liquidity:                p.Liquidity,
+type stepComputationsU256 struct {
+	sqrtPriceStartX96 uint256.Int
+	tickNext          int
+	initialized       bool
+	sqrtPriceNextX96  uint256.Int
+	amountIn          uint256.Int
+	amountOut         uint256.Int
+	feeAmount         uint256.Int
+}
+
+func (p *Pool) computeSwapU256(zeroForOne, exactInput bool, amountSpecified *big.Int, sqrtPriceX96, sqrtPriceLimitX96 *uint256.Int, lpFee int64) (*SwapResult, error) {
+	var err error
+	var state swapStateU256
+	var amountSpecified256 uint256.Int
+	if amountSpecified.BitLen() > 255 || state.liquidity.SetFromBig(p.Liquidity) {
+		return nil, v4utils.ErrUint256Overflow
 	}
+	state.sqrtPriceX96.Set(sqrtPriceX96)
+	amountSpecified256.SetFromBig(amountSpecified)
+	state.amountSpecifiedRemaining.Neg(&amountSpecified256)
+	state.tick = p.TickCurrent
 
 	// crossInitTickLoops is the number of loops that cross an initialized tick.
 	// We only count when tick passes an initialized tick, since gas only significant in this case.
 	crossInitTickLoops := 0
 
-	// start swap while loop
-	for state.amountSpecifiedRemaining.Cmp(v3constants.Zero) != 0 && state.sqrtPriceX96.Cmp(sqrtPriceLimitX96) != 0 {
-		var step StepComputations
-		step.SqrtPriceStartX96 = state.sqrtPriceX96
+	var step stepComputationsU256
+	var sqrtPriceNextX96, liquidityNet, amountInPlusFee uint256.Int
+
+	// continue swapping as long as we haven't used the entire input/output and haven't reached the price limit
+	for !state.amountSpecifiedRemaining.IsZero() && !state.sqrtPriceX96.Eq(sqrtPriceLimitX96) {
+		step.sqrtPriceStartX96.Set(&state.sqrtPriceX96)
 
 		// because each iteration of the while loop rounds, we can't optimize this code (relative to the smart contract)
 		// by simply traversing to the next available tick, we instead need to exactly replicate
 		// tickBitmap.nextInitializedTickWithinOneWord
-		// step.TickNext, step.Initialized = p.TickDataProvider.NextInitializedTickWithinOneWord(state.tick, zeroForOne, p.tickSpacing())
-		step.TickNext, step.Initialized, err = p.TickDataProvider.NextInitializedTickIndex(state.tick, zeroForOne)
+		step.tickNext, step.initialized, err = p.TickDataProvider.NextInitializedTickWithinOneWord(state.tick, zeroForOne, int(p.TickSpacing))
 		if err != nil {
 			return nil, err
 		}
 
-		if step.TickNext < v3utils.MinTick {
-			step.TickNext = v3utils.MinTick
-		} else if step.TickNext > v3utils.MaxTick {
-			step.TickNext = v3utils.MaxTick
+		// ensure that we do not overshoot the min/max tick, as the tick bitmap is not aware of these bounds
+		if step.tickNext <= v4utils.MinTick {
+			step.tickNext = v4utils.MinTick
+		} else if step.tickNext >= v4utils.MaxTick {
+			step.tickNext = v4utils.MaxTick
 		}
 
-		step.SqrtPriceNextX96, err = v3utils.GetSqrtRatioAtTick(step.TickNext)
-		if err != nil {
+		// get the price for the next tick
+		if err = v4utils.GetSqrtRatioAtTickU256(step.tickNext, &step.sqrtPriceNextX96); err != nil {
 			return nil, err
 		}
-		var targetValue *big.Int
-		if zeroForOne {
-			if step.SqrtPriceNextX96.Cmp(sqrtPriceLimitX96) < 0 {
-				targetValue = sqrtPriceLimitX96
-			} else {
-				targetValue = step.SqrtPriceNextX96
-			}
-		} else {
-			if step.SqrtPriceNextX96.Cmp(sqrtPriceLimitX96) > 0 {
-				targetValue = sqrtPriceLimitX96
-			} else {
-				targetValue = step.SqrtPriceNextX96
-			}
-		}
 
-		state.sqrtPriceX96, step.AmountIn, step.AmountOut, step.FeeAmount, err = v3utils.ComputeSwapStep(state.sqrtPriceX96, targetValue, state.liquidity, state.amountSpecifiedRemaining, v3constants.FeeAmount(p.Fee))
+		// compute values to swap to the target tick, price limit, or point where input/output amount is exhausted
+		err = v4utils.ComputeSwapStepU256(
+			&state.sqrtPriceX96,
+			v4utils.GetSqrtPriceTargetU256(zeroForOne, &step.sqrtPriceNextX96, sqrtPriceLimitX96),
+			&state.liquidity,
+			&state.amountSpecifiedRemaining,
+			lpFee,
+			&sqrtPriceNextX96, &step.amountIn, &step.amountOut, &step.feeAmount,
+		)
 		if err != nil {
 			return nil, err
 		}
+		state.sqrtPriceX96.Set(&sqrtPriceNextX96)
 
+		amountInPlusFee.Add(&step.amountIn, &step.feeAmount)
 		if exactInput {
-			state.amountSpecifiedRemaining = new(big.Int).Sub(state.amountSpecifiedRemaining, new(big.Int).Add(step.AmountIn, step.FeeAmount))
-			state.amountCalculated = new(big.Int).Sub(state.amountCalculated, step.AmountOut)
+			state.amountSpecifiedRemaining.Add(&state.amountSpecifiedRemaining, &amountInPlusFee)
+			state.amountCalculated.Add(&state.amountCalculated, &step.amountOut)
 		} else {
-			state.amountSpecifiedRemaining = new(big.Int).Add(state.amountSpecifiedRemaining, step.AmountOut)
-			state.amountCalculated = new(big.Int).Add(state.amountCalculated, new(big.Int).Add(step.AmountIn, step.FeeAmount))
+			state.amountSpecifiedRemaining.Sub(&state.amountSpecifiedRemaining, &step.amountOut)
+			state.amountCalculated.Sub(&state.amountCalculated, &amountInPlusFee)
 		}
 
-		// TODO
-		if state.sqrtPriceX96.Cmp(step.SqrtPriceNextX96) == 0 {
+		// shift tick if we reached the next price, and preemptively decrement for zeroForOne swaps to tickNext - 1.
+		if state.sqrtPriceX96.Eq(&step.sqrtPriceNextX96) {
 			// if the tick is initialized, run the tick transition
-			if step.Initialized {
-				tick, err := p.TickDataProvider.GetTick(step.TickNext)
+			if step.initialized {
+				tick, err := p.TickDataProvider.GetTick(step.tickNext)
 				if err != nil {
 					return nil, err
 				}
-				liquidityNet := tick.LiquidityNet
+				liquidityNet.SetFromBig(tick.LiquidityNet)
 				// if we're moving leftward, we interpret liquidityNet as the opposite sign
 				// safe because liquidityNet cannot be type(int128).min
 				if zeroForOne {
-					liquidityNet = new(big.Int).Mul(liquidityNet, v3constants.NegativeOne)
+					liquidityNet.Neg(&liquidityNet)
+				}
+				if err = v4utils.AddDeltaU256(&state.liquidity, &liquidityNet, &state.liquidity); err != nil {
+					return nil, err
 				}
-				state.liquidity = v3utils.AddDelta(state.liquidity, liquidityNet)
 
 				crossInitTickLoops++
 			}
 			if zeroForOne {
-				state.tick = step.TickNext - 1
+				state.tick = step.tickNext - 1
 			} else {
-				state.tick = step.TickNext
+				state.tick = step.tickNext
 			}
-		} else if state.sqrtPriceX96.Cmp(step.SqrtPriceStartX96) != 0 {
+		} else if !state.sqrtPriceX96.Eq(&step.sqrtPriceStartX96) {
 			// recompute unless we're on a lower tick boundary (i.e. already transitioned ticks), and haven't moved
-			state.tick, err = v3utils.GetTickAtSqrtRatio(state.sqrtPriceX96)
+			state.tick, err = v4utils.GetTickAtSqrtRatioU256(&state.sqrtPriceX96)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	// back to the sdk sign convention
+	state.amountCalculated.Neg(&state.amountCalculated)
+	state.amountSpecifiedRemaining.Neg(&state.amountSpecifiedRemaining)
+
+	// swaps that cross no initialized tick keep the liquidity of the pool
+	liquidity := p.Liquidity
+	if crossInitTickLoops > 0 {
+		liquidity = state.liquidity.ToBig()
+	}
 	return &SwapResult{
-		AmountCalculated:      state.amountCalculated,
-		SqrtRatioX96:          state.sqrtPriceX96,
-		Liquidity:             state.liquidity,
+		AmountCalculated:      toBigSigned(&state.amountCalculated),
+		SqrtRatioX96:          state.sqrtPriceX96.ToBig(),
+		Liquidity:             liquidity,
 		CurrentTick:           state.tick,
-		RemainingTargetAmount: state.amountSpecifiedRemaining,
+		RemainingTargetAmount: toBigSigned(&state.amountSpecifiedRemaining),
 		CrossInitTickLoops:    crossInitTickLoops,
 	}, nil
 }
 
-func (p *Pool) hookImpactsSwap() bool {
-	var hook v4utils.Hook
-	permission, err := (&hook).HasSwapPermissions(p.Hooks.Hex())
-	if err != nil {
-		return false
+// toBigSigned converts an int256 in two's complement representation into a big.Int
+func toBigSigned(x *uint256.Int) *big.Int {
+	if x.Sign() >= 0 {
+		return x.ToBig()
 	}
-	return permission
+	var abs uint256.Int
+	abs.Neg(x)
+	y := abs.ToBig()
+	return y.Neg(y)
+}
+
+func (p *Pool) hookImpactsSwap() bool {
+	return p.hasHookPermission(v4utils.BeforeSwap) || p.hasHookPermission(v4utils.AfterSwap)
 }
@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	v4utils "github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrCurrenciesOutOfOrderOrEqual = errors.New("currencies out of order or equal")
+	ErrTickSpacingTooLarge         = errors.New("tick spacing too large")
+	ErrTickSpacingTooSmall         = errors.New("tick spacing too small")
+	ErrFeeOverflowsUint24          = errors.New("fee does not fit in uint24")
+	ErrTickSpacingOverflowsInt24   = errors.New("tick spacing does not fit in int24")
+)
+
+// PoolKey identifies a pool in the PoolManager
+type PoolKey struct {
+	Currency0   common.Address
+	Currency1   common.Address
+	Fee         int64
+	TickSpacing int64
+	Hooks       common.Address
+}
+
+// PoolId is the id of a pool in the PoolManager, the keccak256 hash of its PoolKey
+type PoolId [32]byte
+
+// IsDynamicFee returns true if the key fee is the dynamic fee flag
+func (k PoolKey) IsDynamicFee() bool {
+	return isDynamicFee(k.Fee)
+}
+
+func isDynamicFee(fee int64) bool {
+	return fee == constants.DynamicFeeFlag
+}
+
+/**
+ * Returns the id of the pool the way PoolIdLibrary.toId computes it, i.e. the keccak256 hash of the
+ * five 32 byte words of the key in memory, with fee as a uint24 and tickSpacing as a sign extended int24
+ */
+func (k PoolKey) ToId() (PoolId, error) {
+	if k.Fee < 0 || k.Fee >= 1<<24 {
+		return PoolId{}, ErrFeeOverflowsUint24
+	}
+	if k.TickSpacing < -(1<<23) || k.TickSpacing >= 1<<23 {
+		return PoolId{}, ErrTickSpacingOverflowsInt24
+	}
+
+	var encoded [5 * 32]byte
+	copy(encoded[12:32], k.Currency0[:])
+	copy(encoded[44:64], k.Currency1[:])
+	putInt64Word(encoded[64:96], k.Fee)
+	putInt64Word(encoded[96:128], k.TickSpacing)
+	copy(encoded[140:160], k.Hooks[:])
+
+	return PoolId(crypto.Keccak256Hash(encoded[:])), nil
+}
+
+// putInt64Word writes v into the 32 byte word as a sign extended big endian integer
+func putInt64Word(word []byte, v int64) {
+	var pad byte
+	if v < 0 {
+		pad = 0xff
+	}
+	for i := 0; i < 24; i++ {
+		word[i] = pad
+	}
+	for i := 0; i < 8; i++ {
+		word[31-i] = byte(v >> (8 * i))
+	}
+}
+
+/**
+ * Checks the key against the rules the PoolManager enforces when initializing a pool:
+ * currency0 must sort strictly before currency1, the fee must be the dynamic fee flag or at most
+ * 100% and the tick spacing must be between MinTickSpacing and MaxTickSpacing
+ */
+func (k PoolKey) Validate() error {
+	if bytes.Compare(k.Currency0[:], k.Currency1[:]) >= 0 {
+		return ErrCurrenciesOutOfOrderOrEqual
+	}
+	if k.Fee < 0 || (k.Fee > constants.MaxLPFee && !k.IsDynamicFee()) {
+		return ErrFeeTooHigh
+	}
+	if k.TickSpacing > v4utils.MaxTickSpacing {
+		return ErrTickSpacingTooLarge
+	}
+	if k.TickSpacing < v4utils.MinTickSpacing {
+		return ErrTickSpacingTooSmall
+	}
+	return nil
+}
+
+// Hex returns the 0x prefixed hex encoding of the id
+func (id PoolId) Hex() string {
+	return hexutil.Encode(id[:])
+}
+
+func (id PoolId) String() string {
+	return id.Hex()
+}
+
+// MarshalText encodes the id as a 0x prefixed hex string, it is used for JSON values and map keys
+func (id PoolId) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(id[:]).MarshalText()
+}
+
+// UnmarshalText decodes a 0x prefixed hex string of 32 bytes
+func (id *PoolId) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("PoolId", input, id[:])
+}
@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	testUSDC      = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	testUSDCToken = core.NewToken(1, testUSDC, 6, "USDC", "USD Coin")
+)
+
+// abiEncodedId hashes the key abi encoded as (address,address,uint24,int24,address), like keccak256(abi.encode(key))
+func abiEncodedId(t *testing.T, key PoolKey) PoolId {
+	t.Helper()
+	newType := func(name string) abi.Type {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return typ
+	}
+	arguments := abi.Arguments{
+		{Type: newType("address")}, {Type: newType("address")}, {Type: newType("uint24")}, {Type: newType("int24")}, {Type: newType("address")},
+	}
+	encoded, err := arguments.Pack(key.Currency0, key.Currency1, big.NewInt(key.Fee), big.NewInt(key.TickSpacing), key.Hooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return PoolId(crypto.Keccak256Hash(encoded))
+}
+
+func TestPoolKeyToIdMainnet(t *testing.T) {
+	// the native ETH/USDC 0.05% pool of the mainnet PoolManager
+	key := PoolKey{Currency0: common.Address{}, Currency1: testUSDC, Fee: 500, TickSpacing: 10}
+	id, err := key.ToId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Hex() != "0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27" {
+		t.Errorf("pool id %s", id)
+	}
+
+	poolId, err := GetPoolId(core.EtherOnChain(1), testUSDCToken, 500, 10, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if poolId != id {
+		t.Errorf("GetPoolId %s, want %s", poolId, id)
+	}
+}
+
+func TestPoolKeyToIdAbiEncoding(t *testing.T) {
+	hooks := common.HexToAddress("0x0000000000000000000000000000000000002080")
+	tests := []struct {
+		name string
+		key  PoolKey
+	}{
+		{"dynamic fee", PoolKey{Currency0: testToken0.Address, Currency1: testToken1.Address, Fee: constants.DynamicFeeFlag, TickSpacing: 60, Hooks: hooks}},
+		{"negative tick spacing", PoolKey{Currency0: testToken0.Address, Currency1: testToken1.Address, Fee: 3000, TickSpacing: -60}},
+		{"int24 bounds", PoolKey{Currency0: testToken0.Address, Currency1: testUSDC, Fee: 1<<24 - 1, TickSpacing: -(1 << 23)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := tt.key.ToId()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := abiEncodedId(t, tt.key); id != want {
+				t.Errorf("pool id %s, want %s", id, want)
+			}
+		})
+	}
+
+	dynamic, err := tests[0].key.ToId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	static := tests[0].key
+	static.Fee = 3000
+	if id, _ := static.ToId(); id == dynamic {
+		t.Error("dynamic and static fee keys have the same id")
+	}
+}
+
+func TestPoolKeyToIdOverflow(t *testing.T) {
+	for _, key := range []PoolKey{{Fee: -1}, {Fee: 1 << 24}} {
+		if _, err := key.ToId(); !errors.Is(err, ErrFeeOverflowsUint24) {
+			t.Errorf("fee %d returned %v", key.Fee, err)
+		}
+	}
+	for _, key := range []PoolKey{{TickSpacing: 1 << 23}, {TickSpacing: -(1 << 23) - 1}} {
+		if _, err := key.ToId(); !errors.Is(err, ErrTickSpacingOverflowsInt24) {
+			t.Errorf("tick spacing %d returned %v", key.TickSpacing, err)
+		}
+	}
+}
+
+func TestPoolKeyValidate(t *testing.T) {
+	valid := PoolKey{Currency0: common.Address{}, Currency1: testUSDC, Fee: 500, TickSpacing: 10}
+	tests := []struct {
+		name   string
+		modify func(*PoolKey)
+		err    error
+	}{
+		{"valid", func(*PoolKey) {}, nil},
+		{"dynamic fee", func(k *PoolKey) { k.Fee = constants.DynamicFeeFlag }, nil},
+		{"currencies out of order", func(k *PoolKey) { k.Currency0, k.Currency1 = k.Currency1, k.Currency0 }, ErrCurrenciesOutOfOrderOrEqual},
+		{"equal currencies", func(k *PoolKey) { k.Currency0 = k.Currency1 }, ErrCurrenciesOutOfOrderOrEqual},
+		{"fee above 100%", func(k *PoolKey) { k.Fee = constants.MaxLPFee + 1 }, ErrFeeTooHigh},
+		{"negative tick spacing", func(k *PoolKey) { k.TickSpacing = -10 }, ErrTickSpacingTooSmall},
+		{"zero tick spacing", func(k *PoolKey) { k.TickSpacing = 0 }, ErrTickSpacingTooSmall},
+		{"tick spacing above int16", func(k *PoolKey) { k.TickSpacing = 32768 }, ErrTickSpacingTooLarge},
+	}
+	for _, tt := range tests {
+		key := valid
+		tt.modify(&key)
+		if err := key.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: returned %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestPoolIdText(t *testing.T) {
+	id, err := PoolKey{Currency1: testUSDC, Fee: 500, TickSpacing: 10}.ToId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded PoolId
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != id || string(text) != id.Hex() {
+		t.Errorf("decoded %s from %s", decoded, text)
+	}
+	if err := decoded.UnmarshalText([]byte("0x1234")); err == nil {
+		t.Error("short id decoded")
+	}
+}
@@ -0,0 +1,337 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrUnknownEvent    = errors.New("unknown event")
+	ErrInvalidLog      = errors.New("invalid log")
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrPoolIdMismatch  = errors.New("pool id does not match the event")
+)
+
+var (
+	initializeEvent         = mustNewEvent("Initialize", "bytes32 indexed id", "address indexed currency0", "address indexed currency1", "uint24 fee", "int24 tickSpacing", "address hooks", "uint160 sqrtPriceX96", "int24 tick")
+	modifyLiquidityEvent    = mustNewEvent("ModifyLiquidity", "bytes32 indexed id", "address indexed sender", "int24 tickLower", "int24 tickUpper", "int256 liquidityDelta", "bytes32 salt")
+	swapEvent               = mustNewEvent("Swap", "bytes32 indexed id", "address indexed sender", "int128 amount0", "int128 amount1", "uint160 sqrtPriceX96", "uint128 liquidity", "int24 tick", "uint24 fee")
+	donateEvent             = mustNewEvent("Donate", "bytes32 indexed id", "address indexed sender", "uint256 amount0", "uint256 amount1")
+	protocolFeeUpdatedEvent = mustNewEvent("ProtocolFeeUpdated", "bytes32 indexed id", "uint24 protocolFee")
+	// ERC6909 events of the PoolManager's claim tokens, the id of a claim token is the address of its currency
+	transferEvent    = mustNewEvent("Transfer", "address caller", "address indexed from", "address indexed to", "uint256 indexed id", "uint256 amount")
+	operatorSetEvent = mustNewEvent("OperatorSet", "address indexed owner", "address indexed operator", "bool approved")
+)
+
+// The topics of the PoolManager events, e.g. to filter logs
+var (
+	InitializeTopic         = initializeEvent.ID
+	ModifyLiquidityTopic    = modifyLiquidityEvent.ID
+	SwapTopic               = swapEvent.ID
+	DonateTopic             = donateEvent.ID
+	ProtocolFeeUpdatedTopic = protocolFeeUpdatedEvent.ID
+	TransferTopic           = transferEvent.ID
+	OperatorSetTopic        = operatorSetEvent.ID
+)
+
+/**
+ * EventLog is a log emitted by a contract. It has the fields of go-ethereum's types.Log in the same order, so a
+ * types.Log converts directly with EventLog(log) without this package depending on core/types.
+ */
+type EventLog struct {
+	Address        common.Address
+	Topics         []common.Hash
+	Data           []byte
+	BlockNumber    uint64
+	TxHash         common.Hash
+	TxIndex        uint
+	BlockHash      common.Hash
+	BlockTimestamp uint64
+	Index          uint
+	Removed        bool
+}
+
+type InitializeEvent struct {
+	Id           PoolId
+	Currency0    common.Address
+	Currency1    common.Address
+	Fee          int64
+	TickSpacing  int64
+	Hooks        common.Address
+	SqrtPriceX96 *big.Int
+	Tick         int
+	Raw          EventLog
+}
+
+type ModifyLiquidityEvent struct {
+	Id        PoolId
+	Sender    common.Address
+	TickLower int
+	TickUpper int
+	// LiquidityDelta is negative when liquidity is removed
+	LiquidityDelta *big.Int
+	Salt           common.Hash
+	Raw            EventLog
+}
+
+type SwapEvent struct {
+	Id     PoolId
+	Sender common.Address
+	// The balance deltas of the swapper, negative for the amount paid into the pool and positive for the amount out
+	Amount0      *big.Int
+	Amount1      *big.Int
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         int
+	// Fee is the fee charged on the swap, the LP fee combined with the protocol fee
+	Fee int64
+	Raw EventLog
+}
+
+type DonateEvent struct {
+	Id      PoolId
+	Sender  common.Address
+	Amount0 *big.Int
+	Amount1 *big.Int
+	Raw     EventLog
+}
+
+type ProtocolFeeUpdatedEvent struct {
+	Id          PoolId
+	ProtocolFee int64
+	Raw         EventLog
+}
+
+// TransferEvent is an ERC6909 transfer of PoolManager claim tokens, a mint when From is zero and a burn when To is zero
+type TransferEvent struct {
+	Caller common.Address
+	From   common.Address
+	To     common.Address
+	Id     *big.Int
+	Amount *big.Int
+	Raw    EventLog
+}
+
+// Currency returns the address of the currency of the claim token
+func (e *TransferEvent) Currency() common.Address {
+	return common.BigToAddress(e.Id)
+}
+
+type OperatorSetEvent struct {
+	Owner    common.Address
+	Operator common.Address
+	Approved bool
+	Raw      EventLog
+}
+
+// unpackLog decodes the topics and data of the log into the values of the event inputs in declaration order
+func unpackLog(event abi.Event, log EventLog) (*paramReader, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != event.ID {
+		return nil, fmt.Errorf("%w: not a %s log", ErrInvalidLog, event.Name)
+	}
+	data, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", event.Name, err)
+	}
+
+	values := make([]interface{}, 0, len(event.Inputs))
+	topics := log.Topics[1:]
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			values = append(values, data[0])
+			data = data[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("%w: %s is missing topic %s", ErrInvalidLog, event.Name, input.Name)
+		}
+		// the topic of an indexed value type is its abi encoding
+		value, err := abi.Arguments{{Type: input.Type}}.Unpack(topics[0][:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", event.Name, err)
+		}
+		values = append(values, value[0])
+		topics = topics[1:]
+	}
+	return &paramReader{values: values}, nil
+}
+
+func DecodeInitializeEvent(log EventLog) (*InitializeEvent, error) {
+	r, err := unpackLog(initializeEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &InitializeEvent{
+		Id:           PoolId(r.hash(0)),
+		Currency0:    r.address(1),
+		Currency1:    r.address(2),
+		Fee:          r.bigInt(3).Int64(),
+		TickSpacing:  r.bigInt(4).Int64(),
+		Hooks:        r.address(5),
+		SqrtPriceX96: r.bigInt(6),
+		Tick:         r.int(7),
+		Raw:          log,
+	}
+	return event, r.err
+}
+
+// PoolKey returns the key of the initialized pool
+func (e *InitializeEvent) PoolKey() PoolKey {
+	return PoolKey{
+		Currency0:   e.Currency0,
+		Currency1:   e.Currency1,
+		Fee:         e.Fee,
+		TickSpacing: e.TickSpacing,
+		Hooks:       e.Hooks,
+	}
+}
+
+/**
+ * Constructs the pool in the state it was initialized in, without liquidity
+ * @param registry Resolves the currencies of the pool
+ * @param ticks The tick data provider of the pool, optional
+ */
+func (e *InitializeEvent) Pool(registry TokenRegistry, ticks TickDataProvider) (*Pool, error) {
+	currency0, ok := registry.Currency(e.Currency0)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, e.Currency0.Hex())
+	}
+	currency1, ok := registry.Currency(e.Currency1)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, e.Currency1.Hex())
+	}
+	pool, err := NewPool(currency0, currency1, e.Fee, e.TickSpacing, e.Hooks, e.SqrtPriceX96, big.NewInt(0), e.Tick, ticks)
+	if err != nil {
+		return nil, err
+	}
+	if pool.PoolId != e.Id {
+		return nil, ErrPoolIdMismatch
+	}
+	return pool, nil
+}
+
+func DecodeModifyLiquidityEvent(log EventLog) (*ModifyLiquidityEvent, error) {
+	r, err := unpackLog(modifyLiquidityEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &ModifyLiquidityEvent{
+		Id:             PoolId(r.hash(0)),
+		Sender:         r.address(1),
+		TickLower:      r.int(2),
+		TickUpper:      r.int(3),
+		LiquidityDelta: r.bigInt(4),
+		Salt:           r.hash(5),
+		Raw:            log,
+	}
+	return event, r.err
+}
+
+func DecodeSwapEvent(log EventLog) (*SwapEvent, error) {
+	r, err := unpackLog(swapEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &SwapEvent{
+		Id:           PoolId(r.hash(0)),
+		Sender:       r.address(1),
+		Amount0:      r.bigInt(2),
+		Amount1:      r.bigInt(3),
+		SqrtPriceX96: r.bigInt(4),
+		Liquidity:    r.bigInt(5),
+		Tick:         r.int(6),
+		Fee:          r.bigInt(7).Int64(),
+		Raw:          log,
+	}
+	return event, r.err
+}
+
+func DecodeDonateEvent(log EventLog) (*DonateEvent, error) {
+	r, err := unpackLog(donateEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &DonateEvent{
+		Id:      PoolId(r.hash(0)),
+		Sender:  r.address(1),
+		Amount0: r.bigInt(2),
+		Amount1: r.bigInt(3),
+		Raw:     log,
+	}
+	return event, r.err
+}
+
+func DecodeProtocolFeeUpdatedEvent(log EventLog) (*ProtocolFeeUpdatedEvent, error) {
+	r, err := unpackLog(protocolFeeUpdatedEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &ProtocolFeeUpdatedEvent{
+		Id:          PoolId(r.hash(0)),
+		ProtocolFee: r.bigInt(1).Int64(),
+		Raw:         log,
+	}
+	return event, r.err
+}
+
+func DecodeTransferEvent(log EventLog) (*TransferEvent, error) {
+	r, err := unpackLog(transferEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &TransferEvent{
+		Caller: r.address(0),
+		From:   r.address(1),
+		To:     r.address(2),
+		Id:     r.bigInt(3),
+		Amount: r.bigInt(4),
+		Raw:    log,
+	}
+	return event, r.err
+}
+
+func DecodeOperatorSetEvent(log EventLog) (*OperatorSetEvent, error) {
+	r, err := unpackLog(operatorSetEvent, log)
+	if err != nil {
+		return nil, err
+	}
+	event := &OperatorSetEvent{
+		Owner:    r.address(0),
+		Operator: r.address(1),
+		Approved: r.bool(2),
+		Raw:      log,
+	}
+	return event, r.err
+}
+
+/**
+ * Decodes a PoolManager log into the event of its topic, one of *InitializeEvent, *ModifyLiquidityEvent, *SwapEvent,
+ * *DonateEvent, *ProtocolFeeUpdatedEvent, *TransferEvent or *OperatorSetEvent
+ * @param log The log, logs of other events fail with ErrUnknownEvent
+ */
+func DecodePoolManagerLog(log EventLog) (interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("%w: no topics", ErrInvalidLog)
+	}
+	switch log.Topics[0] {
+	case InitializeTopic:
+		return DecodeInitializeEvent(log)
+	case ModifyLiquidityTopic:
+		return DecodeModifyLiquidityEvent(log)
+	case SwapTopic:
+		return DecodeSwapEvent(log)
+	case DonateTopic:
+		return DecodeDonateEvent(log)
+	case ProtocolFeeUpdatedTopic:
+		return DecodeProtocolFeeUpdatedEvent(log)
+	case TransferTopic:
+		return DecodeTransferEvent(log)
+	case OperatorSetTopic:
+		return DecodeOperatorSetEvent(log)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, log.Topics[0].Hex())
+}
@@ -0,0 +1,185 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The logs are laid out as the PoolManager emits them for the ETH/USDC 0.05% pool of mainnet
+var (
+	eventTestPoolId = "0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"
+	eventTestUSDC   = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	// the Universal Router and the PositionManager of mainnet
+	eventTestRouter          = common.HexToAddress("0x66a9893cC07D91D95644AEDD05D03f95e1dBA8Af")
+	eventTestPositionManager = common.HexToAddress("0xbD216513d74C8cf14cf4747E6AaA6420FF64ee9e")
+)
+
+func eventTestLog(t *testing.T, topics []string, data ...string) EventLog {
+	t.Helper()
+	log := EventLog{Data: abiWords(t, data...), BlockNumber: 21688329, Index: 3}
+	for _, topic := range topics {
+		log.Topics = append(log.Topics, common.HexToHash(topic))
+	}
+	return log
+}
+
+func initializeTestLog(t *testing.T) EventLog {
+	return eventTestLog(t, []string{
+		"0xdd466e674ea557f56295e2d0218a125ea4b4f0f6f3307b95f85e6110838d6438",
+		eventTestPoolId,
+		"0x0000000000000000000000000000000000000000000000000000000000000000",
+		"0x000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+	},
+		"00000000000000000000000000000000000000000000000000000000000001f4",
+		"000000000000000000000000000000000000000000000000000000000000000a",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000439673cbc7afe44e59d00",
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd0e18",
+	)
+}
+
+func TestPoolManagerTopics(t *testing.T) {
+	tests := []struct {
+		name  string
+		topic common.Hash
+		want  string
+	}{
+		{"Initialize", InitializeTopic, "0xdd466e674ea557f56295e2d0218a125ea4b4f0f6f3307b95f85e6110838d6438"},
+		{"ModifyLiquidity", ModifyLiquidityTopic, "0xf208f4912782fd25c7f114ca3723a2d5dd6f3bcc3ac8db5af63baa85f711d5ec"},
+		{"Swap", SwapTopic, "0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f"},
+		{"Donate", DonateTopic, "0x29ef05caaff9404b7cb6d1c0e9bbae9eaa7ab2541feba1a9c4248594c08156cb"},
+	}
+	for _, tt := range tests {
+		if tt.topic.Hex() != tt.want {
+			t.Errorf("%s topic %s, want %s", tt.name, tt.topic.Hex(), tt.want)
+		}
+	}
+}
+
+func TestDecodeInitializeEvent(t *testing.T) {
+	log := initializeTestLog(t)
+	event, err := DecodeInitializeEvent(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqrtPriceX96, _ := new(big.Int).SetString("5106782559129848953281792", 10)
+	if event.Id.Hex() != eventTestPoolId || event.Currency0 != (common.Address{}) || event.Currency1 != eventTestUSDC ||
+		event.Fee != 500 || event.TickSpacing != 10 || event.Hooks != (common.Address{}) ||
+		event.SqrtPriceX96.Cmp(sqrtPriceX96) != 0 || event.Tick != -193000 || event.Raw.BlockNumber != 21688329 {
+		t.Errorf("decoded as %+v", event)
+	}
+
+	// the pool of the event has the id of the log
+	pool, err := event.Pool(NewCurrencyMap(core.EtherOnChain(1), core.NewToken(1, eventTestUSDC, 6, "USDC", "USD Coin")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.PoolId.Hex() != eventTestPoolId || pool.TickCurrent != -193000 || pool.Liquidity.Sign() != 0 {
+		t.Errorf("pool %s at tick %d with liquidity %s", pool.PoolId, pool.TickCurrent, pool.Liquidity)
+	}
+	if _, err := event.Pool(NewCurrencyMap(core.EtherOnChain(1)), nil); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("pool without USDC in the registry returned %v", err)
+	}
+	event.Fee = 3000
+	if _, err := event.Pool(NewCurrencyMap(core.EtherOnChain(1), core.NewToken(1, eventTestUSDC, 6, "USDC", "USD Coin")), nil); !errors.Is(err, ErrPoolIdMismatch) {
+		t.Errorf("pool of another fee returned %v", err)
+	}
+}
+
+func TestDecodeSwapEvent(t *testing.T) {
+	// 1 ETH sold for 3600 USDC
+	log := eventTestLog(t, []string{
+		"0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f",
+		eventTestPoolId,
+		"0x00000000000000000000000066a9893cc07d91d95644aedd05d03f95e1dba8af",
+	},
+		"fffffffffffffffffffffffffffffffffffffffffffffffff21f494c589c0000",
+		"00000000000000000000000000000000000000000000000000000000d693a400",
+		"000000000000000000000000000000000000000000043402908d3a20f48babe4",
+		"0000000000000000000000000000000000000000000000001bc16d674ec80000",
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd0db4",
+		"00000000000000000000000000000000000000000000000000000000000001f4",
+	)
+	event, err := DecodeSwapEvent(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount0, _ := new(big.Int).SetString("-1000000000000000000", 10)
+	sqrtPriceX96, _ := new(big.Int).SetString("5081313645101312063024100", 10)
+	liquidity, _ := new(big.Int).SetString("2000000000000000000", 10)
+	if event.Id.Hex() != eventTestPoolId || event.Sender != eventTestRouter ||
+		event.Amount0.Cmp(amount0) != 0 || event.Amount1.Int64() != 3600000000 ||
+		event.SqrtPriceX96.Cmp(sqrtPriceX96) != 0 || event.Liquidity.Cmp(liquidity) != 0 ||
+		event.Tick != -193100 || event.Fee != 500 {
+		t.Errorf("decoded as %+v", event)
+	}
+}
+
+func TestDecodeModifyLiquidityEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		liquidityDelta string
+		want           int64
+	}{
+		{"mint", "00000000000000000000000000000000000000000000000000007048860ddf79", 123456789012345},
+		{"burn", "ffffffffffffffffffffffffffffffffffffffffffffffffffff8fb779f22087", -123456789012345},
+	}
+	for _, tt := range tests {
+		// the salt of positions of the PositionManager is their token id
+		log := eventTestLog(t, []string{
+			"0xf208f4912782fd25c7f114ca3723a2d5dd6f3bcc3ac8db5af63baa85f711d5ec",
+			eventTestPoolId,
+			"0x000000000000000000000000bd216513d74c8cf14cf4747e6aaa6420ff64ee9e",
+		},
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd0648",
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd15e8",
+			tt.liquidityDelta,
+			"0000000000000000000000000000000000000000000000000000000000002b0c",
+		)
+		event, err := DecodeModifyLiquidityEvent(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Id.Hex() != eventTestPoolId || event.Sender != eventTestPositionManager ||
+			event.TickLower != -195000 || event.TickUpper != -191000 ||
+			event.LiquidityDelta.Int64() != tt.want || event.Salt != common.BigToHash(big.NewInt(11020)) {
+			t.Errorf("%s: decoded as %+v", tt.name, event)
+		}
+	}
+}
+
+func TestDecodePoolManagerLog(t *testing.T) {
+	log := initializeTestLog(t)
+	if event, err := DecodePoolManagerLog(log); err != nil {
+		t.Fatal(err)
+	} else if _, ok := event.(*InitializeEvent); !ok {
+		t.Errorf("decoded as %T", event)
+	}
+
+	if _, err := DecodeSwapEvent(log); !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("Initialize log decoded as Swap returned %v", err)
+	}
+	if _, err := DecodePoolManagerLog(EventLog{}); !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("log without topics returned %v", err)
+	}
+	// the Transfer of ERC20 tokens has the signature of neither PoolManager event
+	erc20Transfer := EventLog{Topics: []common.Hash{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")}}
+	if _, err := DecodePoolManagerLog(erc20Transfer); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("ERC20 Transfer returned %v", err)
+	}
+
+	missingTopic := log
+	missingTopic.Topics = log.Topics[:3]
+	if _, err := DecodeInitializeEvent(missingTopic); !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("log without currency1 returned %v", err)
+	}
+	truncated := log
+	truncated.Data = log.Data[:len(log.Data)-32]
+	if _, err := DecodeInitializeEvent(truncated); err == nil {
+		t.Error("log without the tick decoded")
+	}
+}
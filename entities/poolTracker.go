@@ -0,0 +1,460 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+)
+
+var (
+	ErrPoolNotTracked     = errors.New("pool not tracked")
+	ErrPoolAlreadyTracked = errors.New("pool already tracked")
+	ErrEventOutOfOrder    = errors.New("event is not after the last applied event")
+	ErrNegativeLiquidity  = errors.New("liquidity would become negative")
+	ErrUnsupportedEvent   = errors.New("unsupported event")
+	ErrNilLiquidityGross  = errors.New("nil tick liquidityGross")
+)
+
+/**
+ * PoolTracker maintains the state of many pools from the PoolManager events, so that up to date pools can be
+ * quoted without fetching them on every block. Events must be applied in the order they were emitted in, Initialize
+ * starts tracking a pool and Swap and ModifyLiquidity keep its price, liquidity and initialized ticks current.
+ * Pools initialized before the first applied event are tracked with Track. Events of untracked pools are ignored.
+ *
+ * The tracker keeps the changes of the last blocks, see SetReorgDepth, so that the state can be rewound when they
+ * are reorged out of the chain. Logs with Removed set rewind it automatically.
+ *
+ * A PoolTracker is safe for concurrent use, e.g. by an indexer applying events and a service quoting its pools.
+ */
+type PoolTracker struct {
+	mu       sync.RWMutex
+	registry TokenRegistry
+	pools    map[PoolId]*trackedPool
+
+	// the position of the last applied event
+	lastBlock uint64
+	lastIndex uint
+	applied   bool
+
+	// undo information of the last reorgDepth blocks, oldest first
+	reorgDepth uint64
+	journal    []blockUndo
+	// the most recent block that can no longer be rewound, valid when pruned is set
+	prunedBlock uint64
+	pruned      bool
+}
+
+// poolState is the slot0 and liquidity of a tracked pool
+type poolState struct {
+	sqrtPriceX96 *big.Int
+	tick         int
+	liquidity    *big.Int
+	lpFee        int64
+	// lpFeeSet is false while the LP fee of a dynamic fee pool is unknown
+	lpFeeSet    bool
+	protocolFee int64
+}
+
+// trackedPool is the state of a pool in the PoolManager. Its big ints are never modified in place, so that
+// snapshots can share them.
+type trackedPool struct {
+	key PoolKey
+	poolState
+	ticks map[int]Tick
+
+	// snapshot caches the pool of the current state until the next change
+	snapshot *Pool
+}
+
+/**
+ * Creates an empty tracker
+ * @param registry Resolves the currencies of the tracked pools when building their snapshots
+ */
+func NewPoolTracker(registry TokenRegistry) *PoolTracker {
+	return &PoolTracker{
+		registry:   registry,
+		pools:      make(map[PoolId]*trackedPool),
+		reorgDepth: DefaultReorgDepth,
+	}
+}
+
+/**
+ * Starts tracking a pool from its current state, e.g. one fetched from StateView for a pool initialized before
+ * the events applied to the tracker
+ * @param pool The pool at the block of the next event to apply
+ * @param ticks All initialized ticks of the pool, with the liquidityGross returned by getTickLiquidity which is needed to
+ * know when a tick becomes uninitialized
+ */
+func (t *PoolTracker) Track(pool *Pool, ticks []Tick) error {
+	if err := validateTicks(ticks, int(pool.TickSpacing)); err != nil {
+		return err
+	}
+	for _, tick := range ticks {
+		if tick.LiquidityGross == nil {
+			return fmt.Errorf("%w: tick %d", ErrNilLiquidityGross, tick.Index)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pools[pool.PoolId]; ok {
+		return ErrPoolAlreadyTracked
+	}
+	tracked := &trackedPool{
+		key: pool.PoolKey,
+		poolState: poolState{
+			sqrtPriceX96: new(big.Int).Set(pool.SqrtRatioX96),
+			tick:         pool.TickCurrent,
+			liquidity:    new(big.Int).Set(pool.Liquidity),
+			lpFee:        pool.LPFee,
+			lpFeeSet:     pool.lpFeeSet,
+		},
+		ticks: make(map[int]Tick, len(ticks)),
+	}
+	for _, tick := range ticks {
+		if _, ok := tracked.ticks[tick.Index]; ok {
+			return ErrTicksNotSorted
+		}
+		tracked.ticks[tick.Index] = Tick{
+			Index:          tick.Index,
+			LiquidityGross: new(big.Int).Set(tick.LiquidityGross),
+			LiquidityNet:   new(big.Int).Set(tick.LiquidityNet),
+		}
+	}
+	t.pools[pool.PoolId] = tracked
+	return nil
+}
+
+// Untrack stops tracking the pool. Like Track it is not undone by Rewind
+func (t *PoolTracker) Untrack(id PoolId) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pools, id)
+}
+
+/**
+ * Decodes the PoolManager log and applies it. Logs of events that do not change the tracked state, such as
+ * Donate or ERC6909 transfers, only advance the position of the tracker.
+ * @param log The log, in the order it was emitted in
+ */
+func (t *PoolTracker) HandleLog(log EventLog) error {
+	event, err := DecodePoolManagerLog(log)
+	if errors.Is(err, ErrUnknownEvent) {
+		return t.Apply(&unknownEvent{Raw: log})
+	}
+	if err != nil {
+		return err
+	}
+	return t.Apply(event)
+}
+
+// unknownEvent lets HandleLog advance the position of the tracker past logs of other events
+type unknownEvent struct {
+	Raw EventLog
+}
+
+/**
+ * Applies a decoded PoolManager event, as returned by DecodePoolManagerLog
+ * @param event The event, its Raw log must be after the last applied one
+ */
+func (t *PoolTracker) Apply(event interface{}) error {
+	raw, err := eventLog(event)
+	if err != nil {
+		return err
+	}
+	if raw.Removed {
+		// the block of the log and every later one are no longer part of the chain
+		if raw.BlockNumber == 0 {
+			return ErrReorgTooDeep
+		}
+		return t.Rewind(raw.BlockNumber - 1)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.applied && (raw.BlockNumber < t.lastBlock || raw.BlockNumber == t.lastBlock && raw.Index <= t.lastIndex) {
+		return fmt.Errorf("%w: log %d of block %d", ErrEventOutOfOrder, raw.Index, raw.BlockNumber)
+	}
+
+	t.beginBlock(raw.BlockNumber)
+	switch e := event.(type) {
+	case *InitializeEvent:
+		err = t.initialize(e)
+	case *SwapEvent:
+		err = t.swap(e)
+	case *ModifyLiquidityEvent:
+		err = t.modifyLiquidity(e)
+	case *ProtocolFeeUpdatedEvent:
+		if pool, ok := t.pools[e.Id]; ok {
+			t.recordState(e.Id, pool)
+			pool.protocolFee = e.ProtocolFee
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	t.lastBlock, t.lastIndex, t.applied = raw.BlockNumber, raw.Index, true
+	t.prune()
+	return nil
+}
+
+// eventLog returns the log an event was decoded from
+func eventLog(event interface{}) (EventLog, error) {
+	switch e := event.(type) {
+	case *InitializeEvent:
+		return e.Raw, nil
+	case *SwapEvent:
+		return e.Raw, nil
+	case *ModifyLiquidityEvent:
+		return e.Raw, nil
+	case *DonateEvent:
+		return e.Raw, nil
+	case *ProtocolFeeUpdatedEvent:
+		return e.Raw, nil
+	case *TransferEvent:
+		return e.Raw, nil
+	case *OperatorSetEvent:
+		return e.Raw, nil
+	case *unknownEvent:
+		return e.Raw, nil
+	}
+	return EventLog{}, fmt.Errorf("%w: %T", ErrUnsupportedEvent, event)
+}
+
+func (t *PoolTracker) initialize(e *InitializeEvent) error {
+	if _, ok := t.pools[e.Id]; ok {
+		return ErrPoolAlreadyTracked
+	}
+	// the LP fee of dynamic fee pools is unknown until a swap reports it
+	lpFee, lpFeeSet := e.Fee, true
+	if isDynamicFee(e.Fee) {
+		lpFee, lpFeeSet = 0, false
+	}
+	t.pools[e.Id] = &trackedPool{
+		key: e.PoolKey(),
+		poolState: poolState{
+			sqrtPriceX96: e.SqrtPriceX96,
+			tick:         e.Tick,
+			liquidity:    big.NewInt(0),
+			lpFee:        lpFee,
+			lpFeeSet:     lpFeeSet,
+		},
+		ticks: make(map[int]Tick),
+	}
+	t.recordCreate(e.Id)
+	return nil
+}
+
+func (t *PoolTracker) swap(e *SwapEvent) error {
+	pool, ok := t.pools[e.Id]
+	if !ok {
+		return nil
+	}
+	t.recordState(e.Id, pool)
+	pool.sqrtPriceX96 = e.SqrtPriceX96
+	pool.tick = e.Tick
+	pool.liquidity = e.Liquidity
+	// hooks update dynamic LP fees without an event, the fee last charged is the best estimate of the current one
+	if isDynamicFee(pool.key.Fee) {
+		if lpFee, ok := swapLPFee(e, pool.protocolFee); ok {
+			pool.lpFee, pool.lpFeeSet = lpFee, true
+		}
+	}
+	pool.snapshot = nil
+	return nil
+}
+
+/**
+ * Returns the LP fee a swap was charged with. The fee of the Swap event is the LP fee combined with the protocol fee
+ * of the direction of the swap, the lower 12 bits of the protocol fee for zeroForOne swaps and the upper 12 bits
+ * otherwise. It is false when the direction of a swap without amounts decides the protocol fee, or when no LP fee
+ * combines to the fee of the event. The rounding of the protocol fee makes some LP fees charge the same, the smallest
+ * of them is returned.
+ * @param e The swap
+ * @param protocolFee The protocol fee of the pool as set by ProtocolFeeUpdated
+ */
+func swapLPFee(e *SwapEvent, protocolFee int64) (int64, bool) {
+	zeroForOneFee, oneForZeroFee := protocolFee&0xfff, protocolFee>>12
+	directionalFee := zeroForOneFee
+	switch {
+	case e.Amount0.Sign() < 0:
+	case e.Amount1.Sign() < 0:
+		directionalFee = oneForZeroFee
+	case zeroForOneFee != oneForZeroFee:
+		return 0, false
+	}
+	if directionalFee == 0 {
+		return e.Fee, true
+	}
+
+	// swapFee grows by 0 or 1 with each step of lpFee, step from the estimate to the first lpFee reaching the fee
+	lpFee := (e.Fee - directionalFee) * constants.MaxLPFee / (constants.MaxLPFee - directionalFee)
+	if lpFee < 0 {
+		return 0, false
+	}
+	for lpFee > 0 && swapFee(directionalFee, lpFee-1) >= e.Fee {
+		lpFee--
+	}
+	for lpFee < constants.MaxLPFee && swapFee(directionalFee, lpFee) < e.Fee {
+		lpFee++
+	}
+	return lpFee, swapFee(directionalFee, lpFee) == e.Fee
+}
+
+// swapFee combines the protocol fee of a direction with the LP fee as ProtocolFeeLibrary.calculateSwapFee
+func swapFee(protocolFee, lpFee int64) int64 {
+	return protocolFee + lpFee - protocolFee*lpFee/constants.MaxLPFee
+}
+
+func (t *PoolTracker) modifyLiquidity(e *ModifyLiquidityEvent) error {
+	pool, ok := t.pools[e.Id]
+	if !ok {
+		return nil
+	}
+	if e.LiquidityDelta.Sign() == 0 {
+		// poking a position to collect its fees does not change the pool
+		return nil
+	}
+
+	lower, err := pool.updateTick(e.TickLower, e.LiquidityDelta, false)
+	if err != nil {
+		return err
+	}
+	upper, err := pool.updateTick(e.TickUpper, e.LiquidityDelta, true)
+	if err != nil {
+		return err
+	}
+	liquidity := pool.liquidity
+	if e.TickLower <= pool.tick && pool.tick < e.TickUpper {
+		liquidity = new(big.Int).Add(pool.liquidity, e.LiquidityDelta)
+		if liquidity.Sign() < 0 {
+			return ErrNegativeLiquidity
+		}
+	}
+
+	t.recordState(e.Id, pool)
+	t.recordTick(e.Id, pool, e.TickLower)
+	t.recordTick(e.Id, pool, e.TickUpper)
+	pool.setTick(lower)
+	pool.setTick(upper)
+	pool.liquidity = liquidity
+	pool.snapshot = nil
+	return nil
+}
+
+// updateTick returns the tick after adding liquidityDelta to a position with the tick as its lower or upper tick
+func (p *trackedPool) updateTick(index int, liquidityDelta *big.Int, upper bool) (Tick, error) {
+	tick, ok := p.ticks[index]
+	if !ok {
+		tick = Tick{Index: index, LiquidityGross: big.NewInt(0), LiquidityNet: big.NewInt(0)}
+	}
+	gross := new(big.Int).Add(tick.LiquidityGross, liquidityDelta)
+	if gross.Sign() < 0 {
+		return Tick{}, ErrNegativeLiquidity
+	}
+	net := new(big.Int)
+	if upper {
+		net.Sub(tick.LiquidityNet, liquidityDelta)
+	} else {
+		net.Add(tick.LiquidityNet, liquidityDelta)
+	}
+	return Tick{Index: index, LiquidityGross: gross, LiquidityNet: net}, nil
+}
+
+// setTick stores the tick, ticks without liquidity referencing them are uninitialized
+func (p *trackedPool) setTick(tick Tick) {
+	if tick.LiquidityGross.Sign() == 0 {
+		delete(p.ticks, tick.Index)
+		return
+	}
+	p.ticks[tick.Index] = tick
+}
+
+// Pool returns a snapshot of the current state of the tracked pool, with a TickBitmapProvider of its initialized ticks.
+// Swaps on the snapshot of a dynamic fee pool fail with ErrLPFeeNotSet until a Swap event has reported its fee.
+func (t *PoolTracker) Pool(id PoolId) (*Pool, error) {
+	t.mu.RLock()
+	tracked, ok := t.pools[id]
+	var snapshot *Pool
+	if ok {
+		snapshot = tracked.snapshot
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return nil, ErrPoolNotTracked
+	}
+	if snapshot != nil {
+		pool := *snapshot
+		return &pool, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tracked, ok = t.pools[id]
+	if !ok {
+		return nil, ErrPoolNotTracked
+	}
+	if tracked.snapshot == nil {
+		snapshot, err := tracked.newPool(t.registry)
+		if err != nil {
+			return nil, err
+		}
+		tracked.snapshot = snapshot
+	}
+	pool := *tracked.snapshot
+	return &pool, nil
+}
+
+func (p *trackedPool) newPool(registry TokenRegistry) (*Pool, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("%w: no token registry", ErrUnknownCurrency)
+	}
+	currency0, ok := registry.Currency(p.key.Currency0)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, p.key.Currency0.Hex())
+	}
+	currency1, ok := registry.Currency(p.key.Currency1)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, p.key.Currency1.Hex())
+	}
+
+	ticks := make([]Tick, 0, len(p.ticks))
+	for _, tick := range p.ticks {
+		ticks = append(ticks, tick)
+	}
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i].Index < ticks[j].Index })
+	provider, err := NewTickBitmapProvider(ticks, int(p.key.TickSpacing))
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := NewPool(currency0, currency1, p.key.Fee, p.key.TickSpacing, p.key.Hooks, p.sqrtPriceX96, p.liquidity, p.tick, provider)
+	if err != nil {
+		return nil, err
+	}
+	pool.LPFee, pool.lpFeeSet = p.lpFee, p.lpFeeSet
+	return pool, nil
+}
+
+// Pools returns the ids of the tracked pools
+func (t *PoolTracker) Pools() []PoolId {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	ids := make([]PoolId, 0, len(t.pools))
+	for id := range t.pools {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// LastApplied returns the block number and log index of the last applied event, ok is false before the first one
+func (t *PoolTracker) LastApplied() (blockNumber uint64, logIndex uint, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastBlock, t.lastIndex, t.applied
+}
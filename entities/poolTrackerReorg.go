@@ -0,0 +1,260 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultReorgDepth is the number of blocks a new PoolTracker can rewind
+const DefaultReorgDepth = 64
+
+var ErrReorgTooDeep = errors.New("reorg is deeper than the retained undo information")
+
+// blockUndo is the undo information of the events applied in one block
+type blockUndo struct {
+	block uint64
+	// the position of the tracker before the first event of the block
+	prevBlock   uint64
+	prevIndex   uint
+	prevApplied bool
+	entries     []undoEntry
+}
+
+type undoKind int
+
+const (
+	// the pool was initialized, undone by removing it
+	undoCreate undoKind = iota
+	// the slot0 or liquidity of the pool changed, undone by restoring state
+	undoState
+	// an initialized tick of the pool changed, undone by restoring tick
+	undoTick
+)
+
+type undoEntry struct {
+	kind  undoKind
+	id    PoolId
+	state poolState
+	// the previous tick, its LiquidityGross is nil when it was not initialized
+	tick Tick
+}
+
+/**
+ * Sets the number of blocks the tracker can rewind, 0 disables rewinding. Undo information of older blocks is
+ * discarded as events of new blocks are applied.
+ * @param depth The number of blocks
+ */
+func (t *PoolTracker) SetReorgDepth(depth uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reorgDepth = depth
+	if t.applied {
+		t.prune()
+	}
+}
+
+// beginBlock starts the undo information of the block unless events of the block were already applied
+func (t *PoolTracker) beginBlock(block uint64) {
+	if n := len(t.journal); n > 0 && t.journal[n-1].block == block {
+		return
+	}
+	t.journal = append(t.journal, blockUndo{
+		block:       block,
+		prevBlock:   t.lastBlock,
+		prevIndex:   t.lastIndex,
+		prevApplied: t.applied,
+	})
+}
+
+func (t *PoolTracker) record(entry undoEntry) {
+	undo := &t.journal[len(t.journal)-1]
+	undo.entries = append(undo.entries, entry)
+}
+
+func (t *PoolTracker) recordCreate(id PoolId) {
+	t.record(undoEntry{kind: undoCreate, id: id})
+}
+
+func (t *PoolTracker) recordState(id PoolId, pool *trackedPool) {
+	t.record(undoEntry{kind: undoState, id: id, state: pool.poolState})
+}
+
+func (t *PoolTracker) recordTick(id PoolId, pool *trackedPool, index int) {
+	tick, ok := pool.ticks[index]
+	if !ok {
+		tick = Tick{Index: index}
+	}
+	t.record(undoEntry{kind: undoTick, id: id, tick: tick})
+}
+
+// prune discards the undo information of the blocks that are deeper than the reorg depth
+func (t *PoolTracker) prune() {
+	n := 0
+	for n < len(t.journal) && t.journal[n].block+t.reorgDepth <= t.lastBlock {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	t.prunedBlock, t.pruned = t.journal[n-1].block, true
+	t.journal = append(t.journal[:0], t.journal[n:]...)
+}
+
+/**
+ * Restores the state the tracked pools had at the end of a block, undoing the events of every later block
+ * @param toBlock The last block that is still part of the chain
+ * @returns ErrReorgTooDeep, leaving the state unchanged, when the undo information of a later block was discarded
+ */
+func (t *PoolTracker) Rewind(toBlock uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.checkRewind(toBlock); err != nil {
+		return err
+	}
+
+	for n := len(t.journal); n > 0 && t.journal[n-1].block > toBlock; n = len(t.journal) {
+		undo := t.journal[n-1]
+		undo.apply(t.pools)
+		t.lastBlock, t.lastIndex, t.applied = undo.prevBlock, undo.prevIndex, undo.prevApplied
+		t.journal = t.journal[:n-1]
+	}
+	return nil
+}
+
+// checkRewind returns ErrReorgTooDeep when the undo information of a block after toBlock was discarded
+func (t *PoolTracker) checkRewind(toBlock uint64) error {
+	if t.applied && t.lastBlock > toBlock && t.pruned && t.prunedBlock > toBlock {
+		return fmt.Errorf("%w: cannot rewind to block %d, undo information ends at block %d", ErrReorgTooDeep, toBlock, t.prunedBlock)
+	}
+	return nil
+}
+
+// apply undoes the events of the block, last first
+func (u *blockUndo) apply(pools map[PoolId]*trackedPool) {
+	for i := len(u.entries) - 1; i >= 0; i-- {
+		u.entries[i].apply(pools)
+	}
+}
+
+func (entry undoEntry) apply(pools map[PoolId]*trackedPool) {
+	if entry.kind == undoCreate {
+		delete(pools, entry.id)
+		return
+	}
+	pool, ok := pools[entry.id]
+	if !ok {
+		// untracked since
+		return
+	}
+	switch entry.kind {
+	case undoState:
+		pool.poolState = entry.state
+	case undoTick:
+		if entry.tick.LiquidityGross == nil {
+			delete(pool.ticks, entry.tick.Index)
+		} else {
+			pool.ticks[entry.tick.Index] = entry.tick
+		}
+	}
+	pool.snapshot = nil
+}
+
+// TrackerCheckpoint is a copy of the state of every pool of a PoolTracker at the position it was taken at
+type TrackerCheckpoint struct {
+	// The position of the last event applied before the checkpoint
+	BlockNumber uint64
+	LogIndex    uint
+
+	applied  bool
+	registry TokenRegistry
+	pools    map[PoolId]*trackedPool
+}
+
+// Checkpoint copies the state of all tracked pools after the last applied event, the state can be restored with Restore
+func (t *PoolTracker) Checkpoint() *TrackerCheckpoint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return &TrackerCheckpoint{
+		BlockNumber: t.lastBlock,
+		LogIndex:    t.lastIndex,
+		applied:     t.applied,
+		registry:    t.registry,
+		pools:       clonePools(t.pools),
+	}
+}
+
+/**
+ * Copies the state all tracked pools had at the end of a block, as Checkpoint would after Rewind to the block but
+ * leaving the tracker unchanged. Like Rewind it does not undo Track and Untrack.
+ * @param block The block, its later blocks must be within the reorg depth
+ * @returns ErrReorgTooDeep when the undo information of a later block was discarded
+ */
+func (t *PoolTracker) CheckpointAt(block uint64) (*TrackerCheckpoint, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if err := t.checkRewind(block); err != nil {
+		return nil, err
+	}
+
+	checkpoint := &TrackerCheckpoint{
+		BlockNumber: t.lastBlock,
+		LogIndex:    t.lastIndex,
+		applied:     t.applied,
+		registry:    t.registry,
+		pools:       clonePools(t.pools),
+	}
+	for i := len(t.journal) - 1; i >= 0 && t.journal[i].block > block; i-- {
+		undo := &t.journal[i]
+		undo.apply(checkpoint.pools)
+		checkpoint.BlockNumber, checkpoint.LogIndex, checkpoint.applied = undo.prevBlock, undo.prevIndex, undo.prevApplied
+	}
+	return checkpoint, nil
+}
+
+/**
+ * Replaces the state of the tracker with the checkpoint. The undo information is discarded, so the tracker cannot
+ * be rewound before the block of the checkpoint.
+ * @param checkpoint The checkpoint, it stays usable afterwards
+ */
+func (t *PoolTracker) Restore(checkpoint *TrackerCheckpoint) {
+	pools := clonePools(checkpoint.pools)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pools = pools
+	t.lastBlock, t.lastIndex, t.applied = checkpoint.BlockNumber, checkpoint.LogIndex, checkpoint.applied
+	t.journal = nil
+	t.prunedBlock, t.pruned = checkpoint.BlockNumber, checkpoint.applied
+}
+
+// Pool returns the pool as it was at the checkpoint, see PoolTracker.Pool
+func (c *TrackerCheckpoint) Pool(id PoolId) (*Pool, error) {
+	tracked, ok := c.pools[id]
+	if !ok {
+		return nil, ErrPoolNotTracked
+	}
+	return tracked.newPool(c.registry)
+}
+
+// Pools returns the ids of the pools of the checkpoint
+func (c *TrackerCheckpoint) Pools() []PoolId {
+	ids := make([]PoolId, 0, len(c.pools))
+	for id := range c.pools {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// clonePools copies the pools, ticks and big ints are shared as they are never modified in place
+func clonePools(pools map[PoolId]*trackedPool) map[PoolId]*trackedPool {
+	clone := make(map[PoolId]*trackedPool, len(pools))
+	for id, pool := range pools {
+		c := *pool
+		c.ticks = make(map[int]Tick, len(pool.ticks))
+		for index, tick := range pool.ticks {
+			c.ticks[index] = tick
+		}
+		clone[id] = &c
+	}
+	return clone
+}
@@ -0,0 +1,234 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+// captureState copies the state of every pool of the tracker
+func (f *trackerFixture) captureState() map[PoolId]*trackedPool {
+	f.tracker.mu.RLock()
+	defer f.tracker.mu.RUnlock()
+	return clonePools(f.tracker.pools)
+}
+
+// expectPools checks the pools have exactly the slot0, liquidity, fees and initialized ticks of want
+func expectPools(t *testing.T, got, want map[PoolId]*trackedPool) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%d pools, want %d", len(got), len(want))
+	}
+	for id, w := range want {
+		g, ok := got[id]
+		if !ok {
+			t.Errorf("pool %s missing", id)
+			continue
+		}
+		if g.key != w.key || g.tick != w.tick || g.lpFee != w.lpFee || g.lpFeeSet != w.lpFeeSet || g.protocolFee != w.protocolFee ||
+			g.sqrtPriceX96.Cmp(w.sqrtPriceX96) != 0 || g.liquidity.Cmp(w.liquidity) != 0 {
+			t.Errorf("pool %s state %+v, want %+v", id, g.poolState, w.poolState)
+		}
+		if len(g.ticks) != len(w.ticks) {
+			t.Errorf("pool %s has %d initialized ticks, want %d", id, len(g.ticks), len(w.ticks))
+		}
+		for index, wt := range w.ticks {
+			gt, ok := g.ticks[index]
+			if !ok || gt.LiquidityGross.Cmp(wt.LiquidityGross) != 0 || gt.LiquidityNet.Cmp(wt.LiquidityNet) != 0 {
+				t.Errorf("pool %s tick %d is %+v, want %+v", id, index, gt, wt)
+			}
+		}
+	}
+}
+
+func TestPoolTrackerRewindMultipleBlocks(t *testing.T) {
+	f := newTrackerFixture(t)
+	other := f.withPool(500, 10)
+
+	f.mustApply(f.initialize(10, 0, 100))
+	f.mustApply(f.modifyLiquidity(10, 1, -120, 120, 1000))
+	atBlock10 := f.captureState()
+
+	f.mustApply(f.swap(11, 0, 130, 1000))
+	f.mustApply(f.modifyLiquidity(11, 1, 60, 180, 250))
+	f.mustApply(f.tracker.Apply(&ProtocolFeeUpdatedEvent{Id: f.id, ProtocolFee: 100, Raw: EventLog{BlockNumber: 11, Index: 2}}))
+	atBlock11 := f.captureState()
+
+	// a pool created in a later block and ticks touched again
+	f.mustApply(other.initialize(12, 0, -5))
+	f.mustApply(other.modifyLiquidity(12, 1, -10, 10, 77))
+	f.mustApply(f.modifyLiquidity(12, 2, 60, 180, -250))
+	f.mustApply(f.modifyLiquidity(13, 0, -120, 240, 40))
+	f.mustApply(f.swap(13, 1, -30, 1040))
+
+	if err := f.tracker.Rewind(11); err != nil {
+		t.Fatal(err)
+	}
+	expectPools(t, f.captureState(), atBlock11)
+	if block, index, _ := f.tracker.LastApplied(); block != 11 || index != 2 {
+		t.Errorf("last applied %d %d, want 11 2", block, index)
+	}
+
+	if err := f.tracker.Rewind(10); err != nil {
+		t.Fatal(err)
+	}
+	expectPools(t, f.captureState(), atBlock10)
+	if _, err := f.tracker.Pool(other.id); !errors.Is(err, ErrPoolNotTracked) {
+		t.Errorf("pool created after the block returned %v", err)
+	}
+	f.expectState(100, 1000)
+
+	// rewinding to the current or a later block does nothing
+	if err := f.tracker.Rewind(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.tracker.Rewind(20); err != nil {
+		t.Fatal(err)
+	}
+	expectPools(t, f.captureState(), atBlock10)
+
+	// rewinding before the first event removes the pools it initialized
+	if err := f.tracker.Rewind(9); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.tracker.Pools()) != 0 {
+		t.Errorf("%d pools left", len(f.tracker.Pools()))
+	}
+	if _, _, ok := f.tracker.LastApplied(); ok {
+		t.Error("events still applied")
+	}
+	f.mustApply(f.initialize(10, 0, 100))
+}
+
+func TestPoolTrackerReorgTooDeep(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.tracker.SetReorgDepth(2)
+	f.mustApply(f.initialize(10, 0, 0))
+	f.mustApply(f.modifyLiquidity(10, 1, -600, 600, 1000))
+	var atBlock12 map[PoolId]*trackedPool
+	for block := uint64(11); block <= 14; block++ {
+		f.mustApply(f.swap(block, 0, int(block)*10, 1000))
+		if block == 12 {
+			atBlock12 = f.captureState()
+		}
+	}
+
+	// blocks 13 and 14 can be undone, block 12 can not
+	before := f.captureState()
+	if err := f.tracker.Rewind(11); !errors.Is(err, ErrReorgTooDeep) {
+		t.Fatalf("rewinding past the depth returned %v", err)
+	}
+	expectPools(t, f.captureState(), before)
+	if _, err := f.tracker.CheckpointAt(11); !errors.Is(err, ErrReorgTooDeep) {
+		t.Errorf("checkpoint past the depth returned %v", err)
+	}
+	if err := f.tracker.Rewind(12); err != nil {
+		t.Fatal(err)
+	}
+	expectPools(t, f.captureState(), atBlock12)
+
+	// without undo information no block can be rewound
+	f.tracker.SetReorgDepth(0)
+	f.mustApply(f.swap(13, 0, 200, 1000))
+	if err := f.tracker.Rewind(12); !errors.Is(err, ErrReorgTooDeep) {
+		t.Errorf("rewinding with depth 0 returned %v", err)
+	}
+	removed := &SwapEvent{Id: f.id, Raw: EventLog{BlockNumber: 13, Index: 0, Removed: true}}
+	if err := f.tracker.Apply(removed); !errors.Is(err, ErrReorgTooDeep) {
+		t.Errorf("removed log with depth 0 returned %v", err)
+	}
+	f.expectState(200, 1000)
+	if err := f.tracker.Rewind(13); err != nil {
+		t.Errorf("rewinding to the last block returned %v", err)
+	}
+}
+
+func TestPoolTrackerRemovedLogRewindsLaterBlocks(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 0))
+	f.mustApply(f.modifyLiquidity(10, 1, -60, 60, 1000))
+	atBlock10 := f.captureState()
+	f.mustApply(f.swap(11, 4, 30, 1000))
+	f.mustApply(f.modifyLiquidity(12, 0, 0, 120, 500))
+
+	// removed logs are delivered for the reorged blocks, the first one rewinds them all
+	f.mustApply(f.tracker.Apply(&ModifyLiquidityEvent{Id: f.id, Raw: EventLog{BlockNumber: 11, Index: 4, Removed: true}}))
+	expectPools(t, f.captureState(), atBlock10)
+	f.mustApply(f.tracker.Apply(&ModifyLiquidityEvent{Id: f.id, Raw: EventLog{BlockNumber: 12, Index: 0, Removed: true}}))
+	expectPools(t, f.captureState(), atBlock10)
+
+	// the same positions are valid again on the new chain
+	f.mustApply(f.swap(11, 0, -30, 1000))
+	f.mustApply(f.modifyLiquidity(12, 0, 0, 120, 500))
+	f.expectState(-30, 1000)
+}
+
+func TestPoolTrackerCheckpointRestore(t *testing.T) {
+	f := newTrackerFixture(t)
+	other := f.withPool(500, 10)
+	f.mustApply(f.initialize(10, 0, 0))
+	f.mustApply(f.modifyLiquidity(10, 1, -60, 60, 1000))
+	f.mustApply(f.swap(11, 0, 20, 1000))
+	atBlock11 := f.captureState()
+	checkpoint := f.tracker.Checkpoint()
+	if checkpoint.BlockNumber != 11 || checkpoint.LogIndex != 0 {
+		t.Errorf("checkpoint at %d %d", checkpoint.BlockNumber, checkpoint.LogIndex)
+	}
+
+	f.mustApply(other.initialize(12, 0, 0))
+	f.mustApply(f.modifyLiquidity(12, 1, 0, 120, 300))
+	f.mustApply(f.swap(13, 0, 90, 300))
+	expectPools(t, checkpoint.pools, atBlock11)
+
+	for i := 0; i < 2; i++ {
+		f.tracker.Restore(checkpoint)
+		expectPools(t, f.captureState(), atBlock11)
+		if block, index, _ := f.tracker.LastApplied(); block != 11 || index != 0 {
+			t.Errorf("last applied %d %d", block, index)
+		}
+		// the undo information before the checkpoint is gone
+		if err := f.tracker.Rewind(10); !errors.Is(err, ErrReorgTooDeep) {
+			t.Errorf("rewinding before the checkpoint returned %v", err)
+		}
+		f.mustApply(f.modifyLiquidity(12, 0, 0, 120, 300))
+		f.expectState(20, 1300)
+	}
+}
+
+func TestPoolTrackerCheckpointAt(t *testing.T) {
+	f := newTrackerFixture(t)
+	other := f.withPool(500, 10)
+	f.mustApply(f.initialize(10, 0, 0))
+	f.mustApply(f.modifyLiquidity(10, 1, -60, 60, 1000))
+	f.mustApply(f.swap(11, 3, 20, 1000))
+	atBlock11 := f.captureState()
+	f.mustApply(other.initialize(12, 0, 0))
+	f.mustApply(f.modifyLiquidity(12, 1, 0, 120, 300))
+	f.mustApply(f.swap(13, 0, 90, 300))
+	current := f.captureState()
+
+	checkpoint, err := f.tracker.CheckpointAt(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.BlockNumber != 11 || checkpoint.LogIndex != 3 {
+		t.Errorf("checkpoint at %d %d", checkpoint.BlockNumber, checkpoint.LogIndex)
+	}
+	expectPools(t, checkpoint.pools, atBlock11)
+	// the tracker is unchanged
+	expectPools(t, f.captureState(), current)
+
+	pool, err := checkpoint.Pool(f.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.TickCurrent != 20 || pool.Liquidity.Int64() != 1000 {
+		t.Errorf("checkpoint pool at tick %d liquidity %s", pool.TickCurrent, pool.Liquidity)
+	}
+	if _, err := checkpoint.Pool(other.id); !errors.Is(err, ErrPoolNotTracked) {
+		t.Errorf("pool created after the checkpoint returned %v", err)
+	}
+
+	f.tracker.Restore(checkpoint)
+	expectPools(t, f.captureState(), atBlock11)
+	f.mustApply(f.swap(12, 0, -20, 1000))
+}
@@ -0,0 +1,418 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// trackerFixture applies events of a token0/token1 pool with fee 3000 and tick spacing 60 to a tracker
+type trackerFixture struct {
+	t       *testing.T
+	tracker *PoolTracker
+	key     PoolKey
+	id      PoolId
+}
+
+func newTrackerFixture(t *testing.T) *trackerFixture {
+	t.Helper()
+	key := PoolKey{Currency0: testToken0.Address, Currency1: testToken1.Address, Fee: 3000, TickSpacing: 60}
+	id, err := key.ToId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &trackerFixture{t: t, tracker: NewPoolTracker(NewCurrencyMap(testToken0, testToken1)), key: key, id: id}
+}
+
+// withPool returns a fixture of another pool of the same tokens in the same tracker
+func (f *trackerFixture) withPool(fee, tickSpacing int64) *trackerFixture {
+	f.t.Helper()
+	key := PoolKey{Currency0: f.key.Currency0, Currency1: f.key.Currency1, Fee: fee, TickSpacing: tickSpacing}
+	id, err := key.ToId()
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return &trackerFixture{t: f.t, tracker: f.tracker, key: key, id: id}
+}
+
+func (f *trackerFixture) sqrtPrice(tick int) *big.Int {
+	f.t.Helper()
+	sqrtPriceX96, err := utils.GetSqrtRatioAtTick(tick)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return sqrtPriceX96
+}
+
+func (f *trackerFixture) initialize(block uint64, index uint, tick int) error {
+	return f.tracker.Apply(&InitializeEvent{
+		Id:           f.id,
+		Currency0:    f.key.Currency0,
+		Currency1:    f.key.Currency1,
+		Fee:          f.key.Fee,
+		TickSpacing:  f.key.TickSpacing,
+		Hooks:        f.key.Hooks,
+		SqrtPriceX96: f.sqrtPrice(tick),
+		Tick:         tick,
+		Raw:          EventLog{BlockNumber: block, Index: index},
+	})
+}
+
+func (f *trackerFixture) modifyLiquidity(block uint64, index uint, tickLower, tickUpper int, liquidityDelta int64) error {
+	return f.tracker.Apply(&ModifyLiquidityEvent{
+		Id:             f.id,
+		TickLower:      tickLower,
+		TickUpper:      tickUpper,
+		LiquidityDelta: big.NewInt(liquidityDelta),
+		Raw:            EventLog{BlockNumber: block, Index: index},
+	})
+}
+
+func (f *trackerFixture) swap(block uint64, index uint, tick int, liquidity int64) error {
+	return f.tracker.Apply(&SwapEvent{
+		Id:           f.id,
+		Amount0:      big.NewInt(0),
+		Amount1:      big.NewInt(0),
+		SqrtPriceX96: f.sqrtPrice(tick),
+		Liquidity:    big.NewInt(liquidity),
+		Tick:         tick,
+		Fee:          f.key.Fee,
+		Raw:          EventLog{BlockNumber: block, Index: index},
+	})
+}
+
+func (f *trackerFixture) mustApply(err error) {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// expectState checks the slot0 and liquidity of the pool snapshot
+func (f *trackerFixture) expectState(tick int, liquidity int64) {
+	f.t.Helper()
+	pool, err := f.tracker.Pool(f.id)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if pool.TickCurrent != tick || pool.SqrtRatioX96.Cmp(f.sqrtPrice(tick)) != 0 {
+		f.t.Errorf("pool at tick %d price %s, want tick %d", pool.TickCurrent, pool.SqrtRatioX96, tick)
+	}
+	if pool.Liquidity.Cmp(big.NewInt(liquidity)) != 0 {
+		f.t.Errorf("pool liquidity %s, want %d", pool.Liquidity, liquidity)
+	}
+}
+
+// expectTicks checks the initialized ticks of the pool, keyed by index with their gross and net liquidity
+func (f *trackerFixture) expectTicks(want map[int][2]int64) {
+	f.t.Helper()
+	f.tracker.mu.RLock()
+	defer f.tracker.mu.RUnlock()
+	tracked, ok := f.tracker.pools[f.id]
+	if !ok {
+		f.t.Fatal("pool not tracked")
+	}
+	if len(tracked.ticks) != len(want) {
+		f.t.Errorf("%d initialized ticks, want %d", len(tracked.ticks), len(want))
+	}
+	for index, liquidity := range want {
+		tick, ok := tracked.ticks[index]
+		if !ok {
+			f.t.Errorf("tick %d not initialized", index)
+			continue
+		}
+		if tick.LiquidityGross.Int64() != liquidity[0] || tick.LiquidityNet.Int64() != liquidity[1] {
+			f.t.Errorf("tick %d gross %s net %s, want %d %d", index, tick.LiquidityGross, tick.LiquidityNet, liquidity[0], liquidity[1])
+		}
+	}
+}
+
+func TestPoolTrackerInitialize(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 100))
+	f.expectState(100, 0)
+	f.expectTicks(map[int][2]int64{})
+
+	if err := f.initialize(10, 1, 100); !errors.Is(err, ErrPoolAlreadyTracked) {
+		t.Errorf("initializing twice returned %v", err)
+	}
+	if block, index, ok := f.tracker.LastApplied(); !ok || block != 10 || index != 0 {
+		t.Errorf("last applied %d %d %v", block, index, ok)
+	}
+}
+
+func TestPoolTrackerModifyLiquidity(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 100))
+
+	// a range containing the current tick adds to the liquidity of the pool
+	f.mustApply(f.modifyLiquidity(10, 1, -120, 120, 1000))
+	f.expectState(100, 1000)
+	// a range above the current tick only initializes its ticks
+	f.mustApply(f.modifyLiquidity(10, 2, 120, 240, 500))
+	f.expectState(100, 1000)
+	f.expectTicks(map[int][2]int64{-120: {1000, 1000}, 120: {1500, -500}, 240: {500, -500}})
+
+	// the upper tick is exclusive
+	f.mustApply(f.modifyLiquidity(11, 0, -240, 60, 300))
+	f.expectState(100, 1000)
+
+	// removing all liquidity of a tick uninitializes it
+	f.mustApply(f.modifyLiquidity(11, 1, 120, 240, -500))
+	f.expectTicks(map[int][2]int64{-240: {300, 300}, -120: {1000, 1000}, 60: {300, -300}, 120: {1000, -1000}})
+
+	if err := f.modifyLiquidity(11, 2, -120, 120, -2000); !errors.Is(err, ErrNegativeLiquidity) {
+		t.Errorf("removing more liquidity than the ticks hold returned %v", err)
+	}
+	f.expectState(100, 1000)
+}
+
+func TestPoolTrackerSwap(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 100))
+	f.mustApply(f.modifyLiquidity(10, 1, -120, 120, 1000))
+	f.mustApply(f.modifyLiquidity(10, 2, 120, 240, 500))
+
+	f.mustApply(f.swap(11, 0, 150, 500))
+	f.expectState(150, 500)
+	// a position added after the swap is in range of the new tick
+	f.mustApply(f.modifyLiquidity(11, 1, 120, 180, 200))
+	f.expectState(150, 700)
+
+	pool, err := f.tracker.Pool(f.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.LPFee != 3000 || pool.PoolId != f.id {
+		t.Errorf("pool fee %d id %s", pool.LPFee, pool.PoolId)
+	}
+}
+
+func TestPoolTrackerOutOfOrder(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 5, 100))
+	f.mustApply(f.swap(11, 3, 110, 0))
+
+	for _, position := range [][2]uint64{{11, 3}, {11, 2}, {10, 9}} {
+		err := f.swap(position[0], uint(position[1]), 200, 0)
+		if !errors.Is(err, ErrEventOutOfOrder) {
+			t.Errorf("log %d of block %d returned %v", position[1], position[0], err)
+		}
+	}
+	f.expectState(110, 0)
+	if block, index, _ := f.tracker.LastApplied(); block != 11 || index != 3 {
+		t.Errorf("last applied %d %d", block, index)
+	}
+}
+
+func TestPoolTrackerRemovedLog(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 100))
+	f.mustApply(f.modifyLiquidity(10, 1, -120, 120, 1000))
+	f.mustApply(f.swap(11, 0, 110, 1000))
+	f.mustApply(f.modifyLiquidity(11, 1, 60, 180, 400))
+	f.expectState(110, 1400)
+
+	// the removed log reverts its whole block
+	f.mustApply(f.tracker.Apply(&SwapEvent{Id: f.id, Raw: EventLog{BlockNumber: 11, Index: 0, Removed: true}}))
+	f.expectState(100, 1000)
+	f.expectTicks(map[int][2]int64{-120: {1000, 1000}, 120: {1000, -1000}})
+	if block, index, _ := f.tracker.LastApplied(); block != 10 || index != 1 {
+		t.Errorf("last applied %d %d", block, index)
+	}
+
+	// the block of the new chain is applied in its place
+	f.mustApply(f.swap(11, 0, 90, 1000))
+	f.mustApply(f.modifyLiquidity(11, 1, -60, 120, 100))
+	f.expectState(90, 1100)
+	f.expectTicks(map[int][2]int64{-120: {1000, 1000}, -60: {100, 100}, 120: {1100, -1100}})
+}
+
+func TestPoolTrackerHandleLog(t *testing.T) {
+	f := newTrackerFixture(t)
+	f.mustApply(f.initialize(10, 0, 100))
+
+	data, err := encodeArguments(swapEvent.Inputs.NonIndexed(), -5, 4, f.sqrtPrice(-20), 700, -20, 3000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := EventLog{
+		Topics:      []common.Hash{SwapTopic, common.Hash(f.id), common.BytesToHash(testToken0.Address.Bytes())},
+		Data:        data,
+		BlockNumber: 10,
+		Index:       1,
+	}
+	f.mustApply(f.tracker.HandleLog(log))
+	f.expectState(-20, 700)
+
+	// logs of other contracts' events only advance the tracker
+	f.mustApply(f.tracker.HandleLog(EventLog{Topics: []common.Hash{{1}}, BlockNumber: 10, Index: 2}))
+	if block, index, _ := f.tracker.LastApplied(); block != 10 || index != 2 {
+		t.Errorf("last applied %d %d", block, index)
+	}
+}
+
+func TestPoolTrackerTrackRequiresLiquidityGross(t *testing.T) {
+	pool, err := NewPool(testToken0, testToken1, 3000, 60, common.Address{}, utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1)), big.NewInt(10), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks := []Tick{
+		{Index: -60, LiquidityNet: big.NewInt(10)},
+		{Index: 60, LiquidityNet: big.NewInt(-10)},
+	}
+	tracker := NewPoolTracker(NewCurrencyMap(testToken0, testToken1))
+	if err := tracker.Track(pool, ticks); !errors.Is(err, ErrNilLiquidityGross) {
+		t.Fatalf("tracking ticks without liquidityGross returned %v", err)
+	}
+
+	ticks[0].LiquidityGross, ticks[1].LiquidityGross = big.NewInt(10), big.NewInt(10)
+	if err := tracker.Track(pool, ticks); err != nil {
+		t.Fatal(err)
+	}
+	tracked, err := tracker.Pool(pool.PoolId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracked.Liquidity.Int64() != 10 || tracked.TickCurrent != 0 {
+		t.Errorf("tracked pool liquidity %s tick %d", tracked.Liquidity, tracked.TickCurrent)
+	}
+}
+
+func TestPoolTrackerDynamicFee(t *testing.T) {
+	f := newTrackerFixture(t).withPool(constants.DynamicFeeFlag, 60)
+	f.mustApply(f.initialize(10, 0, 0))
+	f.mustApply(f.modifyLiquidity(10, 1, -600, 600, 1000000000))
+	inputAmount := core.FromRawAmount(testToken0, big.NewInt(1000))
+
+	// the LP fee is unknown until a swap reports it
+	pool, err := f.tracker.Pool(f.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrLPFeeNotSet) {
+		t.Errorf("swap before the fee is known returned %v", err)
+	}
+
+	f.mustApply(f.tracker.Apply(&SwapEvent{
+		Id:           f.id,
+		Amount0:      big.NewInt(0),
+		Amount1:      big.NewInt(0),
+		SqrtPriceX96: f.sqrtPrice(0),
+		Liquidity:    big.NewInt(1000000000),
+		Fee:          500,
+		Raw:          EventLog{BlockNumber: 11, Index: 0},
+	}))
+	pool, err = f.tracker.Pool(f.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.LPFee != 500 {
+		t.Errorf("pool fee %d", pool.LPFee)
+	}
+	if _, err := pool.GetOutputAmount(inputAmount, nil); err != nil {
+		t.Errorf("swap after the fee is known returned %v", err)
+	}
+
+	// rewinding the swap forgets the fee again
+	if err := f.tracker.Rewind(10); err != nil {
+		t.Fatal(err)
+	}
+	pool, err = f.tracker.Pool(f.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrLPFeeNotSet) {
+		t.Errorf("swap after rewinding returned %v", err)
+	}
+
+	// a tracked pool keeps the fee set on it
+	pool, err = NewPool(testToken0, testToken1, constants.DynamicFeeFlag, 10, common.Address{}, f.sqrtPrice(0), big.NewInt(1000000000), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.SetLPFee(700); err != nil {
+		t.Fatal(err)
+	}
+	tracker := NewPoolTracker(NewCurrencyMap(testToken0, testToken1))
+	f.mustApply(tracker.Track(pool, []Tick{
+		{Index: -600, LiquidityNet: big.NewInt(1000000000), LiquidityGross: big.NewInt(1000000000)},
+		{Index: 600, LiquidityNet: big.NewInt(-1000000000), LiquidityGross: big.NewInt(1000000000)},
+	}))
+	tracked, err := tracker.Pool(pool.PoolId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tracked.GetOutputAmount(inputAmount, nil); err != nil || tracked.LPFee != 700 {
+		t.Errorf("tracked pool fee %d, swap returned %v", tracked.LPFee, err)
+	}
+}
+
+func TestPoolTrackerDynamicFeeWithProtocolFee(t *testing.T) {
+	f := newTrackerFixture(t).withPool(constants.DynamicFeeFlag, 60)
+	f.mustApply(f.initialize(10, 0, 0))
+	// 0.05% on zeroForOne swaps and 0.1% on oneForZero swaps
+	f.mustApply(f.tracker.Apply(&ProtocolFeeUpdatedEvent{Id: f.id, ProtocolFee: 1000<<12 | 500, Raw: EventLog{BlockNumber: 10, Index: 1}}))
+
+	tests := []struct {
+		amount0, amount1 int64
+		fee              int64
+		lpFee            int64
+	}{
+		// 500 + 3000 - 500 * 3000 / 1e6
+		{-100, 99, 3499, 3000},
+		// 1000 + 2500 - 1000 * 2500 / 1e6
+		{99, -100, 3498, 2500},
+		{-100, 99, 500, 0},
+	}
+	for i, tt := range tests {
+		f.mustApply(f.tracker.Apply(&SwapEvent{
+			Id:           f.id,
+			Amount0:      big.NewInt(tt.amount0),
+			Amount1:      big.NewInt(tt.amount1),
+			SqrtPriceX96: f.sqrtPrice(0),
+			Liquidity:    big.NewInt(0),
+			Fee:          tt.fee,
+			Raw:          EventLog{BlockNumber: 11, Index: uint(i)},
+		}))
+		pool, err := f.tracker.Pool(f.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pool.LPFee != tt.lpFee {
+			t.Errorf("swap fee %d: LP fee %d, want %d", tt.fee, pool.LPFee, tt.lpFee)
+		}
+	}
+}
+
+func TestSwapLPFee(t *testing.T) {
+	zeroForOne := &SwapEvent{Amount0: big.NewInt(-1), Amount1: big.NewInt(1)}
+	for _, protocolFee := range []int64{1, 500, 999, 1000} {
+		// every LP fee is recovered up to the LP fees charging the same swap fee
+		for lpFee := int64(0); lpFee <= constants.MaxLPFee; lpFee += 7 {
+			zeroForOne.Fee = swapFee(protocolFee, lpFee)
+			derived, ok := swapLPFee(zeroForOne, protocolFee)
+			if !ok || swapFee(protocolFee, derived) != zeroForOne.Fee || derived > lpFee {
+				t.Fatalf("protocol fee %d, LP fee %d: derived %d, %v", protocolFee, lpFee, derived, ok)
+			}
+		}
+	}
+
+	noAmounts := &SwapEvent{Amount0: big.NewInt(0), Amount1: big.NewInt(0), Fee: 3499}
+	if lpFee, ok := swapLPFee(noAmounts, 500<<12|500); !ok || lpFee != 3000 {
+		t.Errorf("swap without amounts derived %d, %v", lpFee, ok)
+	}
+	if _, ok := swapLPFee(noAmounts, 1000<<12|500); ok {
+		t.Error("swap without amounts derived a fee with the protocol fee depending on its direction")
+	}
+	// the fee of the event is below the protocol fee
+	zeroForOne.Fee = 400
+	if _, ok := swapLPFee(zeroForOne, 500); ok {
+		t.Error("fee below the protocol fee derived a LP fee")
+	}
+}
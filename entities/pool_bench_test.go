@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	benchUSDC = core.NewToken(1, common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), 6, "USDC", "USD Coin")
+	benchDAI  = core.NewToken(1, common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), 18, "DAI", "DAI Stablecoin")
+)
+
+// benchPool returns a USDC/DAI pool at price 1 with a full range position and, every 10 ticks,
+// a narrow position so that large swaps cross many initialized ticks
+func benchPool(b testing.TB, rangeCount int) *Pool {
+	b.Helper()
+	const tickSpacing = 10
+	liquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	ticks := []Tick{
+		{Index: utils.MinUsableTick(tickSpacing), LiquidityNet: liquidity, LiquidityGross: liquidity},
+		{Index: utils.MaxUsableTick(tickSpacing), LiquidityNet: new(big.Int).Neg(liquidity), LiquidityGross: liquidity},
+	}
+	for i := 1; i <= rangeCount; i++ {
+		for _, tick := range []int{-i * tickSpacing, i * tickSpacing} {
+			ticks = append(ticks, Tick{Index: tick, LiquidityNet: big.NewInt(0), LiquidityGross: big.NewInt(1)})
+		}
+	}
+	provider, err := NewTickBitmapProvider(ticks, tickSpacing)
+	if err != nil {
+		b.Fatal(err)
+	}
+	pool, err := NewPool(benchUSDC, benchDAI, 500, tickSpacing, common.Address{},
+		utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1)), liquidity, 0, provider)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return pool
+}
+
+// TestSwapAllocations checks that the swap loop does not allocate, only the conversion of the result to big.Int does
+func TestSwapAllocations(t *testing.T) {
+	pool := benchPool(t, 100)
+	tests := []struct {
+		name      string
+		amount    *big.Int
+		maxAllocs float64
+	}{
+		{"single tick", big.NewInt(1000), 7},
+		// the liquidity changes when crossing initialized ticks
+		{"multi tick", new(big.Int).Exp(big.NewInt(10), big.NewInt(17), nil), 9},
+		{"exact output", big.NewInt(-1000), 7},
+	}
+	for _, tt := range tests {
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := pool.swap(true, tt.amount, nil); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs > tt.maxAllocs {
+			t.Errorf("%s: %v allocations, want at most %v", tt.name, allocs, tt.maxAllocs)
+		}
+	}
+}
+
+func benchmarkGetOutputAmount(b *testing.B, pool *Pool, amount *big.Int) {
+	inputAmount := core.FromRawAmount(benchDAI, amount)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := pool.GetOutputAmount(inputAmount, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetOutputAmountSingleTick(b *testing.B) {
+	benchmarkGetOutputAmount(b, benchPool(b, 100), big.NewInt(1000))
+}
+
+func BenchmarkGetOutputAmountMultiTick(b *testing.B) {
+	// moves the price by roughly 10%, crossing about 100 initialized ticks
+	benchmarkGetOutputAmount(b, benchPool(b, 100), new(big.Int).Exp(big.NewInt(10), big.NewInt(17), nil))
+}
+
+func BenchmarkGetInputAmountMultiTick(b *testing.B) {
+	pool := benchPool(b, 100)
+	outputAmount := core.FromRawAmount(benchUSDC, new(big.Int).Exp(big.NewInt(10), big.NewInt(17), nil))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := pool.GetInputAmount(outputAmount, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
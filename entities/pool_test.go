@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// overrideFeeSimulator overrides the LP fee of every swap
+type overrideFeeSimulator struct {
+	NeutralHookSimulator
+	lpFee int64
+}
+
+func (s overrideFeeSimulator) BeforeSwap(pool *Pool, params SwapParams) (*BeforeSwapResult, error) {
+	return &BeforeSwapResult{LPFeeOverride: s.lpFee | constants.OverrideFeeFlag}, nil
+}
+
+// testPoolWithLiquidity returns a token0/token1 pool at price 1 with liquidity between ticks -600 and 600
+func testPoolWithLiquidity(t *testing.T, fee int64, hooks common.Address) *Pool {
+	t.Helper()
+	return testPoolWithCurrencies(t, testToken0, testToken1, fee, hooks)
+}
+
+// testPoolWithCurrencies returns a pool of the currencies at price 1 with liquidity between ticks -600 and 600
+func testPoolWithCurrencies(t *testing.T, currency0, currency1 core.Currency, fee int64, hooks common.Address) *Pool {
+	t.Helper()
+	liquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	provider, err := NewTickListDataProvider([]Tick{
+		{Index: -600, LiquidityNet: liquidity, LiquidityGross: liquidity},
+		{Index: 600, LiquidityNet: new(big.Int).Neg(liquidity), LiquidityGross: liquidity},
+	}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(currency0, currency1, fee, 60, hooks, utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1)), liquidity, 0, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestDynamicFeePoolRequiresLPFee(t *testing.T) {
+	inputAmount := core.FromRawAmount(testToken0, big.NewInt(1000000))
+	static, err := testPoolWithLiquidity(t, 3000, common.Address{}).GetOutputAmount(inputAmount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectOutput := func(t *testing.T, pool *Pool) {
+		t.Helper()
+		result, err := pool.GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.ReturnedAmount.Quotient().Cmp(static.ReturnedAmount.Quotient()) != 0 {
+			t.Errorf("output %s, want %s of the static fee pool", result.ReturnedAmount.Quotient(), static.ReturnedAmount.Quotient())
+		}
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, common.Address{})
+		if _, err := pool.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrLPFeeNotSet) {
+			t.Errorf("swap without LP fee returned %v", err)
+		}
+		if _, err := pool.GetInputAmount(core.FromRawAmount(testToken1, big.NewInt(1000)), nil); !errors.Is(err, ErrLPFeeNotSet) {
+			t.Errorf("exact output swap without LP fee returned %v", err)
+		}
+	})
+	t.Run("SetLPFee", func(t *testing.T) {
+		pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, common.Address{})
+		if err := pool.SetLPFee(3000); err != nil {
+			t.Fatal(err)
+		}
+		expectOutput(t, pool)
+		// the fee carries over to the pool after the swap
+		result, err := pool.GetOutputAmount(inputAmount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := result.NewPoolState.GetOutputAmount(inputAmount, nil); err != nil {
+			t.Errorf("swap on the next pool state returned %v", err)
+		}
+	})
+	t.Run("zero fee set", func(t *testing.T) {
+		pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, common.Address{})
+		if err := pool.SetLPFee(0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.GetOutputAmount(inputAmount, nil); err != nil {
+			t.Errorf("swap with a zero LP fee returned %v", err)
+		}
+	})
+	t.Run("LPFeeProvider", func(t *testing.T) {
+		pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, common.Address{})
+		pool.LPFeeProvider = func(*Pool) (int64, error) { return 3000, nil }
+		expectOutput(t, pool)
+	})
+	t.Run("hook override", func(t *testing.T) {
+		// a hook with only the beforeSwap flag
+		hooks := common.HexToAddress("0x0000000000000000000000000000000000000080")
+		pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, hooks)
+		pool.HookSimulator = NeutralHookSimulator{}
+		if _, err := pool.GetOutputAmount(inputAmount, nil); !errors.Is(err, ErrLPFeeNotSet) {
+			t.Errorf("swap through a hook without override returned %v", err)
+		}
+		pool.HookSimulator = overrideFeeSimulator{lpFee: 3000}
+		expectOutput(t, pool)
+	})
+}
+
+func TestSetLPFee(t *testing.T) {
+	if err := testPoolWithLiquidity(t, 3000, common.Address{}).SetLPFee(500); !errors.Is(err, ErrNotDynamicFee) {
+		t.Errorf("setting the fee of a static fee pool returned %v", err)
+	}
+	pool := testPoolWithLiquidity(t, constants.DynamicFeeFlag, common.Address{})
+	for _, lpFee := range []int64{-1, constants.MaxLPFee + 1} {
+		if err := pool.SetLPFee(lpFee); !errors.Is(err, ErrFeeTooHigh) {
+			t.Errorf("fee %d returned %v", lpFee, err)
+		}
+	}
+	if _, err := pool.currentLPFee(); !errors.Is(err, ErrLPFeeNotSet) {
+		t.Errorf("invalid fees set the fee: %v", err)
+	}
+}
+
+func TestNativePool(t *testing.T) {
+	weth := pmTestNative.Wrapped()
+	native := testPoolWithCurrencies(t, pmTestNative, testToken1, 3000, common.Address{})
+	wrapped := testPoolWithCurrencies(t, testToken1, weth, 3000, common.Address{})
+	if native.PoolKey.Currency0 != (common.Address{}) || native.PoolKey.Currency1 != testToken1.Address {
+		t.Errorf("native pool key currencies %s %s", native.PoolKey.Currency0, native.PoolKey.Currency1)
+	}
+	if wrapped.PoolKey.Currency1 != weth.Address {
+		t.Errorf("wrapped pool key currency1 %s", wrapped.PoolKey.Currency1)
+	}
+	if native.PoolId == wrapped.PoolId {
+		t.Error("ETH and WETH pools have the same id")
+	}
+	if native.InvolvesCurrency(weth) {
+		t.Error("native pool involves WETH")
+	}
+
+	// the native pool quotes like the same pool of tokens, in the native currency
+	tokens := testPoolWithCurrencies(t, testToken0, testToken1, 3000, common.Address{})
+	output, err := native.GetOutputAmount(core.FromRawAmount(pmTestNative, big.NewInt(1000000)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokensOutput, err := tokens.GetOutputAmount(core.FromRawAmount(testToken0, big.NewInt(1000000)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !output.ReturnedAmount.Currency.Equal(testToken1) || output.ReturnedAmount.Quotient().Cmp(tokensOutput.ReturnedAmount.Quotient()) != 0 {
+		t.Errorf("native input returned %s %s, want %s", output.ReturnedAmount.Quotient(), output.ReturnedAmount.Currency.Symbol(), tokensOutput.ReturnedAmount.Quotient())
+	}
+	output, err = native.GetOutputAmount(core.FromRawAmount(testToken1, big.NewInt(1000000)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !output.ReturnedAmount.Currency.IsNative() {
+		t.Errorf("output in %s, want ETH", output.ReturnedAmount.Currency.Symbol())
+	}
+	input, err := native.GetInputAmount(core.FromRawAmount(pmTestNative, big.NewInt(1000000)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !input.ReturnedAmount.Currency.Equal(testToken1) {
+		t.Errorf("ETH output needs %s", input.ReturnedAmount.Currency.Symbol())
+	}
+	if _, err := native.GetOutputAmount(core.FromRawAmount(weth, big.NewInt(1000000)), nil); !errors.Is(err, ErrTokenNotInvolved) {
+		t.Errorf("WETH input on the native pool returned %v", err)
+	}
+}
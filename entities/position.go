@@ -0,0 +1,332 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+)
+
+var (
+	ErrTickOrder = errors.New("tickLower must be less than tickUpper")
+	ErrTickLower = errors.New("tickLower out of range or not a multiple of tick spacing")
+	ErrTickUpper = errors.New("tickUpper out of range or not a multiple of tick spacing")
+)
+
+// Position represents a liquidity position on a specific v4 pool
+type Position struct {
+	Pool      *Pool
+	TickLower int
+	TickUpper int
+	Liquidity *big.Int
+
+	// cached values
+	token0Amount *core.CurrencyAmount
+	token1Amount *core.CurrencyAmount
+	mintAmounts  *PositionAmounts
+}
+
+// PositionAmounts is the pair of raw amounts of currency0 and currency1 of a position
+type PositionAmounts struct {
+	Amount0 *big.Int
+	Amount1 *big.Int
+}
+
+/**
+ * Constructs a position for a given pool with the given liquidity
+ * @param pool For which pool the liquidity is assigned
+ * @param liquidity The amount of liquidity that is in the position
+ * @param tickLower The lower tick of the position
+ * @param tickUpper The upper tick of the position
+ */
+func NewPosition(pool *Pool, liquidity *big.Int, tickLower int, tickUpper int) (*Position, error) {
+	if tickLower >= tickUpper {
+		return nil, ErrTickOrder
+	}
+	if tickLower < utils.MinTick || tickLower%int(pool.TickSpacing) != 0 {
+		return nil, ErrTickLower
+	}
+	if tickUpper > utils.MaxTick || tickUpper%int(pool.TickSpacing) != 0 {
+		return nil, ErrTickUpper
+	}
+
+	return &Position{
+		Pool:      pool,
+		Liquidity: liquidity,
+		TickLower: tickLower,
+		TickUpper: tickUpper,
+	}, nil
+}
+
+// Token0PriceLower returns the price of token0 at the lower tick
+func (p *Position) Token0PriceLower() (*core.Price, error) {
+	return utils.TickToPrice(p.Pool.Currency0, p.Pool.Currency1, p.TickLower)
+}
+
+// Token0PriceUpper returns the price of token0 at the upper tick
+func (p *Position) Token0PriceUpper() (*core.Price, error) {
+	return utils.TickToPrice(p.Pool.Currency0, p.Pool.Currency1, p.TickUpper)
+}
+
+// Amount0 returns the amount of token0 that this position's liquidity could be burned for at the current pool price
+func (p *Position) Amount0() (*core.CurrencyAmount, error) {
+	if p.token0Amount != nil {
+		return p.token0Amount, nil
+	}
+	sqrtRatioLower, sqrtRatioUpper, err := p.sqrtRatios()
+	if err != nil {
+		return nil, err
+	}
+
+	amount := constants.Zero
+	if p.Pool.TickCurrent < p.TickLower {
+		amount, err = utils.GetAmount0Delta(sqrtRatioLower, sqrtRatioUpper, p.Liquidity, false)
+	} else if p.Pool.TickCurrent < p.TickUpper {
+		amount, err = utils.GetAmount0Delta(p.Pool.SqrtRatioX96, sqrtRatioUpper, p.Liquidity, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.token0Amount = core.FromRawAmount(p.Pool.Currency0, amount)
+	return p.token0Amount, nil
+}
+
+// Amount1 returns the amount of token1 that this position's liquidity could be burned for at the current pool price
+func (p *Position) Amount1() (*core.CurrencyAmount, error) {
+	if p.token1Amount != nil {
+		return p.token1Amount, nil
+	}
+	sqrtRatioLower, sqrtRatioUpper, err := p.sqrtRatios()
+	if err != nil {
+		return nil, err
+	}
+
+	amount := constants.Zero
+	if p.Pool.TickCurrent >= p.TickUpper {
+		amount, err = utils.GetAmount1Delta(sqrtRatioLower, sqrtRatioUpper, p.Liquidity, false)
+	} else if p.Pool.TickCurrent >= p.TickLower {
+		amount, err = utils.GetAmount1Delta(sqrtRatioLower, p.Pool.SqrtRatioX96, p.Liquidity, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.token1Amount = core.FromRawAmount(p.Pool.Currency1, amount)
+	return p.token1Amount, nil
+}
+
+/**
+ * Returns the lower and upper sqrt ratios if the price 'slips' up to slippage tolerance percentage
+ * @param slippageTolerance The amount by which the price can 'slip' before the transaction will revert
+ * @returns The sqrt ratios after slippage
+ */
+func (p *Position) ratiosAfterSlippage(slippageTolerance *core.Percent) (sqrtRatioX96Lower, sqrtRatioX96Upper *big.Int) {
+	one := core.NewPercent(big.NewInt(1), big.NewInt(1))
+	token0Price := p.Pool.Token0Price().Fraction
+	priceLower := token0Price.Multiply(one.Subtract(slippageTolerance).Fraction)
+	priceUpper := token0Price.Multiply(slippageTolerance.Add(one).Fraction)
+
+	sqrtRatioX96Lower = utils.EncodeSqrtRatioX96(priceLower.Numerator, priceLower.Denominator)
+	if sqrtRatioX96Lower.Cmp(utils.MinSqrtRatio) <= 0 {
+		sqrtRatioX96Lower = new(big.Int).Add(utils.MinSqrtRatio, constants.One)
+	}
+	sqrtRatioX96Upper = utils.EncodeSqrtRatioX96(priceUpper.Numerator, priceUpper.Denominator)
+	if sqrtRatioX96Upper.Cmp(utils.MaxSqrtRatio) >= 0 {
+		sqrtRatioX96Upper = new(big.Int).Sub(utils.MaxSqrtRatio, constants.One)
+	}
+	return sqrtRatioX96Lower, sqrtRatioX96Upper
+}
+
+// slippagePools returns liquidity-less copies of the position's pool priced at the lower and upper slippage bounds
+func (p *Position) slippagePools(slippageTolerance *core.Percent) (poolLower, poolUpper *Pool, err error) {
+	sqrtRatioX96Lower, sqrtRatioX96Upper := p.ratiosAfterSlippage(slippageTolerance)
+
+	tickLower, err := utils.GetTickAtSqrtRatio(sqrtRatioX96Lower)
+	if err != nil {
+		return nil, nil, err
+	}
+	poolLower, err = NewPool(p.Pool.Currency0, p.Pool.Currency1, p.Pool.Fee, p.Pool.TickSpacing, p.Pool.Hooks,
+		sqrtRatioX96Lower, big.NewInt(0), tickLower, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tickUpper, err := utils.GetTickAtSqrtRatio(sqrtRatioX96Upper)
+	if err != nil {
+		return nil, nil, err
+	}
+	poolUpper, err = NewPool(p.Pool.Currency0, p.Pool.Currency1, p.Pool.Fee, p.Pool.TickSpacing, p.Pool.Hooks,
+		sqrtRatioX96Upper, big.NewInt(0), tickUpper, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return poolLower, poolUpper, nil
+}
+
+/**
+ * Returns the maximum amounts that must be sent in order to safely mint the amount of liquidity held by the position
+ * with the given slippage tolerance
+ * @param slippageTolerance Tolerance of unfavorable slippage from the current price
+ * @returns The amounts, with slippage
+ */
+func (p *Position) MintAmountsWithSlippage(slippageTolerance *core.Percent) (*PositionAmounts, error) {
+	poolLower, poolUpper, err := p.slippagePools(slippageTolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: slippage derivation in v4 is different from v3, minting and increasing are bounded by the MAXIMUM
+	// amounts of token0 and token1, computed with the precise liquidity of the position.
+	// We want the larger amounts, which occurs at the upper price for amount1...
+	upperPosition, err := NewPosition(poolUpper, p.Liquidity, p.TickLower, p.TickUpper)
+	if err != nil {
+		return nil, err
+	}
+	upperAmounts, err := upperPosition.MintAmounts()
+	if err != nil {
+		return nil, err
+	}
+	// ...and the lower for amount0
+	lowerPosition, err := NewPosition(poolLower, p.Liquidity, p.TickLower, p.TickUpper)
+	if err != nil {
+		return nil, err
+	}
+	lowerAmounts, err := lowerPosition.MintAmounts()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PositionAmounts{Amount0: lowerAmounts.Amount0, Amount1: upperAmounts.Amount1}, nil
+}
+
+/**
+ * Returns the minimum amounts that should be requested in order to safely burn the amount of liquidity held by the
+ * position with the given slippage tolerance
+ * @param slippageTolerance tolerance of unfavorable slippage from the current price
+ * @returns The amounts, with slippage
+ */
+func (p *Position) BurnAmountsWithSlippage(slippageTolerance *core.Percent) (*PositionAmounts, error) {
+	poolLower, poolUpper, err := p.slippagePools(slippageTolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	// we want the smaller amounts...
+	// ...which occurs at the upper price for amount0...
+	upperPosition, err := NewPosition(poolUpper, p.Liquidity, p.TickLower, p.TickUpper)
+	if err != nil {
+		return nil, err
+	}
+	amount0, err := upperPosition.Amount0()
+	if err != nil {
+		return nil, err
+	}
+	// ...and the lower for amount1
+	lowerPosition, err := NewPosition(poolLower, p.Liquidity, p.TickLower, p.TickUpper)
+	if err != nil {
+		return nil, err
+	}
+	amount1, err := lowerPosition.Amount1()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PositionAmounts{Amount0: amount0.Quotient(), Amount1: amount1.Quotient()}, nil
+}
+
+/**
+ * Returns the minimum amounts that must be sent in order to mint the amount of liquidity held by the position at
+ * the current price for the pool
+ */
+func (p *Position) MintAmounts() (*PositionAmounts, error) {
+	if p.mintAmounts != nil {
+		return p.mintAmounts, nil
+	}
+	sqrtRatioLower, sqrtRatioUpper, err := p.sqrtRatios()
+	if err != nil {
+		return nil, err
+	}
+
+	amount0, amount1 := constants.Zero, constants.Zero
+	if p.Pool.TickCurrent < p.TickLower {
+		amount0, err = utils.GetAmount0Delta(sqrtRatioLower, sqrtRatioUpper, p.Liquidity, true)
+	} else if p.Pool.TickCurrent < p.TickUpper {
+		amount0, err = utils.GetAmount0Delta(p.Pool.SqrtRatioX96, sqrtRatioUpper, p.Liquidity, true)
+		if err != nil {
+			return nil, err
+		}
+		amount1, err = utils.GetAmount1Delta(sqrtRatioLower, p.Pool.SqrtRatioX96, p.Liquidity, true)
+	} else {
+		amount1, err = utils.GetAmount1Delta(sqrtRatioLower, sqrtRatioUpper, p.Liquidity, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.mintAmounts = &PositionAmounts{Amount0: amount0, Amount1: amount1}
+	return p.mintAmounts, nil
+}
+
+func (p *Position) sqrtRatios() (sqrtRatioLower, sqrtRatioUpper *big.Int, err error) {
+	sqrtRatioLower, err = utils.GetSqrtRatioAtTick(p.TickLower)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqrtRatioUpper, err = utils.GetSqrtRatioAtTick(p.TickUpper)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sqrtRatioLower, sqrtRatioUpper, nil
+}
+
+/**
+ * Computes the maximum amount of liquidity received for a given amount of token0, token1,
+ * and the prices at the tick boundaries.
+ * @param pool The pool for which the position should be created
+ * @param tickLower The lower tick of the position
+ * @param tickUpper The upper tick of the position
+ * @param amount0 token0 amount
+ * @param amount1 token1 amount
+ * @param useFullPrecision If false, liquidity will be maximized according to what the router can calculate,
+ * not what core can theoretically support
+ * @returns The amount of liquidity for the position
+ */
+func FromAmounts(pool *Pool, tickLower, tickUpper int, amount0, amount1 *big.Int, useFullPrecision bool) (*Position, error) {
+	sqrtRatioAX96, err := utils.GetSqrtRatioAtTick(tickLower)
+	if err != nil {
+		return nil, err
+	}
+	sqrtRatioBX96, err := utils.GetSqrtRatioAtTick(tickUpper)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := utils.MaxLiquidityForAmounts(pool.SqrtRatioX96, sqrtRatioAX96, sqrtRatioBX96, amount0, amount1, useFullPrecision)
+	return NewPosition(pool, liquidity, tickLower, tickUpper)
+}
+
+/**
+ * Computes a position with the maximum amount of liquidity received for a given amount of token0, assuming an unlimited amount of token1
+ * @param pool The pool for which the position is created
+ * @param tickLower The lower tick
+ * @param tickUpper The upper tick
+ * @param amount0 The desired amount of token0
+ * @param useFullPrecision If true, liquidity will be maximized according to what the router can calculate,
+ * not what core can theoretically support
+ * @returns The position
+ */
+func FromAmount0(pool *Pool, tickLower, tickUpper int, amount0 *big.Int, useFullPrecision bool) (*Position, error) {
+	return FromAmounts(pool, tickLower, tickUpper, amount0, core.MaxUint256, useFullPrecision)
+}
+
+/**
+ * Computes a position with the maximum amount of liquidity received for a given amount of token1, assuming an unlimited amount of token0
+ * @param pool The pool for which the position is created
+ * @param tickLower The lower tick
+ * @param tickUpper The upper tick
+ * @param amount1 The desired amount of token1
+ * @returns The position
+ */
+func FromAmount1(pool *Pool, tickLower, tickUpper int, amount1 *big.Int) (*Position, error) {
+	// this function always uses full precision
+	return FromAmounts(pool, tickLower, tickUpper, core.MaxUint256, amount1, true)
+}
@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+)
+
+var (
+	ErrInvalidPositionInfo  = errors.New("invalid position info")
+	ErrPositionPoolMismatch = errors.New("position info does not belong to the pool")
+)
+
+/**
+ * PositionInfo is the word the PositionManager packs the pool and range of a position into, from the most
+ * significant bits: 200 bits of the pool id, 24 bits tickUpper, 24 bits tickLower and 8 bits hasSubscriber.
+ */
+type PositionInfo struct {
+	// PoolId holds the first 25 bytes of the id of the pool of the position
+	PoolId        [25]byte
+	TickUpper     int
+	TickLower     int
+	HasSubscriber bool
+}
+
+// NewPositionInfo returns the info of a position without a subscriber, as the PositionManager initializes it on mint
+func NewPositionInfo(poolId PoolId, tickLower, tickUpper int) PositionInfo {
+	info := PositionInfo{TickLower: tickLower, TickUpper: tickUpper}
+	copy(info.PoolId[:], poolId[:25])
+	return info
+}
+
+// MatchesPoolId returns true if the truncated pool id of the info is the one of the pool id
+func (i PositionInfo) MatchesPoolId(poolId PoolId) bool {
+	return [25]byte(poolId[:25]) == i.PoolId
+}
+
+/**
+ * Unpacks the position info returned by positionInfo and getPoolAndPositionInfo of the PositionManager
+ * @param info The packed uint256
+ */
+func DecodePositionInfo(info *big.Int) (PositionInfo, error) {
+	if info == nil || info.Sign() < 0 || info.BitLen() > 256 {
+		return PositionInfo{}, ErrInvalidPositionInfo
+	}
+	var word [32]byte
+	info.FillBytes(word[:])
+
+	var decoded PositionInfo
+	copy(decoded.PoolId[:], word[:25])
+	decoded.TickUpper = int24(word[25:28])
+	decoded.TickLower = int24(word[28:31])
+	decoded.HasSubscriber = word[31]&1 != 0
+	return decoded, nil
+}
+
+// EncodePositionInfo packs the position info into the uint256 word of the PositionManager
+func EncodePositionInfo(info PositionInfo) (*big.Int, error) {
+	if info.TickLower < utils.MinTick || info.TickLower > utils.MaxTick || info.TickUpper < utils.MinTick || info.TickUpper > utils.MaxTick {
+		return nil, ErrTickOutOfRange
+	}
+	var word [32]byte
+	copy(word[:25], info.PoolId[:])
+	putInt24(word[25:28], info.TickUpper)
+	putInt24(word[28:31], info.TickLower)
+	if info.HasSubscriber {
+		word[31] = 1
+	}
+	return new(big.Int).SetBytes(word[:]), nil
+}
+
+// int24 sign extends the big endian int24 in b
+func int24(b []byte) int {
+	v := int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+	if v&0x800000 != 0 {
+		v -= 1 << 24
+	}
+	return v
+}
+
+// PositionDetails is the state of a PositionManager position as read through its view functions
+type PositionDetails struct {
+	TokenId       *big.Int
+	PoolKey       PoolKey
+	PoolId        PoolId
+	TickLower     int
+	TickUpper     int
+	HasSubscriber bool
+	Liquidity     *big.Int
+}
+
+/**
+ * Combines the results of getPoolAndPositionInfo and getPositionLiquidity into the details of the position
+ * @param tokenId The id of the position
+ * @param poolKey The pool key returned by getPoolAndPositionInfo
+ * @param info The decoded position info returned by getPoolAndPositionInfo or positionInfo
+ * @param liquidity The liquidity returned by getPositionLiquidity
+ */
+func NewPositionDetails(tokenId *big.Int, poolKey PoolKey, info PositionInfo, liquidity *big.Int) (*PositionDetails, error) {
+	poolId, err := poolKey.ToId()
+	if err != nil {
+		return nil, err
+	}
+	if !info.MatchesPoolId(poolId) {
+		return nil, ErrPositionPoolMismatch
+	}
+	return &PositionDetails{
+		TokenId:       tokenId,
+		PoolKey:       poolKey,
+		PoolId:        poolId,
+		TickLower:     info.TickLower,
+		TickUpper:     info.TickUpper,
+		HasSubscriber: info.HasSubscriber,
+		Liquidity:     liquidity,
+	}, nil
+}
+
+/**
+ * Returns the position in the pool, e.g. to compute its amounts at the current price
+ * @param pool The pool of the position at the price of interest
+ */
+func (d *PositionDetails) Position(pool *Pool) (*Position, error) {
+	if pool.PoolId != d.PoolId {
+		return nil, ErrPositionPoolMismatch
+	}
+	return NewPosition(pool, d.Liquidity, d.TickLower, d.TickUpper)
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPositionInfoPacking(t *testing.T) {
+	poolId := PoolId(common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"))
+	// the first 25 bytes of the pool id
+	prefix := "21c67e77068de97969ba93d4aab21826d33ca12bb9f565d849"
+	tests := []struct {
+		name          string
+		tickLower     int
+		tickUpper     int
+		hasSubscriber bool
+		// tickUpper, tickLower and hasSubscriber after the pool id prefix
+		packed string
+	}{
+		{"positive", 60, 120, false, "000078" + "00003c" + "00"},
+		{"negative", -120, -60, true, "ffffc4" + "ffff88" + "01"},
+		{"around zero", -887220, 887220, false, "0d89b4" + "f2764c" + "00"},
+		{"full range", utils.MinTick, utils.MaxTick, true, "0d89e8" + "f27618" + "01"},
+		{"empty", 0, 0, false, "000000" + "000000" + "00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := NewPositionInfo(poolId, tt.tickLower, tt.tickUpper)
+			info.HasSubscriber = tt.hasSubscriber
+			packed, err := EncodePositionInfo(info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := new(big.Int).SetString(prefix+tt.packed, 16)
+			if packed.Cmp(want) != 0 {
+				t.Errorf("packed %064x, want %064x", packed, want)
+			}
+
+			decoded, err := DecodePositionInfo(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded != info {
+				t.Errorf("decoded as %+v, want %+v", decoded, info)
+			}
+			if !decoded.MatchesPoolId(poolId) {
+				t.Error("decoded info does not match its pool id")
+			}
+		})
+	}
+}
+
+func TestDecodePositionInfo(t *testing.T) {
+	// only the lowest bit of the last byte is the subscriber flag
+	info, err := DecodePositionInfo(big.NewInt(0xfe))
+	if err != nil || info.HasSubscriber {
+		t.Errorf("decoded as %+v, %v", info, err)
+	}
+	// a position info with a pool id prefix of zeros
+	info, err = DecodePositionInfo(new(big.Int).SetBytes(common.FromHex("fffda8" + "000258" + "01")))
+	if err != nil || info != (PositionInfo{TickUpper: -600, TickLower: 600, HasSubscriber: true}) {
+		t.Errorf("decoded as %+v, %v", info, err)
+	}
+
+	for _, invalid := range []*big.Int{nil, big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if _, err := DecodePositionInfo(invalid); !errors.Is(err, ErrInvalidPositionInfo) {
+			t.Errorf("%v returned %v", invalid, err)
+		}
+	}
+}
+
+func TestEncodePositionInfoOutOfRange(t *testing.T) {
+	for _, ticks := range [][2]int{{utils.MinTick - 1, 0}, {0, utils.MaxTick + 1}, {-1 << 23, 0}, {0, 1 << 23}} {
+		if _, err := EncodePositionInfo(PositionInfo{TickLower: ticks[0], TickUpper: ticks[1]}); !errors.Is(err, ErrTickOutOfRange) {
+			t.Errorf("ticks %v returned %v", ticks, err)
+		}
+	}
+}
+
+func TestNewPositionDetails(t *testing.T) {
+	poolId, err := parserTestKey.ToId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := NewPositionInfo(poolId, -600, 600)
+	details, err := NewPositionDetails(big.NewInt(7), parserTestKey, info, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.PoolId != poolId || details.TickLower != -600 || details.TickUpper != 600 || details.HasSubscriber {
+		t.Errorf("details %+v", details)
+	}
+
+	// the info of a position in another pool
+	otherKey := parserTestKey
+	otherKey.Fee = 500
+	if _, err := NewPositionDetails(big.NewInt(7), otherKey, info, big.NewInt(1000)); !errors.Is(err, ErrPositionPoolMismatch) {
+		t.Errorf("returned %v", err)
+	}
+	// only the prefix of the pool id is compared
+	info.PoolId[24] ^= 1
+	if info.MatchesPoolId(poolId) {
+		t.Error("info with another pool id prefix matches")
+	}
+	poolId[25] ^= 1
+	if !NewPositionInfo(poolId, 0, 60).MatchesPoolId(poolId) {
+		t.Error("info does not match its pool id")
+	}
+}
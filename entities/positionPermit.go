@@ -0,0 +1,169 @@
+package entities
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	nftPermitTypes = map[string][]TypedDataField{
+		"Permit": {
+			{"spender", "address"},
+			{"tokenId", "uint256"},
+			{"nonce", "uint256"},
+			{"deadline", "uint256"},
+		},
+	}
+	nftPermitForAllTypes = map[string][]TypedDataField{
+		"PermitForAll": {
+			{"operator", "address"},
+			{"approved", "bool"},
+			{"nonce", "uint256"},
+			{"deadline", "uint256"},
+		},
+	}
+)
+
+var (
+	pmNonces          = positionManagerMethod("nonces(address,uint256)")
+	pmRevokeNonce     = positionManagerMethod("revokeNonce(uint256)")
+	pmDomainSeparator = positionManagerMethod("DOMAIN_SEPARATOR()")
+)
+
+// NFTPermitValues is the ERC721 permit of the position manager approving spender for one position
+type NFTPermitValues struct {
+	Spender common.Address
+	TokenId *big.Int
+	// The timestamp after which the signature is no longer valid
+	Deadline *big.Int
+	// An unordered nonce of the owner, see NonceBitmapPosition
+	Nonce *big.Int
+}
+
+// NFTPermitOptions is a permit signed by the owner of the position
+type NFTPermitOptions struct {
+	NFTPermitValues
+	Signature []byte
+}
+
+// NFTPermitForAllValues is the permitForAll of the position manager setting the approval of operator for all positions
+type NFTPermitForAllValues struct {
+	Operator common.Address
+	Approved bool
+	Deadline *big.Int
+	Nonce    *big.Int
+}
+
+// NFTPermitForAllOptions is a permitForAll signed by owner
+type NFTPermitForAllOptions struct {
+	NFTPermitForAllValues
+	Owner     common.Address
+	Signature []byte
+}
+
+/**
+ * Returns the EIP-712 domain of the position manager, which has no version
+ * @param positionManagerAddress The address of the position manager
+ * @param chainId The id of the chain
+ */
+func PositionManagerDomain(positionManagerAddress common.Address, chainId *big.Int) TypedDataDomain {
+	return TypedDataDomain{Name: "Uniswap v4 Positions NFT", ChainId: chainId, VerifyingContract: positionManagerAddress}
+}
+
+// NFTPermitTypedData returns the typed data the owner of the position signs to permit the approval
+func NFTPermitTypedData(permit NFTPermitValues, positionManagerAddress common.Address, chainId *big.Int) *TypedData {
+	return &TypedData{
+		Types:       nftPermitTypes,
+		PrimaryType: "Permit",
+		Domain:      PositionManagerDomain(positionManagerAddress, chainId),
+		Message: map[string]interface{}{
+			"spender":  permit.Spender,
+			"tokenId":  permit.TokenId,
+			"nonce":    permit.Nonce,
+			"deadline": permit.Deadline,
+		},
+	}
+}
+
+// NFTPermitForAllTypedData returns the typed data the owner signs to set the approval of the operator
+func NFTPermitForAllTypedData(permit NFTPermitForAllValues, positionManagerAddress common.Address, chainId *big.Int) *TypedData {
+	return &TypedData{
+		Types:       nftPermitForAllTypes,
+		PrimaryType: "PermitForAll",
+		Domain:      PositionManagerDomain(positionManagerAddress, chainId),
+		Message: map[string]interface{}{
+			"operator": permit.Operator,
+			"approved": permit.Approved,
+			"nonce":    permit.Nonce,
+			"deadline": permit.Deadline,
+		},
+	}
+}
+
+/**
+ * Signs the permit and returns it ready for EncodeNFTPermit or RemoveLiquidityOptions.Permit
+ * @param permit The permit
+ * @param positionManagerAddress The address of the position manager
+ * @param chainId The id of the chain
+ * @param signer The owner of the position
+ */
+func SignNFTPermit(permit NFTPermitValues, positionManagerAddress common.Address, chainId *big.Int, signer Signer) (*NFTPermitOptions, error) {
+	signature, err := signer.SignTypedData(NFTPermitTypedData(permit, positionManagerAddress, chainId))
+	if err != nil {
+		return nil, err
+	}
+	return &NFTPermitOptions{NFTPermitValues: permit, Signature: signature}, nil
+}
+
+// SignNFTPermitForAll signs the permitForAll with the owner of the positions and returns it ready for EncodeNFTPermitForAll
+func SignNFTPermitForAll(permit NFTPermitForAllValues, positionManagerAddress common.Address, chainId *big.Int, signer Signer) (*NFTPermitForAllOptions, error) {
+	signature, err := signer.SignTypedData(NFTPermitForAllTypedData(permit, positionManagerAddress, chainId))
+	if err != nil {
+		return nil, err
+	}
+	return &NFTPermitForAllOptions{NFTPermitForAllValues: permit, Owner: signer.Address(), Signature: signature}, nil
+}
+
+// EncodeNFTPermit encodes the permit call of a signed permit, see EncodeERC721Permit
+func EncodeNFTPermit(permit *NFTPermitOptions) ([]byte, error) {
+	return EncodeERC721Permit(permit.Spender, permit.TokenId, permit.Deadline, permit.Nonce, permit.Signature)
+}
+
+// EncodeNFTPermitForAll encodes the permitForAll call of a signed permitForAll, see EncodePermitForAll
+func EncodeNFTPermitForAll(permit *NFTPermitForAllOptions) ([]byte, error) {
+	return EncodePermitForAll(permit.Owner, permit.Operator, permit.Approved, permit.Deadline, permit.Nonce, permit.Signature)
+}
+
+// EncodeNonces encodes the nonces view of the position manager for a word of the unordered nonces of the owner
+func EncodeNonces(owner common.Address, wordPos *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmNonces, owner, wordPos)
+}
+
+// DecodeNoncesResult returns the bitmap of the used nonces of the word, see NextUnusedNonce
+func DecodeNoncesResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(pmNonces, data)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := r.bigInt(0)
+	return bitmap, r.err
+}
+
+// EncodeRevokeNonce encodes marking an unordered nonce of the sender as used, invalidating the permits signed with it
+func EncodeRevokeNonce(nonce *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmRevokeNonce, nonce)
+}
+
+func EncodeDomainSeparator() ([]byte, error) {
+	return encodeMethodCall(pmDomainSeparator)
+}
+
+func DecodeDomainSeparatorResult(data []byte) (common.Hash, error) {
+	r, err := unpackResult(pmDomainSeparator, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	separator := r.hash(0)
+	return separator, r.err
+}
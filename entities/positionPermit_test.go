@@ -0,0 +1,258 @@
+package entities
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// the PositionManager of mainnet
+var testPositionManager = common.HexToAddress("0xbD216513d74C8cf14cf4747E6AaA6420FF64ee9e")
+
+// positionManagerGethTypedData returns the typed data of the position manager in the form of go-ethereum
+func positionManagerGethTypedData(primaryType string, fields []apitypes.Type, message apitypes.TypedDataMessage, chainId int64) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			primaryType: fields,
+		},
+		PrimaryType: primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Uniswap v4 Positions NFT",
+			ChainId:           math.NewHexOrDecimal256(chainId),
+			VerifyingContract: testPositionManager.Hex(),
+		},
+		Message: message,
+	}
+}
+
+func TestNFTPermitTypeHashes(t *testing.T) {
+	// PERMIT_TYPEHASH and PERMIT_FOR_ALL_TYPEHASH of ERC721Permit_v4
+	tests := []struct {
+		types       map[string][]TypedDataField
+		primaryType string
+		want        string
+	}{
+		{nftPermitTypes, "Permit", "0x49ecf333e5b8c95c40fdafc95c1ad136e8914a8fb55e9dc8bb01eaa83a2df9ad"},
+		{nftPermitForAllTypes, "PermitForAll", "0x6673cb397ee2a50b6b8401653d3638b4ac8b3db9c28aa6870ffceb7574ec2f76"},
+	}
+	for _, tt := range tests {
+		encoded := typedDataTypes(tt.types).encodeType(tt.primaryType)
+		if typeHash := crypto.Keccak256Hash([]byte(encoded)); typeHash.Hex() != tt.want {
+			t.Errorf("%s: type hash of %q is %s, want %s", tt.primaryType, encoded, typeHash.Hex(), tt.want)
+		}
+	}
+}
+
+func TestNFTPermitDigestMatchesGeth(t *testing.T) {
+	permit := NFTPermitValues{Spender: testSpender, TokenId: big.NewInt(11020), Deadline: big.NewInt(1700003600), Nonce: new(big.Int).Lsh(big.NewInt(1), 200)}
+	data := NFTPermitTypedData(permit, testPositionManager, big.NewInt(1))
+	want := gethDigest(t, positionManagerGethTypedData("Permit", []apitypes.Type{
+		{Name: "spender", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}, apitypes.TypedDataMessage{
+		"spender":  permit.Spender.Hex(),
+		"tokenId":  permit.TokenId.String(),
+		"nonce":    permit.Nonce.String(),
+		"deadline": permit.Deadline.String(),
+	}, 1))
+	expectDigest(t, data, want)
+	expectJSONDigest(t, data)
+
+	// the domain has no version
+	gethDomain := positionManagerGethTypedData("Permit", nil, nil, 1)
+	wantSeparator, err := gethDomain.HashStruct("EIP712Domain", gethDomain.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	separator, err := data.DomainSeparator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if separator != common.BytesToHash(wantSeparator) {
+		t.Errorf("domain separator %s, want %x", separator, wantSeparator)
+	}
+}
+
+func TestNFTPermitForAllDigestMatchesGeth(t *testing.T) {
+	permit := NFTPermitForAllValues{Operator: testSpender, Approved: true, Deadline: big.NewInt(1700003600), Nonce: new(big.Int).Lsh(big.NewInt(1), 200)}
+	data := NFTPermitForAllTypedData(permit, testPositionManager, big.NewInt(130))
+	want := gethDigest(t, positionManagerGethTypedData("PermitForAll", []apitypes.Type{
+		{Name: "operator", Type: "address"},
+		{Name: "approved", Type: "bool"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}, apitypes.TypedDataMessage{
+		"operator": permit.Operator.Hex(),
+		"approved": permit.Approved,
+		"nonce":    permit.Nonce.String(),
+		"deadline": permit.Deadline.String(),
+	}, 130))
+	expectDigest(t, data, want)
+	expectJSONDigest(t, data)
+}
+
+func TestSignNFTPermit(t *testing.T) {
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewPrivateKeySigner(key)
+	chainId := big.NewInt(1)
+
+	permit, err := SignNFTPermit(NFTPermitValues{Spender: testSpender, TokenId: big.NewInt(7), Deadline: big.NewInt(1), Nonce: big.NewInt(2)},
+		testPositionManager, chainId, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := RecoverTypedDataSigner(NFTPermitTypedData(permit.NFTPermitValues, testPositionManager, chainId), permit.Signature)
+	if err != nil || recovered != signer.Address() {
+		t.Errorf("permit recovered %s, %v", recovered, err)
+	}
+	// the signature of a permit of another position does not recover to the owner
+	other := permit.NFTPermitValues
+	other.TokenId = big.NewInt(8)
+	if recovered, err := RecoverTypedDataSigner(NFTPermitTypedData(other, testPositionManager, chainId), permit.Signature); err == nil && recovered == signer.Address() {
+		t.Error("signature recovered for another position")
+	}
+
+	forAll, err := SignNFTPermitForAll(NFTPermitForAllValues{Operator: testSpender, Approved: true, Deadline: big.NewInt(1), Nonce: big.NewInt(2)},
+		testPositionManager, chainId, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forAll.Owner != signer.Address() {
+		t.Errorf("owner %s", forAll.Owner)
+	}
+	recovered, err = RecoverTypedDataSigner(NFTPermitForAllTypedData(forAll.NFTPermitForAllValues, testPositionManager, chainId), forAll.Signature)
+	if err != nil || recovered != signer.Address() {
+		t.Errorf("permitForAll recovered %s, %v", recovered, err)
+	}
+
+	failing := errors.New("rejected")
+	if _, err := SignNFTPermit(permit.NFTPermitValues, testPositionManager, chainId, failingSigner{failing}); !errors.Is(err, failing) {
+		t.Errorf("failing signer returned %v", err)
+	}
+}
+
+// failingSigner rejects every signature request, as a wallet whose user declines
+type failingSigner struct {
+	err error
+}
+
+func (s failingSigner) Address() common.Address {
+	return common.Address{}
+}
+
+func (s failingSigner) SignTypedData(*TypedData) ([]byte, error) {
+	return nil, s.err
+}
+
+func TestEncodeNFTPermit(t *testing.T) {
+	// r = 1, s = 2, v = 27
+	signature := common.FromHex("0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" + "1b")
+	var (
+		spenderWord   = "00000000000000000000000066a9893cc07d91d95644aedd05d03f95e1dba8af"
+		signatureWord = "0000000000000000000000000000000000000000000000000000000000000041" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"1b00000000000000000000000000000000000000000000000000000000000000"
+	)
+
+	calldata, err := EncodeNFTPermit(&NFTPermitOptions{
+		NFTPermitValues: NFTPermitValues{Spender: testSpender, TokenId: big.NewInt(7), Deadline: big.NewInt(1700003600), Nonce: big.NewInt(2)},
+		Signature:       signature,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// permit(spender, tokenId, deadline, nonce, signature)
+	want := "0f5730f1" + spenderWord +
+		"0000000000000000000000000000000000000000000000000000000000000007" +
+		"000000000000000000000000000000000000000000000000000000006553ff10" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		signatureWord
+	if hex.EncodeToString(calldata) != want {
+		t.Errorf("permit calldata %x\nwant %s", calldata, want)
+	}
+
+	calldata, err = EncodeNFTPermitForAll(&NFTPermitForAllOptions{
+		NFTPermitForAllValues: NFTPermitForAllValues{Operator: testSpender, Approved: true, Deadline: big.NewInt(1700003600), Nonce: big.NewInt(2)},
+		Owner:                 testPositionManager,
+		Signature:             signature,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// permitForAll(owner, operator, approved, deadline, nonce, signature)
+	want = "3aea60f0" +
+		"000000000000000000000000bd216513d74c8cf14cf4747e6aaa6420ff64ee9e" + spenderWord +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"000000000000000000000000000000000000000000000000000000006553ff10" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"00000000000000000000000000000000000000000000000000000000000000c0" +
+		signatureWord
+	if hex.EncodeToString(calldata) != want {
+		t.Errorf("permitForAll calldata %x\nwant %s", calldata, want)
+	}
+}
+
+func TestPositionManagerNonces(t *testing.T) {
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	calldata, err := EncodeNonces(owner, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "502e1a16" +
+		"00000000000000000000000000000000000000000000000000000000000000aa" +
+		"0000000000000000000000000000000000000000000000000000000000000001"
+	if hex.EncodeToString(calldata) != want {
+		t.Errorf("nonces calldata %x", calldata)
+	}
+	// nonce 258 is bit 2 of word 1
+	calldata, err = EncodeRevokeNonce(big.NewInt(258))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(calldata) != "05c1ee20"+"0000000000000000000000000000000000000000000000000000000000000102" {
+		t.Errorf("revokeNonce calldata %x", calldata)
+	}
+	calldata, err = EncodeDomainSeparator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(calldata) != "3644e515" {
+		t.Errorf("DOMAIN_SEPARATOR calldata %x", calldata)
+	}
+
+	bitmap, err := DecodeNoncesResult(abiWords(t, "0000000000000000000000000000000000000000000000000000000000000007"))
+	if err != nil || bitmap.Int64() != 7 {
+		t.Errorf("nonces decoded as %v, %v", bitmap, err)
+	}
+	// the first unused nonce of word 1 with bits 0 to 2 used
+	if nonce, ok := NextUnusedNonce(big.NewInt(1), bitmap); !ok || nonce.Int64() != 259 {
+		t.Errorf("next unused nonce %v, %v", nonce, ok)
+	}
+
+	separator, err := DecodeDomainSeparatorResult(abiWords(t, "21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"))
+	if err != nil || separator != common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27") {
+		t.Errorf("DOMAIN_SEPARATOR decoded as %s, %v", separator, err)
+	}
+	if _, err := DecodeNoncesResult(nil); err == nil {
+		t.Error("empty nonces result decoded")
+	}
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testToken0 = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000001"), 18, "t0", "token0")
+	testToken1 = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000002"), 18, "t1", "token1")
+)
+
+// testPoolAtTick returns a liquidity-less token0/token1 pool with fee 3000 and tick spacing 60 at the price of the tick
+func testPoolAtTick(t testing.TB, tick int) *Pool {
+	t.Helper()
+	sqrtPriceX96, err := utils.GetSqrtRatioAtTick(tick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(testToken0, testToken1, 3000, 60, common.Address{}, sqrtPriceX96, big.NewInt(0), tick, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestMintAmountsWithSlippage(t *testing.T) {
+	pool, err := NewPool(testToken0, testToken1, 3000, 60, common.Address{},
+		utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1)), big.NewInt(0), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	liquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	position, err := NewPosition(pool, liquidity, -600, 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mintAmounts, err := position.MintAmounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mintAmounts.Amount0.String() != "29553010879137170" || mintAmounts.Amount1.String() != "29553010879137170" {
+		t.Fatalf("unexpected mint amounts %s %s", mintAmounts.Amount0, mintAmounts.Amount1)
+	}
+
+	amounts, err := position.MintAmountsWithSlippage(core.NewPercent(big.NewInt(5), big.NewInt(100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// amount0 at the price slipped down by 5%, amount1 at the price slipped up by 5%
+	if amounts.Amount0.String() != "55531362964291266" || amounts.Amount1.String() != "54248087475097009" {
+		t.Fatalf("unexpected maximum amounts %s %s", amounts.Amount0, amounts.Amount1)
+	}
+}
+
+func TestMintAmountsWithSlippageCoverMintAmounts(t *testing.T) {
+	liquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	tests := []struct {
+		name                 string
+		tick                 int
+		tickLower, tickUpper int
+	}{
+		{"in range", 0, -600, 600},
+		{"near lower tick", -540, -600, 600},
+		{"near upper tick", 540, -600, 600},
+		{"range above the price", -1000, -600, 600},
+		{"range below the price", 1000, -600, 600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := NewPosition(testPoolAtTick(t, tt.tick), liquidity, tt.tickLower, tt.tickUpper)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mintAmounts, err := position.MintAmounts()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, slippage := range []int64{0, 1, 50, 500} {
+				maximum, err := position.MintAmountsWithSlippage(core.NewPercent(big.NewInt(slippage), big.NewInt(10000)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if maximum.Amount0.Cmp(mintAmounts.Amount0) < 0 {
+					t.Errorf("slippage %d bips: amount0 max %s below mint amount %s", slippage, maximum.Amount0, mintAmounts.Amount0)
+				}
+				if maximum.Amount1.Cmp(mintAmounts.Amount1) < 0 {
+					t.Errorf("slippage %d bips: amount1 max %s below mint amount %s", slippage, maximum.Amount1, mintAmounts.Amount1)
+				}
+			}
+		})
+	}
+}
@@ -15,10 +15,10 @@ var (
 )
 
 type Route struct {
-	Pools     []*Pool
-	TokenPath []*core.Token
-	Input     core.Currency
-	Output    core.Currency
+	Pools        []*Pool
+	CurrencyPath []core.Currency
+	Input        core.Currency
+	Output       core.Currency
 
 	PathInput  core.Currency
 	PathOutput core.Currency
@@ -37,18 +37,27 @@ func NewRoute(pools []*Pool, input, output core.Currency) (*Route, error) {
 		}
 	}
 
-	pathInput := getPathCurrency(input.Wrapped(), pools[0])
-	pathOutput := getPathCurrency(output.Wrapped(), pools[len(pools)-1])
+	pathInput := getPathCurrency(input, pools[0])
+	if pathInput == nil {
+		return nil, ErrInputNotInvolved
+	}
+	var pathOutput core.Currency
+	if output != nil {
+		pathOutput = getPathCurrency(output, pools[len(pools)-1])
+		if pathOutput == nil {
+			return nil, ErrOutputNotInvolved
+		}
+	}
 
-	currencyPath := []*core.Token{pathInput}
+	currencyPath := []core.Currency{pathInput}
 
 	for i, pool := range pools {
-		currencyInputCurrency := currencyPath[i]
-		if currencyInputCurrency.Equal(pool.Currency0) || currencyInputCurrency.Equal(pool.Currency1) {
+		currentInputCurrency := currencyPath[i]
+		if !currentInputCurrency.Equal(pool.Currency0) && !currentInputCurrency.Equal(pool.Currency1) {
 			return nil, ErrPathNotContinuous
 		}
 		nextCurrency := pool.Currency0
-		if currencyInputCurrency.Equal(pool.Currency0) {
+		if currentInputCurrency.Equal(pool.Currency0) {
 			nextCurrency = pool.Currency1
 		}
 
@@ -57,19 +66,16 @@ func NewRoute(pools []*Pool, input, output core.Currency) (*Route, error) {
 
 	if output == nil {
 		output = currencyPath[len(currencyPath)-1]
-	} else {
-		if !pools[len(pools)-1].InvolvesToken(output.Wrapped()) {
-			return nil, ErrOutputNotInvolved
-		}
+		pathOutput = output
 	}
 
 	return &Route{
-		Pools:      pools,
-		TokenPath:  currencyPath,
-		Input:      input,
-		Output:     output,
-		PathInput:  pathInput,
-		PathOutput: pathOutput,
+		Pools:        pools,
+		CurrencyPath: currencyPath,
+		Input:        input,
+		Output:       output,
+		PathInput:    pathInput,
+		PathOutput:   pathOutput,
 	}, nil
 }
 
@@ -83,10 +89,10 @@ func (r *Route) MidPrice() (*core.Price, error) {
 	}
 
 	var (
-		nextInput *core.Token
+		nextInput core.Currency
 		price     *core.Price
 	)
-	if r.Pools[0].Currency0.Equal(r.Input) {
+	if r.PathInput.Equal(r.Pools[0].Currency0) {
 		nextInput = r.Pools[0].Currency1
 		price = r.Pools[0].Token0Price()
 	} else {
@@ -102,7 +108,7 @@ func (r *Route) MidPrice() (*core.Price, error) {
 	return r.midPrice, nil
 }
 
-func reducePrice(nextInput *core.Token, price *core.Price, pools []*Pool) (*core.Price, error) {
+func reducePrice(nextInput core.Currency, price *core.Price, pools []*Pool) (*core.Price, error) {
 	var err error
 	for _, p := range pools {
 		if nextInput.Equal(p.Currency0) {
@@ -0,0 +1,337 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrUnknownCommand  = errors.New("unknown command")
+	ErrInvalidRawInput = errors.New("command input must be the raw bytes")
+	ErrNoCommands      = errors.New("no commands")
+)
+
+// CommandType is a command of the Universal Router
+type CommandType byte
+
+// The commands of the Universal Router, as numbered in Commands.sol
+const (
+	V3_SWAP_EXACT_IN            CommandType = 0x00
+	V3_SWAP_EXACT_OUT           CommandType = 0x01
+	PERMIT2_TRANSFER_FROM       CommandType = 0x02
+	PERMIT2_PERMIT_BATCH        CommandType = 0x03
+	ROUTER_SWEEP                CommandType = 0x04 // SWEEP in Commands.sol, the name is taken by the SWEEP action
+	TRANSFER                    CommandType = 0x05
+	PAY_PORTION                 CommandType = 0x06
+	V2_SWAP_EXACT_IN            CommandType = 0x08
+	V2_SWAP_EXACT_OUT           CommandType = 0x09
+	PERMIT2_PERMIT              CommandType = 0x0a
+	WRAP_ETH                    CommandType = 0x0b
+	UNWRAP_WETH                 CommandType = 0x0c
+	PERMIT2_TRANSFER_FROM_BATCH CommandType = 0x0d
+	BALANCE_CHECK_ERC20         CommandType = 0x0e
+	// V4_SWAP executes a V4Planner's actions through the Universal Router
+	V4_SWAP                    CommandType = 0x10
+	V3_POSITION_MANAGER_PERMIT CommandType = 0x11
+	V3_POSITION_MANAGER_CALL   CommandType = 0x12
+	V4_INITIALIZE_POOL         CommandType = 0x13
+	V4_POSITION_MANAGER_CALL   CommandType = 0x14
+	EXECUTE_SUB_PLAN           CommandType = 0x21
+
+	// ALLOW_REVERT_FLAG lets the router continue when the command reverts
+	ALLOW_REVERT_FLAG CommandType = 0x80
+	COMMAND_TYPE_MASK CommandType = 0x3f
+)
+
+var commandNames = map[CommandType]string{
+	V3_SWAP_EXACT_IN:            "V3_SWAP_EXACT_IN",
+	V3_SWAP_EXACT_OUT:           "V3_SWAP_EXACT_OUT",
+	PERMIT2_TRANSFER_FROM:       "PERMIT2_TRANSFER_FROM",
+	PERMIT2_PERMIT_BATCH:        "PERMIT2_PERMIT_BATCH",
+	ROUTER_SWEEP:                "SWEEP",
+	TRANSFER:                    "TRANSFER",
+	PAY_PORTION:                 "PAY_PORTION",
+	V2_SWAP_EXACT_IN:            "V2_SWAP_EXACT_IN",
+	V2_SWAP_EXACT_OUT:           "V2_SWAP_EXACT_OUT",
+	PERMIT2_PERMIT:              "PERMIT2_PERMIT",
+	WRAP_ETH:                    "WRAP_ETH",
+	UNWRAP_WETH:                 "UNWRAP_WETH",
+	PERMIT2_TRANSFER_FROM_BATCH: "PERMIT2_TRANSFER_FROM_BATCH",
+	BALANCE_CHECK_ERC20:         "BALANCE_CHECK_ERC20",
+	V4_SWAP:                     "V4_SWAP",
+	V3_POSITION_MANAGER_PERMIT:  "V3_POSITION_MANAGER_PERMIT",
+	V3_POSITION_MANAGER_CALL:    "V3_POSITION_MANAGER_CALL",
+	V4_INITIALIZE_POOL:          "V4_INITIALIZE_POOL",
+	V4_POSITION_MANAGER_CALL:    "V4_POSITION_MANAGER_CALL",
+	EXECUTE_SUB_PLAN:            "EXECUTE_SUB_PLAN",
+}
+
+// String returns the name of the command in Commands.sol, with the allow revert flag ignored
+func (c CommandType) String() string {
+	if name, ok := commandNames[c&COMMAND_TYPE_MASK]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN_COMMAND_0x%02x", byte(c))
+}
+
+// ROUTER_COMMAND_ABI_DEFINITION is the abi layout of the input of each command
+var ROUTER_COMMAND_ABI_DEFINITION = map[CommandType][]ParamType{
+	V3_SWAP_EXACT_IN: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amountIn", Type: "uint256"},
+		{Name: "amountOutMin", Type: "uint256"},
+		{Name: "path", Type: "bytes"},
+		{Name: "payerIsUser", Type: "bool"},
+	},
+	V3_SWAP_EXACT_OUT: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amountOut", Type: "uint256"},
+		{Name: "amountInMax", Type: "uint256"},
+		{Name: "path", Type: "bytes"},
+		{Name: "payerIsUser", Type: "bool"},
+	},
+	PERMIT2_TRANSFER_FROM: {
+		{Name: "token", Type: "address"},
+		{Name: "recipient", Type: "address"},
+		{Name: "amount", Type: "uint160"},
+	},
+	PERMIT2_PERMIT_BATCH: {
+		{Name: "permitBatch", Type: PERMIT_BATCH_STRUCT},
+		{Name: "signature", Type: "bytes"},
+	},
+	ROUTER_SWEEP: {
+		{Name: "token", Type: "address"},
+		{Name: "recipient", Type: "address"},
+		{Name: "amountMin", Type: "uint256"},
+	},
+	TRANSFER: {
+		{Name: "token", Type: "address"},
+		{Name: "recipient", Type: "address"},
+		{Name: "value", Type: "uint256"},
+	},
+	PAY_PORTION: {
+		{Name: "token", Type: "address"},
+		{Name: "recipient", Type: "address"},
+		{Name: "bips", Type: "uint256"},
+	},
+	V2_SWAP_EXACT_IN: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amountIn", Type: "uint256"},
+		{Name: "amountOutMin", Type: "uint256"},
+		{Name: "path", Type: "address[]"},
+		{Name: "payerIsUser", Type: "bool"},
+	},
+	V2_SWAP_EXACT_OUT: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amountOut", Type: "uint256"},
+		{Name: "amountInMax", Type: "uint256"},
+		{Name: "path", Type: "address[]"},
+		{Name: "payerIsUser", Type: "bool"},
+	},
+	PERMIT2_PERMIT: {
+		{Name: "permitSingle", Type: PERMIT_SINGLE_STRUCT},
+		{Name: "signature", Type: "bytes"},
+	},
+	WRAP_ETH: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	},
+	UNWRAP_WETH: {
+		{Name: "recipient", Type: "address"},
+		{Name: "amountMin", Type: "uint256"},
+	},
+	PERMIT2_TRANSFER_FROM_BATCH: {
+		{Name: "transferFrom", Type: ALLOWANCE_TRANSFER_DETAILS_STRUCT + "[]"},
+	},
+	BALANCE_CHECK_ERC20: {
+		{Name: "owner", Type: "address"},
+		{Name: "token", Type: "address"},
+		{Name: "minBalance", Type: "uint256"},
+	},
+	V4_INITIALIZE_POOL: {
+		{Name: "poolKey", Type: POOL_KEY_STRUCT},
+		{Name: "sqrtPriceX96", Type: "uint160"},
+	},
+	EXECUTE_SUB_PLAN: {
+		{Name: "commands", Type: "bytes"},
+		{Name: "inputs", Type: "bytes[]"},
+	},
+}
+
+// rawInputCommands take their input as is instead of abi encoded: the unlockData of a V4Planner for V4_SWAP and
+// position manager calldata for the position manager calls
+var rawInputCommands = map[CommandType]bool{
+	V4_SWAP:                    true,
+	V3_POSITION_MANAGER_PERMIT: true,
+	V3_POSITION_MANAGER_CALL:   true,
+	V4_POSITION_MANAGER_CALL:   true,
+}
+
+var (
+	executeMethod                = mustNewMethod("execute", []string{"bytes commands", "bytes[] inputs", "uint256 deadline"}, nil)
+	executeWithoutDeadlineMethod = mustNewMethod("execute", []string{"bytes commands", "bytes[] inputs"}, nil)
+)
+
+// RoutePlanner plans the commands of a Universal Router execute call
+type RoutePlanner struct {
+	Commands []byte
+	Inputs   [][]byte
+}
+
+func NewRoutePlanner() *RoutePlanner {
+	return &RoutePlanner{
+		Commands: []byte{},
+		Inputs:   [][]byte{},
+	}
+}
+
+/**
+ * Adds a command with its parameters as laid out in ROUTER_COMMAND_ABI_DEFINITION, raw input commands such as
+ * V4_SWAP take their input bytes as the only parameter
+ * @param command The command
+ * @param parameters The parameters of the command
+ * @param allowRevert Whether the router continues with the next command if this one reverts
+ */
+func (p *RoutePlanner) AddCommand(command CommandType, parameters []interface{}, allowRevert bool) (*RoutePlanner, error) {
+	input, err := createCommand(command, parameters)
+	if err != nil {
+		return nil, err
+	}
+	if allowRevert {
+		command |= ALLOW_REVERT_FLAG
+	}
+	p.Commands = append(p.Commands, byte(command))
+	p.Inputs = append(p.Inputs, input)
+	return p, nil
+}
+
+func createCommand(command CommandType, parameters []interface{}) ([]byte, error) {
+	if rawInputCommands[command] {
+		if len(parameters) != 1 {
+			return nil, fmt.Errorf("%w: %s expects 1 param, got %d", ErrInvalidParamsLength, command, len(parameters))
+		}
+		input, ok := parameters[0].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRawInput, command)
+		}
+		return input, nil
+	}
+
+	paramTypes, ok := ROUTER_COMMAND_ABI_DEFINITION[command]
+	if !ok {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnknownCommand, byte(command))
+	}
+	arguments, err := paramTypesArguments(paramTypes)
+	if err != nil {
+		return nil, err
+	}
+	if len(parameters) != len(arguments) {
+		return nil, fmt.Errorf("%w: %s expects %d params, got %d", ErrInvalidParamsLength, command, len(arguments), len(parameters))
+	}
+	input, err := encodeArguments(arguments, parameters...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", command, err)
+	}
+	return input, nil
+}
+
+// AllowRevert lets the router continue when the last added command reverts, e.g. after AddSweep or AddSubPlan
+func (p *RoutePlanner) AllowRevert() (*RoutePlanner, error) {
+	if len(p.Commands) == 0 {
+		return nil, ErrNoCommands
+	}
+	p.Commands[len(p.Commands)-1] |= byte(ALLOW_REVERT_FLAG)
+	return p, nil
+}
+
+// AddV4Swap adds a V4_SWAP command executing the actions of the planner
+func (p *RoutePlanner) AddV4Swap(planner *V4Planner) (*RoutePlanner, error) {
+	command, input, err := planner.V4SwapInput()
+	if err != nil {
+		return nil, err
+	}
+	return p.AddCommand(command, []interface{}{input}, false)
+}
+
+// AddWrapEth wraps exactly amount of the ETH held by the router into WETH for the recipient, reverting if the router
+// holds less. The Universal Router's CONTRACT_BALANCE constant (1 << 255) as amount wraps all of it.
+func (p *RoutePlanner) AddWrapEth(recipient common.Address, amount *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(WRAP_ETH, []interface{}{recipient, amount}, false)
+}
+
+// AddUnwrapWeth unwraps all WETH held by the router into ETH for the recipient, reverting if it is less than amountMin
+func (p *RoutePlanner) AddUnwrapWeth(recipient common.Address, amountMin *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(UNWRAP_WETH, []interface{}{recipient, amountMin}, false)
+}
+
+// AddPermit2Permit sets the Permit2 allowance of the router with a signed permit
+func (p *RoutePlanner) AddPermit2Permit(permit PermitSingle, signature []byte) (*RoutePlanner, error) {
+	return p.AddCommand(PERMIT2_PERMIT, []interface{}{permit, signature}, false)
+}
+
+// AddPermit2PermitBatch sets the Permit2 allowances of the router for several tokens with a signed permit
+func (p *RoutePlanner) AddPermit2PermitBatch(permit PermitBatch, signature []byte) (*RoutePlanner, error) {
+	return p.AddCommand(PERMIT2_PERMIT_BATCH, []interface{}{permit, signature}, false)
+}
+
+// AddPermit2TransferFrom transfers amount of token from the caller to the recipient through Permit2
+func (p *RoutePlanner) AddPermit2TransferFrom(token, recipient common.Address, amount *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(PERMIT2_TRANSFER_FROM, []interface{}{token, recipient, amount}, false)
+}
+
+// AddPermit2TransferFromBatch executes several Permit2 transfers, each from the caller
+func (p *RoutePlanner) AddPermit2TransferFromBatch(transfers []AllowanceTransferDetails) (*RoutePlanner, error) {
+	return p.AddCommand(PERMIT2_TRANSFER_FROM_BATCH, []interface{}{transfers}, false)
+}
+
+// AddSweep sends the whole balance of token held by the router to the recipient, reverting if it is less than amountMin
+func (p *RoutePlanner) AddSweep(token, recipient common.Address, amountMin *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(ROUTER_SWEEP, []interface{}{token, recipient, amountMin}, false)
+}
+
+// AddTransfer sends value of token held by the router to the recipient
+func (p *RoutePlanner) AddTransfer(token, recipient common.Address, value *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(TRANSFER, []interface{}{token, recipient, value}, false)
+}
+
+// AddPayPortion sends a portion in bips of the balance of token held by the router to the recipient, e.g. for fees
+func (p *RoutePlanner) AddPayPortion(token, recipient common.Address, bips *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(PAY_PORTION, []interface{}{token, recipient, bips}, false)
+}
+
+// AddBalanceCheckERC20 reverts unless owner holds at least minBalance of token
+func (p *RoutePlanner) AddBalanceCheckERC20(owner, token common.Address, minBalance *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(BALANCE_CHECK_ERC20, []interface{}{owner, token, minBalance}, false)
+}
+
+// AddV4InitializePool initializes the pool at the price
+func (p *RoutePlanner) AddV4InitializePool(poolKey PoolKey, sqrtPriceX96 *big.Int) (*RoutePlanner, error) {
+	return p.AddCommand(V4_INITIALIZE_POOL, []interface{}{poolKey, sqrtPriceX96}, false)
+}
+
+// AddV4PositionManagerCall calls the v4 position manager with the calldata, e.g. of EncodeModifyLiquidities
+func (p *RoutePlanner) AddV4PositionManagerCall(calldata []byte) (*RoutePlanner, error) {
+	return p.AddCommand(V4_POSITION_MANAGER_CALL, []interface{}{calldata}, false)
+}
+
+// AddSubPlan executes the commands of the sub plan, with allowRevert its failure does not revert the whole plan
+func (p *RoutePlanner) AddSubPlan(subPlan *RoutePlanner, allowRevert bool) (*RoutePlanner, error) {
+	return p.AddCommand(EXECUTE_SUB_PLAN, []interface{}{subPlan.Commands, subPlan.Inputs}, allowRevert)
+}
+
+/**
+ * Produces the calldata of the Universal Router execute call of the planned commands
+ * @param deadline The timestamp after which the call reverts, nil to use the execute overload without deadline
+ */
+func (p *RoutePlanner) ExecuteCalldata(deadline *big.Int) ([]byte, error) {
+	if len(p.Commands) != len(p.Inputs) {
+		return nil, ErrActionsParamsMismatch
+	}
+	if deadline == nil {
+		return encodeMethodCall(executeWithoutDeadlineMethod, p.Commands, p.Inputs)
+	}
+	return encodeMethodCall(executeMethod, p.Commands, p.Inputs, deadline)
+}
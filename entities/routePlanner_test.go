@@ -0,0 +1,241 @@
+package entities
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var routeTestRecipient = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+// decodeExecute unpacks Universal Router execute calldata, deadline is nil for the overload without one
+func decodeExecute(t *testing.T, calldata []byte) (commands []byte, inputs [][]byte, deadline *big.Int) {
+	t.Helper()
+	method := executeMethod
+	if string(calldata[:4]) == string(executeWithoutDeadlineMethod.ID) {
+		method = executeWithoutDeadlineMethod
+	} else if string(calldata[:4]) != string(executeMethod.ID) {
+		t.Fatalf("selector 0x%x", calldata[:4])
+	}
+	values, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) == 3 {
+		deadline = values[2].(*big.Int)
+	}
+	return values[0].([]byte), values[1].([][]byte), deadline
+}
+
+// decodeCommandInput unpacks the abi encoded input of a command
+func decodeCommandInput(t *testing.T, command CommandType, input []byte) []interface{} {
+	t.Helper()
+	arguments, err := paramTypesArguments(ROUTER_COMMAND_ABI_DEFINITION[command&COMMAND_TYPE_MASK])
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := arguments.Unpack(input)
+	if err != nil {
+		t.Fatalf("%s: %v", command, err)
+	}
+	return values
+}
+
+func TestRoutePlannerCommands(t *testing.T) {
+	token := testToken0.Address
+	n := big.NewInt
+	tests := []struct {
+		command CommandType
+		add     func(p *RoutePlanner) (*RoutePlanner, error)
+		values  []interface{}
+	}{
+		{WRAP_ETH, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddWrapEth(routeTestRecipient, n(100)) },
+			[]interface{}{routeTestRecipient, n(100)}},
+		{UNWRAP_WETH, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddUnwrapWeth(routeTestRecipient, n(100)) },
+			[]interface{}{routeTestRecipient, n(100)}},
+		{ROUTER_SWEEP, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddSweep(token, routeTestRecipient, n(1)) },
+			[]interface{}{token, routeTestRecipient, n(1)}},
+		{TRANSFER, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddTransfer(token, routeTestRecipient, n(2)) },
+			[]interface{}{token, routeTestRecipient, n(2)}},
+		{PAY_PORTION, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddPayPortion(token, routeTestRecipient, n(25)) },
+			[]interface{}{token, routeTestRecipient, n(25)}},
+		{PERMIT2_TRANSFER_FROM, func(p *RoutePlanner) (*RoutePlanner, error) {
+			return p.AddPermit2TransferFrom(token, routeTestRecipient, n(3))
+		}, []interface{}{token, routeTestRecipient, n(3)}},
+		{BALANCE_CHECK_ERC20, func(p *RoutePlanner) (*RoutePlanner, error) {
+			return p.AddBalanceCheckERC20(routeTestRecipient, token, n(4))
+		}, []interface{}{routeTestRecipient, token, n(4)}},
+		{V4_INITIALIZE_POOL, func(p *RoutePlanner) (*RoutePlanner, error) { return p.AddV4InitializePool(parserTestKey, n(1<<40)) }, nil},
+		{PERMIT2_PERMIT, func(p *RoutePlanner) (*RoutePlanner, error) {
+			return p.AddPermit2Permit(PermitSingle{
+				Details: PermitDetails{Token: token, Amount: n(6), Expiration: n(7), Nonce: n(8)}, Spender: routeTestRecipient, SigDeadline: n(5),
+			}, []byte{1})
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command.String(), func(t *testing.T) {
+			planner, err := tt.add(NewRoutePlanner())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(planner.Commands) != 1 || CommandType(planner.Commands[0]) != tt.command {
+				t.Fatalf("commands 0x%x, want 0x%02x", planner.Commands, byte(tt.command))
+			}
+			values := decodeCommandInput(t, tt.command, planner.Inputs[0])
+			if tt.values != nil && !reflect.DeepEqual(values, tt.values) {
+				t.Errorf("input decoded as %v, want %v", values, tt.values)
+			}
+		})
+	}
+
+	// WRAP_ETH(recipient, amount) as encoded by abi.encode
+	planner, err := NewRoutePlanner().AddWrapEth(routeTestRecipient, n(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "00000000000000000000000000000000000000000000000000000000000000aa" +
+		"0000000000000000000000000000000000000000000000000000000000000064"
+	if hex.EncodeToString(planner.Inputs[0]) != want {
+		t.Errorf("WRAP_ETH input %x", planner.Inputs[0])
+	}
+}
+
+func TestRoutePlannerRawInputCommands(t *testing.T) {
+	v4Planner, err := NewV4Planner().AddSweep(parserTestCurrency("0x0000000000000000000000000000000000000001"), routeTestRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planner, err := NewRoutePlanner().AddV4Swap(v4Planner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	positionManagerCall := []byte{0xde, 0xad, 0xbe, 0xef}
+	if _, err := planner.AddV4PositionManagerCall(positionManagerCall); err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(planner.Commands) != "1014" {
+		t.Errorf("commands %x", planner.Commands)
+	}
+	call, err := ParseCalldata(planner.Inputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(call.Actions) != 1 || call.Actions[0].ActionType != SWEEP {
+		t.Errorf("V4_SWAP input parsed as %+v", call.Actions)
+	}
+	if string(planner.Inputs[1]) != string(positionManagerCall) {
+		t.Errorf("V4_POSITION_MANAGER_CALL input %x", planner.Inputs[1])
+	}
+
+	if _, err := NewRoutePlanner().AddCommand(V4_SWAP, []interface{}{"0x00"}, false); !errors.Is(err, ErrInvalidRawInput) {
+		t.Errorf("string input returned %v", err)
+	}
+	if _, err := NewRoutePlanner().AddCommand(V4_SWAP, nil, false); !errors.Is(err, ErrInvalidParamsLength) {
+		t.Errorf("missing input returned %v", err)
+	}
+	if _, err := NewRoutePlanner().AddCommand(0x3f, nil, false); !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("unknown command returned %v", err)
+	}
+	if _, err := NewRoutePlanner().AddCommand(ROUTER_SWEEP, []interface{}{routeTestRecipient}, false); !errors.Is(err, ErrInvalidParamsLength) {
+		t.Errorf("missing params returned %v", err)
+	}
+}
+
+func TestRoutePlannerAllowRevert(t *testing.T) {
+	planner, err := NewRoutePlanner().AddCommand(ROUTER_SWEEP, []interface{}{testToken0.Address, routeTestRecipient, big.NewInt(0)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.AddTransfer(testToken0.Address, routeTestRecipient, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.AddUnwrapWeth(routeTestRecipient, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.AllowRevert(); err != nil {
+		t.Fatal(err)
+	}
+	// only the flagged commands allow reverting
+	if hex.EncodeToString(planner.Commands) != "84058c" {
+		t.Errorf("commands %x", planner.Commands)
+	}
+	if command := CommandType(planner.Commands[2]); command.String() != "UNWRAP_WETH" {
+		t.Errorf("flagged command named %s", command)
+	}
+	// the input does not change with the flag
+	if values := decodeCommandInput(t, CommandType(planner.Commands[0]), planner.Inputs[0]); values[1] != routeTestRecipient {
+		t.Errorf("sweep input decoded as %v", values)
+	}
+
+	if _, err := NewRoutePlanner().AllowRevert(); !errors.Is(err, ErrNoCommands) {
+		t.Errorf("empty planner returned %v", err)
+	}
+}
+
+func TestRoutePlannerSubPlan(t *testing.T) {
+	subPlan, err := NewRoutePlanner().AddWrapEth(routeTestRecipient, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subPlan.AddSweep(testToken0.Address, routeTestRecipient, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	for _, allowRevert := range []bool{false, true} {
+		planner, err := NewRoutePlanner().AddSubPlan(subPlan, allowRevert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := EXECUTE_SUB_PLAN
+		if allowRevert {
+			want |= ALLOW_REVERT_FLAG
+		}
+		if CommandType(planner.Commands[0]) != want {
+			t.Errorf("command 0x%02x, want 0x%02x", planner.Commands[0], byte(want))
+		}
+		values := decodeCommandInput(t, EXECUTE_SUB_PLAN, planner.Inputs[0])
+		if !reflect.DeepEqual(values[0], subPlan.Commands) || !reflect.DeepEqual(values[1], subPlan.Inputs) {
+			t.Errorf("sub plan decoded as %x", values)
+		}
+	}
+}
+
+func TestExecuteCalldata(t *testing.T) {
+	planner, err := NewRoutePlanner().AddUnwrapWeth(routeTestRecipient, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		deadline *big.Int
+		selector string
+	}{
+		// execute(bytes,bytes[],uint256)
+		{big.NewInt(1700000000), "3593564c"},
+		// execute(bytes,bytes[])
+		{nil, "24856bc3"},
+	}
+	for _, tt := range tests {
+		calldata, err := planner.ExecuteCalldata(tt.deadline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selector := hex.EncodeToString(calldata[:4]); selector != tt.selector {
+			t.Errorf("deadline %v: selector %s, want %s", tt.deadline, selector, tt.selector)
+		}
+		commands, inputs, deadline := decodeExecute(t, calldata)
+		if !reflect.DeepEqual(commands, planner.Commands) || !reflect.DeepEqual(inputs, planner.Inputs) {
+			t.Errorf("decoded commands %x inputs %x", commands, inputs)
+		}
+		if (deadline == nil) != (tt.deadline == nil) || (deadline != nil && deadline.Cmp(tt.deadline) != 0) {
+			t.Errorf("deadline %v, want %v", deadline, tt.deadline)
+		}
+	}
+
+	planner.Inputs = planner.Inputs[:0]
+	if _, err := planner.ExecuteCalldata(nil); !errors.Is(err, ErrActionsParamsMismatch) {
+		t.Errorf("commands without inputs returned %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNativeRoute(t *testing.T) {
+	weth := pmTestNative.Wrapped()
+	native := testPoolWithCurrencies(t, pmTestNative, testToken1, 3000, common.Address{})
+	wrapped := testPoolWithCurrencies(t, testToken1, weth, 3000, common.Address{})
+
+	tests := []struct {
+		name                  string
+		pool                  *Pool
+		input, output         core.Currency
+		pathInput, pathOutput core.Currency
+	}{
+		{"ETH through a native pool", native, pmTestNative, testToken1, pmTestNative, testToken1},
+		{"to ETH through a native pool", native, testToken1, pmTestNative, testToken1, pmTestNative},
+		// v4 pools of native ETH also take WETH once it is unwrapped
+		{"WETH through a native pool", native, weth, testToken1, pmTestNative, testToken1},
+		{"ETH through a WETH pool", wrapped, pmTestNative, testToken1, weth, testToken1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := NewRoute([]*Pool{tt.pool}, tt.input, tt.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !route.Input.Equal(tt.input) || !route.Output.Equal(tt.output) {
+				t.Errorf("route from %s to %s", route.Input.Symbol(), route.Output.Symbol())
+			}
+			if !route.PathInput.Equal(tt.pathInput) || route.PathInput.IsNative() != tt.pathInput.IsNative() {
+				t.Errorf("path input %s, want %s", route.PathInput.Symbol(), tt.pathInput.Symbol())
+			}
+			if !route.PathOutput.Equal(tt.pathOutput) || route.PathOutput.IsNative() != tt.pathOutput.IsNative() {
+				t.Errorf("path output %s, want %s", route.PathOutput.Symbol(), tt.pathOutput.Symbol())
+			}
+
+			// the trade keeps the currencies of the route, the swap runs in those of the path
+			trade, err := ExactIn(route, core.FromRawAmount(tt.input, big.NewInt(1000000)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !trade.InputAmount().Currency.Equal(tt.input) || !trade.OutputAmount().Currency.Equal(tt.output) {
+				t.Errorf("trade from %s to %s", trade.InputAmount().Currency.Symbol(), trade.OutputAmount().Currency.Symbol())
+			}
+			if trade.OutputAmount().Quotient().Sign() <= 0 {
+				t.Errorf("trade output %s", trade.OutputAmount().Quotient())
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package entities
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	getSlot0Method            = mustNewMethod("getSlot0", []string{"bytes32 poolId"}, []string{"uint160 sqrtPriceX96", "int24 tick", "uint24 protocolFee", "uint24 lpFee"})
+	getLiquidityMethod        = mustNewMethod("getLiquidity", []string{"bytes32 poolId"}, []string{"uint128 liquidity"})
+	getTickInfoMethod         = mustNewMethod("getTickInfo", []string{"bytes32 poolId", "int24 tick"}, []string{"uint128 liquidityGross", "int128 liquidityNet", "uint256 feeGrowthOutside0X128", "uint256 feeGrowthOutside1X128"})
+	getTickBitmapMethod       = mustNewMethod("getTickBitmap", []string{"bytes32 poolId", "int16 tick"}, []string{"uint256 tickBitmap"})
+	getPositionInfoMethod     = mustNewMethod("getPositionInfo", []string{"bytes32 poolId", "address owner", "int24 tickLower", "int24 tickUpper", "bytes32 salt"}, []string{"uint128 liquidity", "uint256 feeGrowthInside0LastX128", "uint256 feeGrowthInside1LastX128"})
+	getPositionInfoByIdMethod = mustNewMethod("getPositionInfo", []string{"bytes32 poolId", "bytes32 positionId"}, []string{"uint128 liquidity", "uint256 feeGrowthInside0LastX128", "uint256 feeGrowthInside1LastX128"})
+	getFeeGrowthGlobalsMethod = mustNewMethod("getFeeGrowthGlobals", []string{"bytes32 poolId"}, []string{"uint256 feeGrowthGlobal0", "uint256 feeGrowthGlobal1"})
+)
+
+// Slot0 is the price state of a pool as returned by StateView.getSlot0
+type Slot0 struct {
+	SqrtPriceX96 *big.Int
+	Tick         int
+	ProtocolFee  int64
+	LPFee        int64
+}
+
+// TickInfo is the state of an initialized tick as returned by StateView.getTickInfo
+type TickInfo struct {
+	LiquidityGross        *big.Int
+	LiquidityNet          *big.Int
+	FeeGrowthOutside0X128 *big.Int
+	FeeGrowthOutside1X128 *big.Int
+}
+
+// PoolPositionInfo is the state of a position in the PoolManager as returned by StateView.getPositionInfo
+type PoolPositionInfo struct {
+	Liquidity                *big.Int
+	FeeGrowthInside0LastX128 *big.Int
+	FeeGrowthInside1LastX128 *big.Int
+}
+
+func EncodeGetSlot0(poolId PoolId) ([]byte, error) {
+	return encodeMethodCall(getSlot0Method, poolId)
+}
+
+func DecodeGetSlot0Result(data []byte) (*Slot0, error) {
+	r, err := unpackResult(getSlot0Method, data)
+	if err != nil {
+		return nil, err
+	}
+	slot0 := &Slot0{
+		SqrtPriceX96: r.bigInt(0),
+		Tick:         r.int(1),
+		ProtocolFee:  r.bigInt(2).Int64(),
+		LPFee:        r.bigInt(3).Int64(),
+	}
+	return slot0, r.err
+}
+
+func EncodeGetLiquidity(poolId PoolId) ([]byte, error) {
+	return encodeMethodCall(getLiquidityMethod, poolId)
+}
+
+func DecodeGetLiquidityResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(getLiquidityMethod, data)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := r.bigInt(0)
+	return liquidity, r.err
+}
+
+func EncodeGetTickInfo(poolId PoolId, tick int) ([]byte, error) {
+	return encodeMethodCall(getTickInfoMethod, poolId, tick)
+}
+
+func DecodeGetTickInfoResult(data []byte) (*TickInfo, error) {
+	r, err := unpackResult(getTickInfoMethod, data)
+	if err != nil {
+		return nil, err
+	}
+	info := &TickInfo{
+		LiquidityGross:        r.bigInt(0),
+		LiquidityNet:          r.bigInt(1),
+		FeeGrowthOutside0X128: r.bigInt(2),
+		FeeGrowthOutside1X128: r.bigInt(3),
+	}
+	return info, r.err
+}
+
+/**
+ * Encodes StateView.getTickBitmap
+ * @param poolId The id of the pool
+ * @param wordPos The position of the word in the bitmap, i.e. the compressed tick shifted right by 8
+ */
+func EncodeGetTickBitmap(poolId PoolId, wordPos int16) ([]byte, error) {
+	return encodeMethodCall(getTickBitmapMethod, poolId, wordPos)
+}
+
+func DecodeGetTickBitmapResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(getTickBitmapMethod, data)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := r.bigInt(0)
+	return bitmap, r.err
+}
+
+/**
+ * Encodes StateView.getPositionInfo for the position of owner in the pool
+ * @param poolId The id of the pool
+ * @param owner The owner of the position, the PositionManager for positions minted through it
+ * @param tickLower The lower tick of the position
+ * @param tickUpper The upper tick of the position
+ * @param salt The salt of the position, the token id for positions of the PositionManager
+ */
+func EncodeGetPositionInfo(poolId PoolId, owner common.Address, tickLower, tickUpper int, salt common.Hash) ([]byte, error) {
+	return encodeMethodCall(getPositionInfoMethod, poolId, owner, tickLower, tickUpper, salt)
+}
+
+// EncodeGetPositionInfoById encodes StateView.getPositionInfo for a position id, see CalculatePositionKey
+func EncodeGetPositionInfoById(poolId PoolId, positionId common.Hash) ([]byte, error) {
+	return encodeMethodCall(getPositionInfoByIdMethod, poolId, positionId)
+}
+
+// CalculatePositionKey returns the id of a position in the PoolManager, keccak256(abi.encodePacked(owner, tickLower, tickUpper, salt))
+func CalculatePositionKey(owner common.Address, tickLower, tickUpper int, salt common.Hash) common.Hash {
+	var packed [20 + 3 + 3 + 32]byte
+	copy(packed[:20], owner[:])
+	putInt24(packed[20:23], tickLower)
+	putInt24(packed[23:26], tickUpper)
+	copy(packed[26:], salt[:])
+	return crypto.Keccak256Hash(packed[:])
+}
+
+func putInt24(b []byte, v int) {
+	b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+}
+
+// DecodeGetPositionInfoResult decodes the result of both getPositionInfo overloads
+func DecodeGetPositionInfoResult(data []byte) (*PoolPositionInfo, error) {
+	r, err := unpackResult(getPositionInfoMethod, data)
+	if err != nil {
+		return nil, err
+	}
+	info := &PoolPositionInfo{
+		Liquidity:                r.bigInt(0),
+		FeeGrowthInside0LastX128: r.bigInt(1),
+		FeeGrowthInside1LastX128: r.bigInt(2),
+	}
+	return info, r.err
+}
+
+func EncodeGetFeeGrowthGlobals(poolId PoolId) ([]byte, error) {
+	return encodeMethodCall(getFeeGrowthGlobalsMethod, poolId)
+}
+
+// DecodeGetFeeGrowthGlobalsResult returns the global fee growth of currency0 and currency1
+func DecodeGetFeeGrowthGlobalsResult(data []byte) (*big.Int, *big.Int, error) {
+	r, err := unpackResult(getFeeGrowthGlobalsMethod, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeGrowthGlobal0, feeGrowthGlobal1 := r.bigInt(0), r.bigInt(1)
+	return feeGrowthGlobal0, feeGrowthGlobal1, r.err
+}
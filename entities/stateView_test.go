@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var stateViewTestPoolId = PoolId(common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"))
+
+func TestStateViewCallEncoding(t *testing.T) {
+	var (
+		poolId = "21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"
+		owner  = common.HexToAddress("0xbD216513d74C8cf14cf4747E6AaA6420FF64ee9e")
+		salt   = common.BigToHash(big.NewInt(7))
+	)
+	encode := func(calldata []byte, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hex.EncodeToString(calldata)
+	}
+	tests := []struct {
+		name     string
+		calldata string
+		want     string
+	}{
+		{"getSlot0", encode(EncodeGetSlot0(stateViewTestPoolId)), "c815641c" + poolId},
+		{"getLiquidity", encode(EncodeGetLiquidity(stateViewTestPoolId)), "fa6793d5" + poolId},
+		{"getFeeGrowthGlobals", encode(EncodeGetFeeGrowthGlobals(stateViewTestPoolId)), "9ec538c8" + poolId},
+		// int24 and int16 are sign extended to a word
+		{"getTickInfo", encode(EncodeGetTickInfo(stateViewTestPoolId, -60)), "7c40f1fe" + poolId +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc4"},
+		{"getTickBitmap", encode(EncodeGetTickBitmap(stateViewTestPoolId, -1)), "1c7ccb4c" + poolId +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{"getPositionInfo", encode(EncodeGetPositionInfo(stateViewTestPoolId, owner, -600, 600, salt)), "dacf1d2f" + poolId +
+			"000000000000000000000000bd216513d74c8cf14cf4747e6aaa6420ff64ee9e" +
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffda8" +
+			"0000000000000000000000000000000000000000000000000000000000000258" +
+			"0000000000000000000000000000000000000000000000000000000000000007"},
+		{"getPositionInfo by id", encode(EncodeGetPositionInfoById(stateViewTestPoolId, common.HexToHash("0x01"))), "97fd7b42" + poolId +
+			"0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+	for _, tt := range tests {
+		if tt.calldata != tt.want {
+			t.Errorf("%s: calldata %s, want %s", tt.name, tt.calldata, tt.want)
+		}
+	}
+
+	if _, err := EncodeGetTickInfo(stateViewTestPoolId, 1<<23); err == nil {
+		t.Error("tick out of the int24 range encoded")
+	}
+}
+
+func TestCalculatePositionKey(t *testing.T) {
+	owner := common.HexToAddress("0xbD216513d74C8cf14cf4747E6AaA6420FF64ee9e")
+	salt := common.BigToHash(big.NewInt(7))
+	// abi.encodePacked(owner, int24(-600), int24(600), salt)
+	packed := common.FromHex("bd216513d74c8cf14cf4747e6aaa6420ff64ee9e" + "fffda8" + "000258" +
+		"0000000000000000000000000000000000000000000000000000000000000007")
+	if key := CalculatePositionKey(owner, -600, 600, salt); key != crypto.Keccak256Hash(packed) {
+		t.Errorf("position key %s", key)
+	}
+}
+
+func TestStateViewResultDecoding(t *testing.T) {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	slot0, err := DecodeGetSlot0Result(abiWords(t,
+		"0000000000000000000000000000000000000001000000000000000000000000",
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe",
+		"00000000000000000000000000000000000000000000000000000000000003e8",
+		"0000000000000000000000000000000000000000000000000000000000000bb8",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slot0.SqrtPriceX96.Cmp(q96) != 0 || slot0.Tick != -2 || slot0.ProtocolFee != 1000 || slot0.LPFee != 3000 {
+		t.Errorf("slot0 decoded as %+v", slot0)
+	}
+
+	liquidity, err := DecodeGetLiquidityResult(abiWords(t, "00000000000000000000000000000000000000000000000000000000000f4240"))
+	if err != nil || liquidity.Int64() != 1000000 {
+		t.Errorf("liquidity decoded as %v, %v", liquidity, err)
+	}
+
+	info, err := DecodeGetTickInfoResult(abiWords(t,
+		"00000000000000000000000000000000000000000000000000000000000f4240",
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffff0bdc0",
+		"0000000000000000000000000000000100000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q128 := new(big.Int).Lsh(big.NewInt(1), 128)
+	if info.LiquidityGross.Int64() != 1000000 || info.LiquidityNet.Int64() != -1000000 ||
+		info.FeeGrowthOutside0X128.Cmp(q128) != 0 || info.FeeGrowthOutside1X128.Int64() != 2 {
+		t.Errorf("tick info decoded as %+v", info)
+	}
+
+	bitmap, err := DecodeGetTickBitmapResult(abiWords(t, "8000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil || bitmap.Bit(255) != 1 || bitmap.Bit(0) != 1 || bitmap.BitLen() != 256 {
+		t.Errorf("bitmap decoded as %x, %v", bitmap, err)
+	}
+
+	position, err := DecodeGetPositionInfoResult(abiWords(t,
+		"00000000000000000000000000000000000000000000000000000000000f4240",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"0000000000000000000000000000000000000000000000000000000000000004",
+	))
+	if err != nil || position.Liquidity.Int64() != 1000000 || position.FeeGrowthInside0LastX128.Int64() != 3 || position.FeeGrowthInside1LastX128.Int64() != 4 {
+		t.Errorf("position decoded as %+v, %v", position, err)
+	}
+
+	feeGrowth0, feeGrowth1, err := DecodeGetFeeGrowthGlobalsResult(abiWords(t,
+		"0000000000000000000000000000000000000000000000000000000000000005",
+		"0000000000000000000000000000000000000000000000000000000000000006",
+	))
+	if err != nil || feeGrowth0.Int64() != 5 || feeGrowth1.Int64() != 6 {
+		t.Errorf("fee growth decoded as %v %v, %v", feeGrowth0, feeGrowth1, err)
+	}
+
+	// a word short
+	if _, err := DecodeGetSlot0Result(abiWords(t,
+		"0000000000000000000000000000000000000001000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	)); err == nil {
+		t.Error("truncated slot0 decoded")
+	}
+}
@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrNoTrades               = errors.New("no trades")
+	ErrTradesCurrencyMismatch = errors.New("routes of the trades must have the same path input and output currency")
+	ErrInvalidFee             = errors.New("fee must be between 0 and 100%")
+	ErrFeeNotWholeBips        = errors.New("fee must be a whole number of bips")
+	ErrNativePermit           = errors.New("native input cannot be permitted")
+)
+
+var bipsBase = big.NewInt(10000)
+
+// FeeOptions is the integrator fee taken from the output of a swap
+type FeeOptions struct {
+	// The portion of the output taken as fee, in whole bips
+	Fee *core.Percent
+	// The account receiving the fee
+	Recipient common.Address
+}
+
+// Options for producing the Universal Router calldata of a swap
+type SwapOptions struct {
+	// How much the execution price is allowed to move unfavorably from the trade execution price
+	SlippageTolerance *core.Percent
+	// The account that receives the output, the caller when zero
+	Recipient common.Address
+	// When the transaction expires, in epoch seconds, nil to not expire
+	Deadline *big.Int
+	// Optional integrator fee taken from the output
+	Fee *FeeOptions
+	// Optional Permit2 permit of the router to spend the input token, executed before the swap
+	InputTokenPermit *Permit2Permit
+	// Optional hook data passed to the hooks of the pools the trades swap through
+	HookData HookDataProvider
+}
+
+/**
+ * Produces the Universal Router calldata and value to execute the trades as a single V4_SWAP. Like AddTrade, the
+ * input of all routes of all trades is settled at once, with SETTLE_ALL bounded by the sum of the maximum inputs or,
+ * for native input, with SETTLE paid from the value sent along. The output is taken at once with TAKE_ALL bounded by
+ * the sum of the minimum outputs, or with TAKE when it goes to another recipient, after TAKE_PORTION took the fee.
+ * @param trades The trades, the routes of all of them must share their path input and output currency
+ * @param options Options for the swap
+ */
+func SwapCallParameters(trades []*Trade, options SwapOptions) (utils.MethodParameters, error) {
+	if len(trades) == 0 {
+		return utils.MethodParameters{}, ErrNoTrades
+	}
+	if options.SlippageTolerance == nil || options.SlippageTolerance.LessThan(constants.PercentZero) {
+		return utils.MethodParameters{}, ErrInvalidSlippageTolerance
+	}
+	// the pool manager is settled and paid in the path currencies of the routes, which may be the wrapped native
+	// currency of a trade from or to the native currency
+	planner := NewV4Planner()
+	settlement := newTradeSettlement()
+	for _, trade := range trades {
+		if err := planner.addTradeSwaps(trade, options.SlippageTolerance, options.HookData, settlement); err != nil {
+			return utils.MethodParameters{}, err
+		}
+	}
+	inputCurrency, outputCurrency := settlement.currencyIn, settlement.currencyOut
+	maxAmountIn, minAmountOut := settlement.maxAmountIn, settlement.minAmountOut
+
+	// the router cannot pull native currency from the caller, it pays from the value sent along instead
+	inputIsNative := inputCurrency.IsNative()
+	var err error
+	if inputIsNative {
+		_, err = planner.AddSettle(&inputCurrency, false, nil)
+	} else {
+		_, err = planner.AddSettleAll(&inputCurrency, maxAmountIn)
+	}
+	if err != nil {
+		return utils.MethodParameters{}, err
+	}
+
+	if options.Fee != nil {
+		bips, err := feeBips(options.Fee.Fee)
+		if err != nil {
+			return utils.MethodParameters{}, err
+		}
+		if _, err := planner.AddTakePortion(&outputCurrency, options.Fee.Recipient, bips); err != nil {
+			return utils.MethodParameters{}, err
+		}
+		// TAKE_PORTION takes the portion rounded down of the whole output, leaving at least this much of the minimum
+		minAmountOut.Sub(minAmountOut, new(big.Int).Quo(new(big.Int).Mul(minAmountOut, bips), bipsBase))
+	}
+
+	recipient := options.Recipient
+	if recipient == (common.Address{}) {
+		recipient = constants.MsgSender
+	}
+	if recipient == constants.MsgSender {
+		_, err = planner.AddTakeAll(&outputCurrency, minAmountOut)
+	} else {
+		// TAKE_ALL only pays the caller, the minimum output is then checked by each swap
+		_, err = planner.AddTake(&outputCurrency, recipient, nil)
+	}
+	if err != nil {
+		return utils.MethodParameters{}, err
+	}
+
+	routePlanner := NewRoutePlanner()
+	if options.InputTokenPermit != nil {
+		if inputIsNative {
+			return utils.MethodParameters{}, ErrNativePermit
+		}
+		if _, err := routePlanner.AddPermit2Permit(options.InputTokenPermit.PermitSingle, options.InputTokenPermit.Signature); err != nil {
+			return utils.MethodParameters{}, err
+		}
+	}
+	if _, err := routePlanner.AddV4Swap(planner); err != nil {
+		return utils.MethodParameters{}, err
+	}
+
+	value := big.NewInt(0)
+	if inputIsNative {
+		value = maxAmountIn
+		// exact output swaps may not spend all of the value, the rest is refunded to the caller
+		if settlement.exactOutput {
+			if _, err := routePlanner.AddSweep(constants.AddressZero, constants.MsgSender, big.NewInt(0)); err != nil {
+				return utils.MethodParameters{}, err
+			}
+		}
+	}
+
+	calldata, err := routePlanner.ExecuteCalldata(options.Deadline)
+	if err != nil {
+		return utils.MethodParameters{}, err
+	}
+	return utils.MethodParameters{Calldata: calldata, Value: value}, nil
+}
+
+// feeBips converts the fee percentage into the bips of TAKE_PORTION, which cannot take a fraction of a bip
+func feeBips(fee *core.Percent) (*big.Int, error) {
+	if fee == nil || fee.LessThan(constants.PercentZero) || fee.GreaterThan(core.NewPercent(big.NewInt(1), big.NewInt(1)).Fraction) {
+		return nil, ErrInvalidFee
+	}
+	bips, remainder := new(big.Int).QuoRem(new(big.Int).Mul(fee.Numerator, bipsBase), fee.Denominator, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, ErrFeeNotWholeBips
+	}
+	return bips, nil
+}
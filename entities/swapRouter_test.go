@@ -0,0 +1,248 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	swapTestSlippage     = core.NewPercent(big.NewInt(1), big.NewInt(100))
+	swapTestDeadline     = big.NewInt(1700000000)
+	swapTestFeeRecipient = common.HexToAddress("0x00000000000000000000000000000000000000fe")
+)
+
+func swapTestTrade(t *testing.T, pools []*Pool, input, output core.Currency, amount int64, tradeType core.TradeType) *Trade {
+	t.Helper()
+	route, err := NewRoute(pools, input, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currency := input
+	if tradeType == core.ExactOutput {
+		currency = output
+	}
+	trade, err := FromRoute(route, core.FromRawAmount(currency, big.NewInt(amount)), tradeType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trade
+}
+
+// decodeSwapCall decodes the router commands of the call and the actions of its V4_SWAP
+func decodeSwapCall(t *testing.T, params utils.MethodParameters) (commands []byte, inputs [][]byte, actions []interface{}) {
+	t.Helper()
+	commands, inputs, deadline := decodeExecute(t, params.Calldata)
+	if deadline == nil || deadline.Cmp(swapTestDeadline) != 0 {
+		t.Errorf("deadline %v", deadline)
+	}
+	for i, command := range commands {
+		if CommandType(command) != V4_SWAP {
+			continue
+		}
+		call, err := ParseCalldata(inputs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, action := range call.Actions {
+			actions = append(actions, action.Decoded)
+		}
+	}
+	return commands, inputs, actions
+}
+
+// expectSwapActions compares the printed actions, a decoded zero big.Int is not deeply equal to big.NewInt(0)
+func expectSwapActions(t *testing.T, actions []interface{}, want ...interface{}) {
+	t.Helper()
+	if fmt.Sprintf("%+v", actions) != fmt.Sprintf("%+v", want) {
+		t.Errorf("actions\n%+v\nwant\n%+v", actions, want)
+	}
+}
+
+func expectValue(t *testing.T, params utils.MethodParameters, want *big.Int) {
+	t.Helper()
+	if params.Value.Cmp(want) != 0 {
+		t.Errorf("value %s, want %s", params.Value, want)
+	}
+}
+
+func TestSwapCallParametersExactInput(t *testing.T) {
+	pool := testPoolWithCurrencies(t, testToken0, testToken1, 3000, common.Address{})
+	trade := swapTestTrade(t, []*Pool{pool}, testToken0, testToken1, 1000000, core.ExactInput)
+	minimumAmountOut, err := trade.MininumAmountOut(swapTestSlippage, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minOut := minimumAmountOut.Quotient()
+
+	params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands, _, actions := decodeSwapCall(t, params)
+	if string(commands) != string([]byte{byte(V4_SWAP)}) {
+		t.Errorf("commands %x", commands)
+	}
+	expectSwapActions(t, actions,
+		SwapExactInSingle{PoolKey: pool.PoolKey, ZeroForOne: true, AmountIn: big.NewInt(1000000), AmountOutMinimum: minOut, HookData: []byte{}},
+		SettleAll{Currency: testToken0.Address, MaxAmount: big.NewInt(1000000)},
+		TakeAll{Currency: testToken1.Address, MinAmount: minOut},
+	)
+	expectValue(t, params, big.NewInt(0))
+
+	t.Run("recipient", func(t *testing.T) {
+		params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline, Recipient: routeTestRecipient})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, actions := decodeSwapCall(t, params)
+		expectSwapActions(t, actions[2:], Take{Currency: testToken1.Address, Recipient: routeTestRecipient, Amount: big.NewInt(FULL_DELTA_AMOUNT)})
+	})
+	t.Run("fee", func(t *testing.T) {
+		params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{
+			SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline,
+			Fee: &FeeOptions{Fee: core.NewPercent(big.NewInt(25), big.NewInt(10000)), Recipient: swapTestFeeRecipient},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, actions := decodeSwapCall(t, params)
+		// what is left after TAKE_PORTION took 25 bips, rounded down, of the minimum output
+		minOutAfterFee := new(big.Int).Sub(minOut, new(big.Int).Div(new(big.Int).Mul(minOut, big.NewInt(25)), big.NewInt(10000)))
+		expectSwapActions(t, actions[2:],
+			TakePortion{Currency: testToken1.Address, Recipient: swapTestFeeRecipient, Bips: big.NewInt(25)},
+			TakeAll{Currency: testToken1.Address, MinAmount: minOutAfterFee},
+		)
+	})
+	t.Run("permit", func(t *testing.T) {
+		permit := &Permit2Permit{
+			PermitSingle: PermitSingle{
+				Details: PermitDetails{Token: testToken0.Address, Amount: big.NewInt(1000000), Expiration: big.NewInt(1700003600), Nonce: big.NewInt(0)},
+				Spender: routeTestRecipient, SigDeadline: swapTestDeadline,
+			},
+			Signature: make([]byte, 65),
+		}
+		params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline, InputTokenPermit: permit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands, inputs, _ := decodeSwapCall(t, params)
+		if string(commands) != string([]byte{byte(PERMIT2_PERMIT), byte(V4_SWAP)}) {
+			t.Fatalf("commands %x", commands)
+		}
+		values := decodeCommandInput(t, PERMIT2_PERMIT, inputs[0])
+		if signature := values[1].([]byte); len(signature) != 65 {
+			t.Errorf("permit signature %x", signature)
+		}
+	})
+}
+
+func TestSwapCallParametersNativeInput(t *testing.T) {
+	pool := testPoolWithCurrencies(t, pmTestNative, testToken1, 3000, common.Address{})
+
+	t.Run("exact input", func(t *testing.T) {
+		trade := swapTestTrade(t, []*Pool{pool}, pmTestNative, testToken1, 1000000, core.ExactInput)
+		params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands, _, actions := decodeSwapCall(t, params)
+		if string(commands) != string([]byte{byte(V4_SWAP)}) {
+			t.Errorf("commands %x", commands)
+		}
+		// the pool manager is paid from the value sent along
+		expectSwapActions(t, actions[1:2], Settle{Currency: constants.AddressZero, Amount: big.NewInt(FULL_DELTA_AMOUNT), PayerIsUser: false})
+		expectValue(t, params, big.NewInt(1000000))
+	})
+	t.Run("exact output", func(t *testing.T) {
+		trade := swapTestTrade(t, []*Pool{pool}, pmTestNative, testToken1, 1000000, core.ExactOutput)
+		maximumAmountIn, err := trade.MaximumAmountIn(swapTestSlippage, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands, inputs, actions := decodeSwapCall(t, params)
+		expectSwapActions(t, actions[:1], SwapExactOutSingle{
+			PoolKey: pool.PoolKey, ZeroForOne: true, AmountOut: big.NewInt(1000000), AmountInMaximum: maximumAmountIn.Quotient(), HookData: []byte{},
+		})
+		expectValue(t, params, maximumAmountIn.Quotient())
+		// the value not spent is swept back to the caller
+		if string(commands) != string([]byte{byte(V4_SWAP), byte(ROUTER_SWEEP)}) {
+			t.Fatalf("commands %x", commands)
+		}
+		values := decodeCommandInput(t, ROUTER_SWEEP, inputs[1])
+		if fmt.Sprint(values) != fmt.Sprint([]interface{}{constants.AddressZero, constants.MsgSender, big.NewInt(0)}) {
+			t.Errorf("sweep input %v", values)
+		}
+	})
+	t.Run("permit", func(t *testing.T) {
+		trade := swapTestTrade(t, []*Pool{pool}, pmTestNative, testToken1, 1000000, core.ExactInput)
+		_, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, InputTokenPermit: &Permit2Permit{}})
+		if !errors.Is(err, ErrNativePermit) {
+			t.Errorf("permit of native input returned %v", err)
+		}
+	})
+}
+
+func TestSwapCallParametersPathCurrencies(t *testing.T) {
+	weth := pmTestNative.Wrapped()
+	wethPool := testPoolWithCurrencies(t, testToken1, weth, 3000, common.Address{})
+	nativePool := testPoolWithCurrencies(t, pmTestNative, testToken1, 3000, common.Address{})
+
+	// an ETH trade through a WETH pool settles WETH, which the router cannot take from the value
+	trade := swapTestTrade(t, []*Pool{wethPool}, pmTestNative, testToken1, 1000000, core.ExactInput)
+	params, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Deadline: swapTestDeadline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, actions := decodeSwapCall(t, params)
+	expectSwapActions(t, actions[1:2], SettleAll{Currency: weth.Address, MaxAmount: big.NewInt(1000000)})
+	expectValue(t, params, big.NewInt(0))
+
+	tests := []struct {
+		name   string
+		trades []*Trade
+	}{
+		{"native and wrapped input", []*Trade{trade, swapTestTrade(t, []*Pool{nativePool}, pmTestNative, testToken1, 1000, core.ExactInput)}},
+		{"opposite directions", []*Trade{trade, swapTestTrade(t, []*Pool{wethPool}, testToken1, weth, 1000, core.ExactInput)}},
+	}
+	for _, tt := range tests {
+		if _, err := SwapCallParameters(tt.trades, SwapOptions{SlippageTolerance: swapTestSlippage}); !errors.Is(err, ErrTradesCurrencyMismatch) {
+			t.Errorf("%s: returned %v", tt.name, err)
+		}
+	}
+}
+
+func TestSwapCallParametersInvalidFee(t *testing.T) {
+	trade := swapTestTrade(t, []*Pool{testPoolWithCurrencies(t, testToken0, testToken1, 3000, common.Address{})}, testToken0, testToken1, 1000000, core.ExactInput)
+	tests := []struct {
+		fee *core.Percent
+		err error
+	}{
+		{core.NewPercent(big.NewInt(1), big.NewInt(30000)), ErrFeeNotWholeBips},
+		{core.NewPercent(big.NewInt(1), big.NewInt(3)), ErrFeeNotWholeBips},
+		{core.NewPercent(big.NewInt(10001), big.NewInt(10000)), ErrInvalidFee},
+		{core.NewPercent(big.NewInt(-1), big.NewInt(10000)), ErrInvalidFee},
+		{nil, ErrInvalidFee},
+	}
+	for _, tt := range tests {
+		_, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Fee: &FeeOptions{Fee: tt.fee}})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("fee %v returned %v, want %v", tt.fee, err, tt.err)
+		}
+	}
+	// a fee of 100% is whole bips
+	fee := core.NewPercent(big.NewInt(1), big.NewInt(1))
+	if _, err := SwapCallParameters([]*Trade{trade}, SwapOptions{SlippageTolerance: swapTestSlippage, Fee: &FeeOptions{Fee: fee}}); err != nil {
+		t.Errorf("fee of 100%% returned %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package entities
+
+import (
+	v4utils "github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	"github.com/holiman/uint256"
+)
+
+// TickBitmapProvider is a TickDataProvider that keeps the initialized ticks in a word based
+// bitmap, the same way TickBitmap.sol does, so that NextInitializedTickWithinOneWord stops at
+// exactly the same ticks as the PoolManager
+type TickBitmapProvider struct {
+	tickSpacing int
+	bitmap      map[int16]*uint256.Int
+	ticks       map[int]Tick
+}
+
+func NewTickBitmapProvider(ticks []Tick, tickSpacing int) (*TickBitmapProvider, error) {
+	if err := validateTicks(ticks, tickSpacing); err != nil {
+		return nil, err
+	}
+	p := &TickBitmapProvider{
+		tickSpacing: tickSpacing,
+		bitmap:      make(map[int16]*uint256.Int),
+		ticks:       make(map[int]Tick, len(ticks)),
+	}
+	for _, t := range ticks {
+		if _, ok := p.ticks[t.Index]; ok {
+			return nil, ErrTicksNotSorted
+		}
+		p.ticks[t.Index] = t
+		p.flipTick(t.Index)
+	}
+	return p, nil
+}
+
+func (p *TickBitmapProvider) GetTick(tick int) (Tick, error) {
+	t, ok := p.ticks[tick]
+	if !ok {
+		return Tick{}, ErrTickNotFound
+	}
+	return t, nil
+}
+
+/**
+ * Returns the next initialized tick contained in the same word (or adjacent word) as the tick that is either
+ * to the left (less than or equal to) or right (greater than) of the given tick
+ * @param tick The starting tick
+ * @param lte Whether to search for the next initialized tick to the left (less than or equal to the starting tick)
+ * @param tickSpacing The spacing between usable ticks
+ * @returns The next initialized or uninitialized tick up to 256 ticks away from the current tick and whether it is initialized
+ */
+func (p *TickBitmapProvider) NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error) {
+	if tickSpacing != p.tickSpacing {
+		return 0, false, ErrInvalidTickSpacing
+	}
+	compressed := compress(tick, tickSpacing)
+
+	var masked uint256.Int
+	if lte {
+		wordPos, bitPos := position(compressed)
+		// all the 1s at or to the right of the current bitPos
+		masked.Lsh(p.word(wordPos), 255-bitPos)
+
+		// if there are no initialized ticks to the right of or at the current tick, return rightmost in the word
+		initialized := !masked.IsZero()
+		if initialized {
+			return (compressed - int(bitPos) + v4utils.MostSignificantBitU256(&masked) - int(255-bitPos)) * tickSpacing, true, nil
+		}
+		return (compressed - int(bitPos)) * tickSpacing, false, nil
+	}
+
+	// start from the word of the next tick, since the current tick state doesn't matter
+	compressed++
+	wordPos, bitPos := position(compressed)
+	// all the 1s at or to the left of the bitPos
+	masked.Rsh(p.word(wordPos), bitPos)
+
+	// if there are no initialized ticks to the left of the current tick, return leftmost in the word
+	initialized := !masked.IsZero()
+	if initialized {
+		return (compressed + v4utils.LeastSignificantBitU256(&masked)) * tickSpacing, true, nil
+	}
+	return (compressed + 255 - int(bitPos)) * tickSpacing, false, nil
+}
+
+var emptyWord uint256.Int
+
+func (p *TickBitmapProvider) word(wordPos int16) *uint256.Int {
+	if word, ok := p.bitmap[wordPos]; ok {
+		return word
+	}
+	return &emptyWord
+}
+
+// flipTick flips the initialized state of the given tick, see TickBitmap.flipTick
+func (p *TickBitmapProvider) flipTick(tick int) {
+	wordPos, bitPos := position(tick / p.tickSpacing)
+	word, ok := p.bitmap[wordPos]
+	if !ok {
+		word = new(uint256.Int)
+		p.bitmap[wordPos] = word
+	}
+	word[bitPos/64] ^= 1 << (bitPos % 64)
+}
+
+// compress divides the tick by the tick spacing, rounding towards negative infinity
+func compress(tick, tickSpacing int) int {
+	compressed := tick / tickSpacing
+	if tick < 0 && tick%tickSpacing != 0 {
+		compressed--
+	}
+	return compressed
+}
+
+// position computes the position in the bitmap where the initialized bit for a compressed tick lives
+func position(compressed int) (int16, uint) {
+	return int16(compressed >> 8), uint(compressed & 0xff)
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+)
+
+// bitmapTestTicks returns ticks initialized at the indexes, with liquidity summing to zero
+func bitmapTestTicks(indexes ...int) []Tick {
+	ticks := make([]Tick, len(indexes))
+	for i, index := range indexes {
+		net := big.NewInt(1)
+		if i%2 == 1 {
+			net = big.NewInt(-1)
+		}
+		ticks[i] = Tick{Index: index, LiquidityNet: net, LiquidityGross: big.NewInt(1)}
+	}
+	return ticks
+}
+
+// the initialized ticks of the v4-core TickBitmap tests
+var tickBitmapTestIndexes = []int{-200, -55, -4, 70, 78, 84, 139, 240, 535, 600}
+
+func TestNextInitializedTickWithinOneWord(t *testing.T) {
+	provider, err := NewTickBitmapProvider(bitmapTestTicks(tickBitmapTestIndexes...), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tick        int
+		lte         bool
+		next        int
+		initialized bool
+	}{
+		// lte = false
+		{78, false, 84, true},
+		{-55, false, -4, true},
+		{77, false, 78, true},
+		{-56, false, -55, true},
+		{255, false, 511, false},
+		{383, false, 511, false},
+		{-257, false, -200, true},
+		{-513, false, -257, false},
+		{-1, false, 70, true},
+		{-2, false, -1, false},
+		// lte = true
+		{78, true, 78, true},
+		{79, true, 78, true},
+		{258, true, 256, false},
+		{256, true, 256, false},
+		{72, true, 70, true},
+		{-257, true, -512, false},
+		{1023, true, 768, false},
+		{900, true, 768, false},
+		{-1, true, -4, true},
+		{-256, true, -256, false},
+		{-201, true, -256, false},
+	}
+	for _, tt := range tests {
+		next, initialized, err := provider.NextInitializedTickWithinOneWord(tt.tick, tt.lte, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != tt.next || initialized != tt.initialized {
+			t.Errorf("tick %d lte %v: got %d %v, want %d %v", tt.tick, tt.lte, next, initialized, tt.next, tt.initialized)
+		}
+	}
+}
+
+// TestTickBitmapMatchesTickList compares the providers from every tick of several words around zero
+func TestTickBitmapMatchesTickList(t *testing.T) {
+	for _, tickSpacing := range []int{1, 10, 60} {
+		var indexes []int
+		for _, index := range []int{-600, -257, -256, -255, -200, -55, -4, 0, 70, 78, 255, 256, 535, 600} {
+			indexes = append(indexes, index*tickSpacing)
+		}
+		ticks := bitmapTestTicks(indexes...)
+		bitmap, err := NewTickBitmapProvider(ticks, tickSpacing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list, err := NewTickListDataProvider(ticks, tickSpacing)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for tick := -800 * tickSpacing; tick <= 800*tickSpacing; tick += 1 + tickSpacing/7 {
+			for _, lte := range []bool{true, false} {
+				want, wantInitialized, err := list.NextInitializedTickWithinOneWord(tick, lte, tickSpacing)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, gotInitialized, err := bitmap.NextInitializedTickWithinOneWord(tick, lte, tickSpacing)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want || gotInitialized != wantInitialized {
+					t.Fatalf("spacing %d tick %d lte %v: bitmap %d %v, list %d %v", tickSpacing, tick, lte, got, gotInitialized, want, wantInitialized)
+				}
+			}
+		}
+	}
+}
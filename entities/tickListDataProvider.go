@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"sort"
+)
+
+// TickListDataProvider is a TickDataProvider backed by an in-memory list of ticks sorted by index.
+// NextInitializedTickWithinOneWord rounds to word boundaries like TickBitmap.sol.
+type TickListDataProvider struct {
+	tickSpacing int
+	ticks       []Tick
+}
+
+func NewTickListDataProvider(ticks []Tick, tickSpacing int) (*TickListDataProvider, error) {
+	if err := validateTicks(ticks, tickSpacing); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(ticks); i++ {
+		if ticks[i-1].Index >= ticks[i].Index {
+			return nil, ErrTicksNotSorted
+		}
+	}
+	return &TickListDataProvider{tickSpacing: tickSpacing, ticks: ticks}, nil
+}
+
+func (p *TickListDataProvider) GetTick(tick int) (Tick, error) {
+	i := sort.Search(len(p.ticks), func(i int) bool { return p.ticks[i].Index >= tick })
+	if i == len(p.ticks) || p.ticks[i].Index != tick {
+		return Tick{}, ErrTickNotFound
+	}
+	return p.ticks[i], nil
+}
+
+/**
+ * Returns the next initialized tick contained in the same word (or adjacent word) as the tick that is either
+ * to the left (less than or equal to) or right (greater than) of the given tick
+ * @param tick The starting tick
+ * @param lte Whether to search for the next initialized tick to the left (less than or equal to the starting tick)
+ * @param tickSpacing The spacing between usable ticks
+ * @returns The next initialized or uninitialized tick up to 256 ticks away from the current tick and whether it is initialized
+ */
+func (p *TickListDataProvider) NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error) {
+	if tickSpacing != p.tickSpacing {
+		return 0, false, ErrInvalidTickSpacing
+	}
+	compressed := compress(tick, tickSpacing)
+
+	if lte {
+		wordPos, _ := position(compressed)
+		minimum := (int(wordPos) << 8) * tickSpacing
+		// index of the first tick above the given tick
+		i := sort.Search(len(p.ticks), func(i int) bool { return p.ticks[i].Index > tick })
+		if i == 0 || p.ticks[i-1].Index < minimum {
+			return minimum, false, nil
+		}
+		return p.ticks[i-1].Index, true, nil
+	}
+
+	wordPos, _ := position(compressed + 1)
+	maximum := ((int(wordPos) << 8) + 255) * tickSpacing
+	i := sort.Search(len(p.ticks), func(i int) bool { return p.ticks[i].Index > tick })
+	if i == len(p.ticks) || p.ticks[i].Index > maximum {
+		return maximum, false, nil
+	}
+	return p.ticks[i].Index, true, nil
+}
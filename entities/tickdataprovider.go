@@ -1,6 +1,22 @@
 package entities
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
+
+	v4utils "github.com/dangthanhduong01/uniswapv4-sdk/utils"
+)
+
+var (
+	ErrNoTickDataProvider = errors.New("no tick data provider was given")
+	ErrTickNotFound       = errors.New("tick not found")
+	ErrZeroTickSpacing    = errors.New("tick spacing must be greater than 0")
+	ErrInvalidTickSpacing = errors.New("tick is not a multiple of the tick spacing")
+	ErrTickOutOfRange     = errors.New("tick out of range")
+	ErrZeroNet            = errors.New("tick liquidityNet must sum to zero")
+	ErrTicksNotSorted     = errors.New("ticks must be sorted and unique")
+	ErrNilLiquidityNet    = errors.New("nil tick liquidityNet")
+)
 
 type Tick struct {
 	Index          int
@@ -14,7 +30,7 @@ type TickDataProvider interface {
 	 * Return information corresponding to a specific tick
 	 * @param tick the tick to load
 	 */
-	GetTick(tick int) Tick
+	GetTick(tick int) (Tick, error)
 
 	/**
 	 * Return the next tick that is initialized within a single word
@@ -22,8 +38,44 @@ type TickDataProvider interface {
 	 * @param lte Whether the next tick should be lte the current tick
 	 * @param tickSpacing The tick spacing of the pool
 	 */
-	NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool)
+	NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error)
+}
+
+/**
+ * This tick data provider does not know how to fetch any tick data. It returns an error whenever it is required. Useful if you
+ * do not need to load tick data for your use case.
+ */
+type NoTickDataProvider struct{}
+
+func (NoTickDataProvider) GetTick(tick int) (Tick, error) {
+	return Tick{}, ErrNoTickDataProvider
+}
+
+func (NoTickDataProvider) NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error) {
+	return 0, false, ErrNoTickDataProvider
+}
 
-	// NextInitializedTickIndex return the next tick that is initialized
-	NextInitializedTickIndex(tick int, lte bool) (int, bool, error)
+// validateTicks checks that every tick is usable with the tick spacing and that the liquidity
+// added and removed across the ticks cancels out, as it does for any real pool
+func validateTicks(ticks []Tick, tickSpacing int) error {
+	if tickSpacing <= 0 {
+		return ErrZeroTickSpacing
+	}
+	sum := big.NewInt(0)
+	for _, t := range ticks {
+		if t.Index%tickSpacing != 0 {
+			return ErrInvalidTickSpacing
+		}
+		if t.Index < v4utils.MinTick || t.Index > v4utils.MaxTick {
+			return ErrTickOutOfRange
+		}
+		if t.LiquidityNet == nil {
+			return ErrNilLiquidityNet
+		}
+		sum.Add(sum, t.LiquidityNet)
+	}
+	if sum.Sign() != 0 {
+		return ErrZeroNet
+	}
+	return nil
 }
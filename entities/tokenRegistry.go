@@ -0,0 +1,36 @@
+package entities
+
+import (
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TokenRegistry resolves the currency behind an address, e.g. to show symbols when rendering decoded calldata
+type TokenRegistry interface {
+	/**
+	 * Returns the currency at the address
+	 * @param address The token address, the zero address for the native currency
+	 */
+	Currency(address common.Address) (core.Currency, bool)
+}
+
+// CurrencyMap is a TokenRegistry backed by a map from address to currency
+type CurrencyMap map[common.Address]core.Currency
+
+// NewCurrencyMap returns a registry of the currencies, native currencies are registered at the zero address
+func NewCurrencyMap(currencies ...core.Currency) CurrencyMap {
+	m := make(CurrencyMap, len(currencies))
+	for _, currency := range currencies {
+		m.Add(currency)
+	}
+	return m
+}
+
+func (m CurrencyMap) Add(currency core.Currency) {
+	m[currencyAddress(currency)] = currency
+}
+
+func (m CurrencyMap) Currency(address common.Address) (core.Currency, bool) {
+	currency, ok := m[address]
+	return currency, ok
+}
@@ -5,7 +5,7 @@ import (
 	"math/big"
 	"sort"
 
-	v3constants "github.com/KyberNetwork/pancake-v3-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	core "github.com/daoleno/uniswap-sdk-core/entities"
 )
 
@@ -35,10 +35,10 @@ func tradeComparator(a, b *Trade) int {
 		if a.InputAmount().EqualTo(b.InputAmount().Fraction) {
 			var aHops, bHops int
 			for _, swap := range a.Swaps {
-				aHops += len(swap.Route.TokenPath)
+				aHops += len(swap.Route.CurrencyPath)
 			}
 			for _, swap := range b.Swaps {
-				bHops += len(swap.Route.TokenPath)
+				bHops += len(swap.Route.CurrencyPath)
 			}
 			return aHops - bHops
 		}
@@ -175,8 +175,7 @@ func FromRoute(route *Route, amount *core.CurrencyAmount, tradeType core.TradeTy
 			return nil, err
 		}
 		// amounts[0] = tokenAmount //amount.Wrapped()
-		routeTokenPath := len(route.TokenPath)
-		for i := 0; i < routeTokenPath; i++ {
+		for i := 0; i < len(route.Pools); i++ {
 			pool := route.Pools[i]
 			outputAmountResult, err := pool.GetOutputAmount(tokenAmount, nil)
 			if err != nil {
@@ -196,7 +195,7 @@ func FromRoute(route *Route, amount *core.CurrencyAmount, tradeType core.TradeTy
 			return nil, err
 		}
 		// amounts[len(amounts)-1] = amount.Wrapped()
-		for i := len(route.TokenPath) - 1; i > 0; i-- {
+		for i := len(route.CurrencyPath) - 1; i > 0; i-- {
 			pool := route.Pools[i-1]
 			inputAmountResult, err := pool.GetInputAmount(tokenAmount, nil)
 			if err != nil {
@@ -257,10 +256,10 @@ func newTrade(routes []*Swap, tradeType core.TradeType) (*Trade, error) {
 	inputCurrency := routes[0].InputAmount.Currency
 	outputCurrency := routes[0].OutputAmount.Currency
 	for _, route := range routes {
-		if !inputCurrency.Wrapped().Equal(route.Route.Input.Wrapped()) {
+		if !inputCurrency.Equal(route.Route.Input) {
 			return nil, ErrInputCurrencyMismatch
 		}
-		if !outputCurrency.Wrapped().Equal(route.Route.Output.Wrapped()) {
+		if !outputCurrency.Equal(route.Route.Output) {
 			return nil, ErrOutputCurrencyMismatch
 		}
 	}
@@ -270,14 +269,14 @@ func newTrade(routes []*Swap, tradeType core.TradeType) (*Trade, error) {
 		numPools += len(route.Route.Pools)
 	}
 
-	var poolAddressSet = make(map[string]bool)
+	var poolAddressSet = make(map[PoolId]bool)
 	for _, route := range routes {
 		for _, p := range route.Route.Pools {
 			poolid, err := GetPoolId(p.Currency0, p.Currency1, p.Fee, p.TickSpacing, p.Hooks)
 			if err != nil {
 				return nil, err
 			}
-			poolAddressSet[string(poolid)] = true
+			poolAddressSet[poolid] = true
 		}
 	}
 	if len(poolAddressSet) != numPools {
@@ -293,7 +292,7 @@ func (t *Trade) MininumAmountOut(slippageTolerance *core.Percent, amountOut *cor
 	if amountOut == nil {
 		amountOut = t.OutputAmount()
 	}
-	if slippageTolerance.LessThan((v3constants.PercentZero)) {
+	if slippageTolerance.LessThan((constants.PercentZero)) {
 		return nil, ErrInvalidSlippageTolerance
 	}
 	if t.TradeType == core.ExactOutput {
@@ -313,7 +312,7 @@ func (t *Trade) MaximumAmountIn(slippageTolerance *core.Percent, amountIn *core.
 	if amountIn == nil {
 		amountIn = t.InputAmount()
 	}
-	if slippageTolerance.LessThan((v3constants.PercentZero)) {
+	if slippageTolerance.LessThan((constants.PercentZero)) {
 		return nil, ErrInvalidSlippageTolerance
 	}
 	if t.TradeType == core.ExactInput {
@@ -350,7 +349,6 @@ func BestTradeExactIn(
 	if opts == nil {
 		opts = &BestTradeOptions{MaxNumResults: 3, MaxHops: 3}
 	}
-	tokenOut := currencyOut.Wrapped()
 	if nextAmountIn == nil {
 		return nil, ErrInvalidMaxHops
 	}
@@ -359,7 +357,7 @@ func BestTradeExactIn(
 		return nil, ErrInvalidRecursion
 	}
 
-	amountIn := nextAmountIn.Wrapped()
+	amountIn := nextAmountIn
 	for i := 0; i < len(pools); i++ {
 		pool := pools[i]
 		if !pool.Currency0.Equal(amountIn.Currency) &&
@@ -371,7 +369,8 @@ func BestTradeExactIn(
 			return nil, err
 		}
 		amountOut := outputAmountResult.ReturnedAmount
-		if amountOut.Currency.Equal(tokenOut) && amountOut.Currency.IsToken() {
+		// we have arrived at the output currency, so this is the final trade of one of the paths
+		if amountOut.Currency.Equal(currencyOut) {
 			r, err := NewRoute(append(currentPools, pool), currencyAmountIn.Currency, currencyOut)
 			if err != nil {
 				return nil, err
@@ -419,7 +418,6 @@ func BestTradeExactOut(pools []*Pool, currencyIn core.Currency,
 	if opts == nil {
 		opts = &BestTradeOptions{MaxNumResults: 3, MaxHops: 3}
 	}
-	// tokenIn := currencyIn.Wrapped()
 	if nextAmountOut == nil {
 		nextAmountOut = currencyAmountOut
 	}
@@ -430,7 +428,7 @@ func BestTradeExactOut(pools []*Pool, currencyIn core.Currency,
 		return nil, ErrInvalidRecursion
 	}
 
-	amountOut := nextAmountOut.Wrapped()
+	amountOut := nextAmountOut
 	for i := 0; i < len(pools); i++ {
 		pool := pools[i]
 		// pool irrelevant
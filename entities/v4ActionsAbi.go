@@ -0,0 +1,402 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+)
+
+var (
+	ErrUnknownAction       = errors.New("unknown action")
+	ErrInvalidParamsLength = errors.New("number of params does not match the abi definition")
+	ErrInvalidAbiType      = errors.New("invalid abi type")
+	ErrInvalidParamValue   = errors.New("param value cannot be encoded as its abi type")
+	ErrParamOutOfRange     = errors.New("param value out of range of its abi type")
+)
+
+// actionArguments returns the abi arguments of the action as defined in V4_BASE_ACTIONS_ABI_DEFINITION
+func actionArguments(action Actions) (abi.Arguments, error) {
+	paramTypes, ok := V4_BASE_ACTIONS_ABI_DEFINITION[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnknownAction, byte(action))
+	}
+	return paramTypesArguments(paramTypes)
+}
+
+// paramTypesArguments builds the abi arguments of an action or command definition
+func paramTypesArguments(paramTypes []ParamType) (abi.Arguments, error) {
+	arguments := make(abi.Arguments, 0, len(paramTypes))
+	for _, param := range paramTypes {
+		marshaling, err := parseAbiType(param.Type)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := abi.NewType(marshaling.Type, "", marshaling.Components)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, abi.Argument{Name: param.Name, Type: typ})
+	}
+	return arguments, nil
+}
+
+/**
+ * Parses a human readable abi type such as "uint24" or "(address currency0,address currency1)[]" into
+ * the form abi.NewType expects, tuples become "tuple" with their named components
+ * @param typ The type, optionally followed by a space and a name
+ */
+func parseAbiType(typ string) (abi.ArgumentMarshaling, error) {
+	typ = strings.TrimSpace(typ)
+	var marshaling abi.ArgumentMarshaling
+
+	// a name follows the last space outside of parentheses
+	if i := lastIndexOutsideParens(typ, ' '); i >= 0 {
+		marshaling.Name = strings.TrimSpace(typ[i+1:])
+		typ = strings.TrimSpace(typ[:i])
+	}
+	if typ == "" {
+		return abi.ArgumentMarshaling{}, ErrInvalidAbiType
+	}
+	if typ[0] != '(' {
+		marshaling.Type = typ
+		return marshaling, nil
+	}
+
+	end := matchingParen(typ)
+	if end < 0 {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("%w: %s", ErrInvalidAbiType, typ)
+	}
+	// array suffixes such as "[]" or "[2]" apply to the whole tuple
+	marshaling.Type = "tuple" + typ[end+1:]
+	inner := typ[1:end]
+	if strings.TrimSpace(inner) == "" {
+		return marshaling, nil
+	}
+	for _, component := range splitOutsideParens(inner, ',') {
+		c, err := parseAbiType(component)
+		if err != nil {
+			return abi.ArgumentMarshaling{}, err
+		}
+		if c.Name == "" {
+			return abi.ArgumentMarshaling{}, fmt.Errorf("%w: unnamed tuple component %s", ErrInvalidAbiType, component)
+		}
+		marshaling.Components = append(marshaling.Components, c)
+	}
+	return marshaling, nil
+}
+
+func matchingParen(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func lastIndexOutsideParens(s string, sep rune) int {
+	depth, last := 0, -1
+	for i, c := range s {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			last = i
+		}
+	}
+	return last
+}
+
+func splitOutsideParens(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+/**
+ * Converts a param value into the go type go-ethereum packs for the abi type. Integers may be given as any go
+ * integer, *big.Int, *uint256.Int, decimal or 0x prefixed string or *core.CurrencyAmount, addresses as
+ * common.Address, hex string or core.Currency, bytes as []byte or hex string, fixed bytes as any byte array of the
+ * same size (e.g. PoolId) and tuples as structs with matching field names (e.g. PoolKey, PathKey), maps keyed by
+ * component name or slices in component order
+ */
+func toAbiValue(typ abi.Type, value interface{}) (reflect.Value, error) {
+	// values already of the packed go type are used as is, integers still get their range checked
+	if value != nil && typ.T != abi.IntTy && typ.T != abi.UintTy && typ.T != abi.TupleTy && reflect.TypeOf(value) == typ.GetType() {
+		return reflect.ValueOf(value), nil
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toBigInt(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !fitsAbiInt(n, typ.Size, typ.T == abi.IntTy) {
+			return reflect.Value{}, fmt.Errorf("%w: %s %s", ErrParamOutOfRange, n, typ)
+		}
+		goType := typ.GetType()
+		if goType == reflect.TypeOf((*big.Int)(nil)) {
+			return reflect.ValueOf(new(big.Int).Set(n)), nil
+		}
+		v := reflect.New(goType).Elem()
+		if typ.T == abi.IntTy {
+			v.SetInt(n.Int64())
+		} else {
+			v.SetUint(n.Uint64())
+		}
+		return v, nil
+
+	case abi.BoolTy:
+		if b, ok := value.(bool); ok {
+			return reflect.ValueOf(b), nil
+		}
+
+	case abi.AddressTy:
+		switch v := value.(type) {
+		case *common.Address:
+			if v != nil {
+				return reflect.ValueOf(*v), nil
+			}
+		case string:
+			if common.IsHexAddress(v) {
+				return reflect.ValueOf(common.HexToAddress(v)), nil
+			}
+		case core.Currency:
+			if v != nil {
+				return reflect.ValueOf(currencyAddress(v)), nil
+			}
+		}
+
+	case abi.BytesTy:
+		switch v := value.(type) {
+		case nil:
+			return reflect.ValueOf([]byte{}), nil
+		case string:
+			b, err := hexutil.Decode(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%w: %s", ErrInvalidParamValue, err)
+			}
+			return reflect.ValueOf(b), nil
+		}
+
+	case abi.FixedBytesTy:
+		// named arrays such as PoolId or common.Hash convert to [N]byte
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Array && v.Type().ConvertibleTo(typ.GetType()) {
+			return v.Convert(typ.GetType()), nil
+		}
+		if b, ok := value.([]byte); ok && len(b) == typ.Size {
+			out := reflect.New(typ.GetType()).Elem()
+			reflect.Copy(out, reflect.ValueOf(b))
+			return out, nil
+		}
+
+	case abi.TupleTy:
+		return toAbiTuple(typ, value)
+
+	case abi.SliceTy, abi.ArrayTy:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			break
+		}
+		if typ.T == abi.ArrayTy && v.Len() != typ.Size {
+			return reflect.Value{}, fmt.Errorf("%w: %s expects %d elements, got %d", ErrInvalidParamsLength, typ, typ.Size, v.Len())
+		}
+		var out reflect.Value
+		if typ.T == abi.SliceTy {
+			out = reflect.MakeSlice(typ.GetType(), v.Len(), v.Len())
+		} else {
+			out = reflect.New(typ.GetType()).Elem()
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := toAbiValue(*typ.Elem, v.Index(i).Interface())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("%w: %T as %s", ErrInvalidParamValue, value, typ)
+}
+
+func toAbiTuple(typ abi.Type, value interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	out := reflect.New(typ.TupleType).Elem()
+	for i, elemType := range typ.TupleElems {
+		name := typ.TupleRawNames[i]
+		var field reflect.Value
+		switch v.Kind() {
+		case reflect.Struct:
+			field = v.FieldByName(abi.ToCamelCase(name))
+		case reflect.Slice, reflect.Array:
+			if v.Len() != len(typ.TupleElems) {
+				return reflect.Value{}, fmt.Errorf("%w: %s expects %d components, got %d", ErrInvalidParamsLength, typ, len(typ.TupleElems), v.Len())
+			}
+			field = v.Index(i)
+		case reflect.Map:
+			if v.Type().Key().Kind() == reflect.String {
+				field = v.MapIndex(reflect.ValueOf(name))
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("%w: %T as %s", ErrInvalidParamValue, value, typ)
+		}
+		if !field.IsValid() || !field.CanInterface() {
+			return reflect.Value{}, fmt.Errorf("%w: missing %s of %s", ErrInvalidParamValue, name, typ)
+		}
+		elem, err := toAbiValue(*elemType, field.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Field(i).Set(elem)
+	}
+	return out, nil
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return v, nil
+		}
+	case *uint256.Int:
+		if v != nil {
+			return v.ToBig(), nil
+		}
+	case *core.CurrencyAmount:
+		if v != nil {
+			return v.Quotient(), nil
+		}
+	case string:
+		if n, ok := new(big.Int).SetString(v, 0); ok {
+			return n, nil
+		}
+	default:
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return big.NewInt(rv.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return new(big.Int).SetUint64(rv.Uint()), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %T as integer", ErrInvalidParamValue, value)
+}
+
+// fitsAbiInt reports whether n is representable as an abi int<size> or uint<size>
+func fitsAbiInt(n *big.Int, size int, signed bool) bool {
+	if !signed {
+		return n.Sign() >= 0 && n.BitLen() <= size
+	}
+	if n.Sign() >= 0 {
+		return n.BitLen() < size
+	}
+	// -2^(size-1) is the smallest value, so -(n+1) must fit in size-1 bits
+	return new(big.Int).Add(n, big.NewInt(1)).BitLen() < size
+}
+
+// mustNewArguments builds abi arguments from human readable types, it panics on an invalid type so it is only
+// used for package level definitions
+func mustNewArguments(types ...string) abi.Arguments {
+	arguments := make(abi.Arguments, 0, len(types))
+	for _, t := range types {
+		marshaling, err := parseAbiType(t)
+		if err != nil {
+			panic(err)
+		}
+		typ, err := abi.NewType(marshaling.Type, "", marshaling.Components)
+		if err != nil {
+			panic(err)
+		}
+		arguments = append(arguments, abi.Argument{Name: marshaling.Name, Type: typ})
+	}
+	return arguments
+}
+
+// mustNewMethod builds a contract method from human readable input and output types, see mustNewArguments
+func mustNewMethod(name string, inputs []string, outputs []string) abi.Method {
+	return abi.NewMethod(name, name, abi.Function, "", false, false, mustNewArguments(inputs...), mustNewArguments(outputs...))
+}
+
+// mustNewError builds a custom error from human readable input types, see mustNewArguments
+func mustNewError(name string, inputs ...string) abi.Error {
+	return abi.NewError(name, mustNewArguments(inputs...))
+}
+
+// mustNewEvent builds an event from human readable input types, see mustNewArguments. Indexed inputs are marked
+// with the indexed keyword as in solidity, e.g. "address indexed sender"
+func mustNewEvent(name string, inputs ...string) abi.Event {
+	indexed := make([]bool, len(inputs))
+	types := make([]string, len(inputs))
+	for i, input := range inputs {
+		types[i] = input
+		if j := strings.Index(input, " indexed "); j >= 0 {
+			indexed[i] = true
+			types[i] = input[:j] + input[j+len(" indexed"):]
+		}
+	}
+	arguments := mustNewArguments(types...)
+	for i := range arguments {
+		arguments[i].Indexed = indexed[i]
+	}
+	return abi.NewEvent(name, name, false, arguments)
+}
+
+// encodeArguments converts the params with toAbiValue and packs them
+func encodeArguments(arguments abi.Arguments, params ...interface{}) ([]byte, error) {
+	if len(params) != len(arguments) {
+		return nil, fmt.Errorf("%w: expected %d params, got %d", ErrInvalidParamsLength, len(arguments), len(params))
+	}
+	values := make([]interface{}, len(params))
+	for i, argument := range arguments {
+		value, err := toAbiValue(argument.Type, params[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", argument.Name, err)
+		}
+		values[i] = value.Interface()
+	}
+	return arguments.Pack(values...)
+}
+
+// encodeMethodCall returns the calldata of the method, its selector followed by the encoded params
+func encodeMethodCall(method abi.Method, params ...interface{}) ([]byte, error) {
+	encoded, err := encodeArguments(method.Inputs, params...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method.Name, err)
+	}
+	return append(append([]byte{}, method.ID...), encoded...), nil
+}
@@ -1,173 +1,474 @@
 package entities
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"math/big"
-	"strconv"
+	"reflect"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var ErrInvalidActionParams = errors.New("decoded params do not match the action")
+
 type Param struct {
-	name  string
-	value interface{}
+	Name  string
+	Value interface{}
 }
 
 type V4RouterAction struct {
-	actionName string
-	actionType Actions
-	params     []Param
+	ActionName string
+	ActionType Actions
+	// Params are the abi decoded params in definition order, named as in V4_BASE_ACTIONS_ABI_DEFINITION
+	Params []Param
+	// Decoded is the typed form of Params, e.g. a SwapExactIn for SWAP_EXACT_IN or a Settle for SETTLE
+	Decoded interface{}
 }
 
 type V4RouterCall struct {
-	actions []V4RouterAction
+	Actions []V4RouterAction
+}
+
+// Values returns the param values, they can be passed back to V4Planner.AddActions to encode the action again
+func (a V4RouterAction) Values() []interface{} {
+	values := make([]interface{}, len(a.Params))
+	for i, param := range a.Params {
+		values[i] = param.Value
+	}
+	return values
 }
 
 type SwapExactInSingle struct {
-	poolKey          PoolKey
-	zeroForOne       bool
-	amountIn         *big.Int
-	amountOutMinimum *big.Int
-	hookData         []byte
+	PoolKey          PoolKey
+	ZeroForOne       bool
+	AmountIn         *big.Int
+	AmountOutMinimum *big.Int
+	HookData         []byte
 }
 
 type SwapExactIn struct {
-	currencyIn       *big.Int
-	path             []PathKey
-	amountIn         *big.Int
-	amountOutMinimum *big.Int
+	CurrencyIn       common.Address
+	Path             []PathKey
+	AmountIn         *big.Int
+	AmountOutMinimum *big.Int
 }
 
 type SwapExactOutSingle struct {
-	poolKey         PoolKey
-	zeroForOne      bool
-	amountOut       *big.Int
-	amountInMaximum *big.Int
-	hookData        []byte
+	PoolKey         PoolKey
+	ZeroForOne      bool
+	AmountOut       *big.Int
+	AmountInMaximum *big.Int
+	HookData        []byte
 }
 
 type SwapExactOut struct {
-	currencyOut     *big.Int
-	path            []PathKey
-	amountIn        *big.Int
-	amountInMaximum *big.Int
+	CurrencyOut     common.Address
+	Path            []PathKey
+	AmountOut       *big.Int
+	AmountInMaximum *big.Int
 }
 
-type V4BaseActionsParser struct {
+type IncreaseLiquidity struct {
+	TokenId    *big.Int
+	Liquidity  *big.Int
+	Amount0Max *big.Int
+	Amount1Max *big.Int
+	HookData   []byte
 }
 
-// func ParseCallData(calldata []byte) V4RouterCall {
-// func ParseCallData(calldata []byte) *V4RouterCall {
-// 	actions, inputs, err := abiEnCoder(calldata)
-// 	if err != nil {
-// 		return nil
-// 	}
-// 	actionTypes := getActions(actions)
+type DecreaseLiquidity struct {
+	TokenId    *big.Int
+	Liquidity  *big.Int
+	Amount0Min *big.Int
+	Amount1Min *big.Int
+	HookData   []byte
+}
 
-// 	for i, actionType := range actionTypes {
+type MintPosition struct {
+	PoolKey    PoolKey
+	TickLower  int
+	TickUpper  int
+	Liquidity  *big.Int
+	Amount0Max *big.Int
+	Amount1Max *big.Int
+	Owner      common.Address
+	HookData   []byte
+}
 
-// 	}
+type BurnPosition struct {
+	TokenId    *big.Int
+	Amount0Min *big.Int
+	Amount1Min *big.Int
+	HookData   []byte
+}
 
-// }
+type IncreaseLiquidityFromDeltas struct {
+	TokenId    *big.Int
+	Amount0Max *big.Int
+	Amount1Max *big.Int
+	HookData   []byte
+}
 
-func getActions(actions []byte) []Actions {
-	var actionTypes []Actions
-	for i := 2; i < len(actions); i += 1 {
-		b := actions[i]
-		actionTypes = append(actionTypes, Actions(b))
-	}
-	return actionTypes
+type MintPositionFromDeltas struct {
+	PoolKey    PoolKey
+	TickLower  int
+	TickUpper  int
+	Amount0Max *big.Int
+	Amount1Max *big.Int
+	Owner      common.Address
+	HookData   []byte
+}
+
+type Donate struct {
+	PoolKey  PoolKey
+	Amount0  *big.Int
+	Amount1  *big.Int
+	HookData []byte
+}
+
+type Settle struct {
+	Currency    common.Address
+	Amount      *big.Int
+	PayerIsUser bool
+}
+
+type SettleAll struct {
+	Currency  common.Address
+	MaxAmount *big.Int
+}
+
+type SettlePair struct {
+	Currency0 common.Address
+	Currency1 common.Address
+}
+
+type Take struct {
+	Currency  common.Address
+	Recipient common.Address
+	Amount    *big.Int
+}
+
+type TakeAll struct {
+	Currency  common.Address
+	MinAmount *big.Int
+}
+
+type TakePortion struct {
+	Currency  common.Address
+	Recipient common.Address
+	Bips      *big.Int
+}
+
+type TakePair struct {
+	Currency0 common.Address
+	Currency1 common.Address
+	Recipient common.Address
+}
+
+type CloseCurrency struct {
+	Currency common.Address
+}
+
+type ClearOrTake struct {
+	Currency  common.Address
+	AmountMax *big.Int
+}
+
+type Sweep struct {
+	Currency  common.Address
+	Recipient common.Address
+}
+
+type Wrap struct {
+	Amount *big.Int
+}
+
+type Unwrap struct {
+	Amount *big.Int
 }
 
-func abiEnCoder(calldata []byte) ([]byte, [][]byte, error) {
-	bytesType, _ := abi.NewType("bytes", "", nil)
-	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+type Mint6909 struct {
+	Currency  common.Address
+	Recipient common.Address
+	Amount    *big.Int
+}
+
+type Burn6909 struct {
+	Currency common.Address
+	Amount   *big.Int
+}
 
-	arguments := abi.Arguments{
-		{Type: bytesType},
-		{Type: bytesArrayType},
+/**
+ * Decodes the unlockData of the position manager or the router, i.e. abi.encode(bytes actions, bytes[] params) as
+ * produced by V4Planner.Finalize, into its actions. modifyLiquidities calldata is unwrapped first.
+ * @param calldata The unlockData or modifyLiquidities calldata
+ */
+func ParseCalldata(calldata []byte) (*V4RouterCall, error) {
+	if bytes.HasPrefix(calldata, modifyLiquiditiesMethod.ID) {
+		args, err := modifyLiquiditiesMethod.Inputs.Unpack(calldata[len(modifyLiquiditiesMethod.ID):])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidActionParams, err)
+		}
+		calldata, _ = args[0].([]byte)
 	}
 
-	decoded, err := arguments.Unpack(calldata)
+	actions, inputs, err := decodeUnlockData(calldata)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	if len(actions) != len(inputs) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidActionParams, ErrActionsParamsMismatch)
+	}
+
+	call := &V4RouterCall{Actions: make([]V4RouterAction, 0, len(actions))}
+	for i, a := range actions {
+		action := Actions(a)
+		arguments, err := actionArguments(action)
+		if err != nil {
+			return nil, err
+		}
+		values, err := arguments.Unpack(inputs[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidActionParams, action, err)
+		}
+		params := make([]Param, len(values))
+		for j, value := range values {
+			params[j] = Param{Name: arguments[j].Name, Value: value}
+		}
+		decoded, err := decodeAction(action, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", action, err)
+		}
+		call.Actions = append(call.Actions, V4RouterAction{
+			ActionName: action.String(),
+			ActionType: action,
+			Params:     params,
+			Decoded:    decoded,
+		})
+	}
+	return call, nil
+}
+
+func decodeUnlockData(calldata []byte) ([]byte, [][]byte, error) {
+	decoded, err := unlockDataArguments.Unpack(calldata)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidActionParams, err)
 	}
 
 	actions, _ := decoded[0].([]byte)
-	inputsIface, _ := decoded[1].([][]byte)
+	inputs, _ := decoded[1].([][]byte)
 
-	return actions, inputsIface, nil
+	return actions, inputs, nil
+}
 
+// decodeAction converts the abi decoded params of the action into its typed struct
+func decodeAction(action Actions, values []interface{}) (interface{}, error) {
+	r := &paramReader{values: values}
+	var decoded interface{}
+	switch action {
+	case INCREASE_LIQUIDITY:
+		decoded = IncreaseLiquidity{r.bigInt(0), r.bigInt(1), r.bigInt(2), r.bigInt(3), r.bytes(4)}
+	case DECREASE_LIQUIDITY:
+		decoded = DecreaseLiquidity{r.bigInt(0), r.bigInt(1), r.bigInt(2), r.bigInt(3), r.bytes(4)}
+	case MINT_POSITION:
+		decoded = MintPosition{r.poolKey(0), r.int(1), r.int(2), r.bigInt(3), r.bigInt(4), r.bigInt(5), r.address(6), r.bytes(7)}
+	case BURN_POSITION:
+		decoded = BurnPosition{r.bigInt(0), r.bigInt(1), r.bigInt(2), r.bytes(3)}
+	case INCREASE_LIQUIDITY_FROM_DELTAS:
+		decoded = IncreaseLiquidityFromDeltas{r.bigInt(0), r.bigInt(1), r.bigInt(2), r.bytes(3)}
+	case MINT_POSITION_FROM_DELTAS:
+		decoded = MintPositionFromDeltas{r.poolKey(0), r.int(1), r.int(2), r.bigInt(3), r.bigInt(4), r.address(5), r.bytes(6)}
+	case SWAP_EXACT_IN_SINGLE:
+		s := r.tuple(0)
+		decoded = SwapExactInSingle{s.poolKey(0), s.bool(1), s.bigInt(2), s.bigInt(3), s.bytes(4)}
+		r.err = s.err
+	case SWAP_EXACT_IN:
+		s := r.tuple(0)
+		decoded = SwapExactIn{s.address(0), s.pathKeys(1), s.bigInt(2), s.bigInt(3)}
+		r.err = s.err
+	case SWAP_EXACT_OUT_SINGLE:
+		s := r.tuple(0)
+		decoded = SwapExactOutSingle{s.poolKey(0), s.bool(1), s.bigInt(2), s.bigInt(3), s.bytes(4)}
+		r.err = s.err
+	case SWAP_EXACT_OUT:
+		s := r.tuple(0)
+		decoded = SwapExactOut{s.address(0), s.pathKeys(1), s.bigInt(2), s.bigInt(3)}
+		r.err = s.err
+	case DONATE:
+		decoded = Donate{r.poolKey(0), r.bigInt(1), r.bigInt(2), r.bytes(3)}
+	case SETTLE:
+		decoded = Settle{r.address(0), r.bigInt(1), r.bool(2)}
+	case SETTLE_ALL:
+		decoded = SettleAll{r.address(0), r.bigInt(1)}
+	case SETTLE_PAIR:
+		decoded = SettlePair{r.address(0), r.address(1)}
+	case TAKE:
+		decoded = Take{r.address(0), r.address(1), r.bigInt(2)}
+	case TAKE_ALL:
+		decoded = TakeAll{r.address(0), r.bigInt(1)}
+	case TAKE_PORTION:
+		decoded = TakePortion{r.address(0), r.address(1), r.bigInt(2)}
+	case TAKE_PAIR:
+		decoded = TakePair{r.address(0), r.address(1), r.address(2)}
+	case CLOSE_CURRENCY:
+		decoded = CloseCurrency{r.address(0)}
+	case CLEAR_OR_TAKE:
+		decoded = ClearOrTake{r.address(0), r.bigInt(1)}
+	case SWEEP:
+		decoded = Sweep{r.address(0), r.address(1)}
+	case WRAP:
+		decoded = Wrap{r.bigInt(0)}
+	case UNWRAP:
+		decoded = Unwrap{r.bigInt(0)}
+	case MINT_6909:
+		decoded = Mint6909{r.address(0), r.address(1), r.bigInt(2)}
+	case BURN_6909:
+		decoded = Burn6909{r.address(0), r.bigInt(1)}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return decoded, nil
 }
 
-func parsePoolKey(data []interface{}) PoolKey {
-	currency0 := data[0].(common.Address)
-	currency1 := data[1].(common.Address)
-	fee := toInt64(data[2])
-	tickSpacing := toInt64(data[3])
-	hooks := data[4].(common.Address)
+// paramReader reads abi decoded values by index, the first mismatch is kept in err and zero values are returned after it
+type paramReader struct {
+	values []interface{}
+	err    error
+}
 
-	return PoolKey{
-		Currency0:   currency0,
-		Currency1:   currency1,
-		Fee:         fee,
-		TickSpacing: tickSpacing,
-		Hooks:       hooks,
+func (r *paramReader) value(i int) interface{} {
+	if r.err != nil {
+		return nil
 	}
+	if i >= len(r.values) {
+		r.err = ErrInvalidActionParams
+		return nil
+	}
+	return r.values[i]
 }
 
-func parsePathKey(data []interface{}) PathKey {
-	intermediateCurrency := data[0].(common.Address)
-	fee := toInt64(data[1])
-	tickSpacing := toInt64(data[2])
-	hooks := data[3].(common.Address)
-	hookData := data[4].([]byte)
+func (r *paramReader) fail(i int) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: unexpected %T at %d", ErrInvalidActionParams, r.values[i], i)
+	}
+}
 
-	return PathKey{
-		IntermediateCurrency: intermediateCurrency,
-		Fee:                  fee,
-		TickSpacing:          tickSpacing,
-		Hooks:                hooks,
-		HookData:             hookData,
+func (r *paramReader) bigInt(i int) *big.Int {
+	v, ok := r.value(i).(*big.Int)
+	if !ok {
+		r.fail(i)
+		return new(big.Int)
 	}
+	return v
 }
 
-func parseV4ExactIn(data []interface{}) SwapExactIn {
-	currencyIn, _ := data[0].(*big.Int)
-	rawPath := data[1].([]interface{})
-	amountIn, _ := data[2].(*big.Int)
-	amountOutMinimum, _ := data[3].(*big.Int)
+// int reads an int24, e.g. a tick
+func (r *paramReader) int(i int) int {
+	return int(r.intN(i, 24, true))
+}
 
-	var paths []PathKey
-	for _, pathKey := range rawPath {
-		// Giả sử parsePathKey nhận vào string, nếu không thì sửa lại cho phù hợp
-		pk := parsePathKey(pathKey.([]interface{}))
-		paths = append(paths, pk)
+// intN reads an abi int<size> or uint<size> of at most 64 bits, go-ethereum decodes them without checking their range
+func (r *paramReader) intN(i int, size int, signed bool) int64 {
+	v := r.bigInt(i)
+	if r.err != nil {
+		return 0
 	}
+	if !fitsAbiInt(v, size, signed) {
+		r.err = fmt.Errorf("%w: %s out of range of %d bits at %d", ErrInvalidActionParams, v, size, i)
+		return 0
+	}
+	return v.Int64()
+}
 
-	return SwapExactIn{
-		currencyIn:       currencyIn,
-		path:             paths,
-		amountIn:         amountIn,
-		amountOutMinimum: amountOutMinimum,
+func (r *paramReader) address(i int) common.Address {
+	v, ok := r.value(i).(common.Address)
+	if !ok {
+		r.fail(i)
 	}
+	return v
 }
 
-// Helper function to convert interface{} to int
-func toInt64(val interface{}) int64 {
-	switch v := val.(type) {
-	case int:
-		return int64(v)
-	case int32:
-		return int64(v)
-	// case int64:
-	// 	return int(v)
-	case float64:
-		return int64(v)
-	case string:
-		i, _ := strconv.Atoi(v)
-		return int64(i)
-	default:
-		return 0
+func (r *paramReader) bool(i int) bool {
+	v, ok := r.value(i).(bool)
+	if !ok {
+		r.fail(i)
+	}
+	return v
+}
+
+func (r *paramReader) bytes(i int) []byte {
+	v, ok := r.value(i).([]byte)
+	if !ok {
+		r.fail(i)
+	}
+	return v
+}
+
+func (r *paramReader) hash(i int) common.Hash {
+	v, ok := r.value(i).([32]byte)
+	if !ok {
+		r.fail(i)
+	}
+	return v
+}
+
+// tuple returns a reader over the components of the tuple, decoded by go-ethereum as an anonymous struct
+func (r *paramReader) tuple(i int) *paramReader {
+	v := reflect.ValueOf(r.value(i))
+	if v.Kind() != reflect.Struct {
+		r.fail(i)
+		return &paramReader{err: r.err}
+	}
+	values := make([]interface{}, v.NumField())
+	for j := range values {
+		values[j] = v.Field(j).Interface()
+	}
+	return &paramReader{values: values}
+}
+
+func (r *paramReader) poolKey(i int) PoolKey {
+	t := r.tuple(i)
+	key := PoolKey{
+		Currency0:   t.address(0),
+		Currency1:   t.address(1),
+		Fee:         t.intN(2, 24, false),
+		TickSpacing: t.intN(3, 24, true),
+		Hooks:       t.address(4),
+	}
+	if r.err == nil {
+		r.err = t.err
+	}
+	return key
+}
+
+func (r *paramReader) pathKeys(i int) []PathKey {
+	v := reflect.ValueOf(r.value(i))
+	if v.Kind() != reflect.Slice {
+		r.fail(i)
+		return nil
+	}
+	path := make([]PathKey, v.Len())
+	elems := &paramReader{values: make([]interface{}, v.Len())}
+	for j := range path {
+		elems.values[j] = v.Index(j).Interface()
+		t := elems.tuple(j)
+		path[j] = PathKey{
+			IntermediateCurrency: t.address(0),
+			Fee:                  t.intN(1, 24, false),
+			TickSpacing:          t.intN(2, 24, true),
+			Hooks:                t.address(3),
+			HookData:             t.bytes(4),
+		}
+		if elems.err == nil {
+			elems.err = t.err
+		}
+	}
+	if r.err == nil {
+		r.err = elems.err
 	}
+	return path
 }
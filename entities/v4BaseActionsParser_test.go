@@ -0,0 +1,273 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	parserTestKey = PoolKey{
+		Currency0:   common.Address{},
+		Currency1:   common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Fee:         3000,
+		TickSpacing: 60,
+		Hooks:       common.HexToAddress("0x0000000000000000000000000000000000000080"),
+	}
+	parserTestOwner = common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	parserTestPath  = []PathKey{
+		{IntermediateCurrency: common.HexToAddress("0x0000000000000000000000000000000000000002"), Fee: 500, TickSpacing: 10, HookData: []byte{}},
+		{IntermediateCurrency: common.HexToAddress("0x0000000000000000000000000000000000000003"), Fee: 10000, TickSpacing: 200,
+			Hooks: common.HexToAddress("0x0000000000000000000000000000000000000040"), HookData: []byte{1, 2, 3}},
+	}
+)
+
+// parserTestCurrency returns the token as the currency pointer taken by the planner helpers
+func parserTestCurrency(address string) *core.Currency {
+	var currency core.Currency = core.NewToken(1, common.HexToAddress(address), 18, "T", "token")
+	return &currency
+}
+
+func TestParseCalldataRoundTrip(t *testing.T) {
+	hookData := []byte{0xde, 0xad}
+	currency := parserTestCurrency("0x0000000000000000000000000000000000000001")
+	other := parserTestCurrency("0x0000000000000000000000000000000000000002")
+	currencyAddr := (*currency).Wrapped().Address
+	otherAddr := (*other).Wrapped().Address
+	n := big.NewInt
+
+	tests := []struct {
+		action  Actions
+		add     func(p *V4Planner) (*V4Planner, error)
+		decoded interface{}
+	}{
+		{INCREASE_LIQUIDITY, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddIncreaseLiquidity(n(1), n(2), n(3), n(4), hookData)
+		},
+			IncreaseLiquidity{n(1), n(2), n(3), n(4), hookData}},
+		{DECREASE_LIQUIDITY, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddDecreaseLiquidity(n(1), n(2), n(3), n(4), hookData)
+		},
+			DecreaseLiquidity{n(1), n(2), n(3), n(4), hookData}},
+		{MINT_POSITION, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddMintPosition(parserTestKey, -887220, 600, n(5), n(6), n(7), parserTestOwner, hookData)
+		}, MintPosition{parserTestKey, -887220, 600, n(5), n(6), n(7), parserTestOwner, hookData}},
+		{BURN_POSITION, func(p *V4Planner) (*V4Planner, error) { return p.AddBurnPosition(n(1), n(2), n(3), hookData) },
+			BurnPosition{n(1), n(2), n(3), hookData}},
+		{INCREASE_LIQUIDITY_FROM_DELTAS, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddIncreaseLiquidityFromDeltas(n(1), n(2), n(3), hookData)
+		}, IncreaseLiquidityFromDeltas{n(1), n(2), n(3), hookData}},
+		{MINT_POSITION_FROM_DELTAS, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddMintPositionFromDeltas(parserTestKey, -60, 887220, n(2), n(3), parserTestOwner, hookData)
+		}, MintPositionFromDeltas{parserTestKey, -60, 887220, n(2), n(3), parserTestOwner, hookData}},
+		{SWAP_EXACT_IN_SINGLE, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddSwapExactInSingle(parserTestKey, true, n(100), n(90), hookData)
+		}, SwapExactInSingle{parserTestKey, true, n(100), n(90), hookData}},
+		{SWAP_EXACT_IN, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddSwapExactIn(currency, parserTestPath, n(100), n(90))
+		},
+			SwapExactIn{currencyAddr, parserTestPath, n(100), n(90)}},
+		{SWAP_EXACT_OUT_SINGLE, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddSwapExactOutSingle(parserTestKey, false, n(100), n(110), hookData)
+		}, SwapExactOutSingle{parserTestKey, false, n(100), n(110), hookData}},
+		{SWAP_EXACT_OUT, func(p *V4Planner) (*V4Planner, error) {
+			return p.AddSwapExactOut(currency, parserTestPath, n(100), n(110))
+		},
+			SwapExactOut{currencyAddr, parserTestPath, n(100), n(110)}},
+		{DONATE, func(p *V4Planner) (*V4Planner, error) { return p.AddDonate(parserTestKey, n(1), n(2), hookData) },
+			Donate{parserTestKey, n(1), n(2), hookData}},
+		{SETTLE, func(p *V4Planner) (*V4Planner, error) { return p.AddSettle(currency, true, n(7)) },
+			Settle{currencyAddr, n(7), true}},
+		{SETTLE_ALL, func(p *V4Planner) (*V4Planner, error) { return p.AddSettleAll(currency, n(7)) },
+			SettleAll{currencyAddr, n(7)}},
+		{SETTLE_PAIR, func(p *V4Planner) (*V4Planner, error) { return p.AddSettlePair(currency, other) },
+			SettlePair{currencyAddr, otherAddr}},
+		{TAKE, func(p *V4Planner) (*V4Planner, error) { return p.AddTake(currency, parserTestOwner, n(7)) },
+			Take{currencyAddr, parserTestOwner, n(7)}},
+		{TAKE_ALL, func(p *V4Planner) (*V4Planner, error) { return p.AddTakeAll(currency, n(7)) },
+			TakeAll{currencyAddr, n(7)}},
+		{TAKE_PORTION, func(p *V4Planner) (*V4Planner, error) { return p.AddTakePortion(currency, parserTestOwner, n(25)) },
+			TakePortion{currencyAddr, parserTestOwner, n(25)}},
+		{TAKE_PAIR, func(p *V4Planner) (*V4Planner, error) { return p.AddTakePair(currency, other, parserTestOwner) },
+			TakePair{currencyAddr, otherAddr, parserTestOwner}},
+		{CLOSE_CURRENCY, func(p *V4Planner) (*V4Planner, error) { return p.AddCloseCurrency(currency) },
+			CloseCurrency{currencyAddr}},
+		{CLEAR_OR_TAKE, func(p *V4Planner) (*V4Planner, error) { return p.AddClearOrTake(currency, n(7)) },
+			ClearOrTake{currencyAddr, n(7)}},
+		{SWEEP, func(p *V4Planner) (*V4Planner, error) { return p.AddSweep(currency, parserTestOwner) },
+			Sweep{currencyAddr, parserTestOwner}},
+		{WRAP, func(p *V4Planner) (*V4Planner, error) { return p.AddWrap(n(7)) }, Wrap{n(7)}},
+		{UNWRAP, func(p *V4Planner) (*V4Planner, error) { return p.AddUnwrap(n(7)) }, Unwrap{n(7)}},
+		{MINT_6909, func(p *V4Planner) (*V4Planner, error) { return p.AddMint6909(currency, parserTestOwner, n(7)) },
+			Mint6909{currencyAddr, parserTestOwner, n(7)}},
+		{BURN_6909, func(p *V4Planner) (*V4Planner, error) { return p.AddBurn6909(currency, n(7)) },
+			Burn6909{currencyAddr, n(7)}},
+	}
+
+	// every action alone, then all of them in one call
+	all := NewV4Planner()
+	for _, tt := range tests {
+		t.Run(tt.action.String(), func(t *testing.T) {
+			planner, err := tt.add(NewV4Planner())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.add(all); err != nil {
+				t.Fatal(err)
+			}
+			expectParsedActions(t, planner, []Actions{tt.action}, []interface{}{tt.decoded})
+		})
+	}
+	actions := make([]Actions, len(tests))
+	decoded := make([]interface{}, len(tests))
+	for i, tt := range tests {
+		actions[i], decoded[i] = tt.action, tt.decoded
+	}
+	expectParsedActions(t, all, actions, decoded)
+}
+
+// expectParsedActions parses the finalized planner, bare and wrapped in modifyLiquidities, and checks the typed actions
+// as well as that their Values encode the same params again
+func expectParsedActions(t *testing.T, planner *V4Planner, actions []Actions, decoded []interface{}) {
+	t.Helper()
+	unlockData, err := planner.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := EncodeModifyLiquidities(unlockData, big.NewInt(1700000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, calldata := range [][]byte{unlockData, wrapped} {
+		call, err := ParseCalldata(calldata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(call.Actions) != len(actions) {
+			t.Fatalf("%d actions, want %d", len(call.Actions), len(actions))
+		}
+		for i, action := range call.Actions {
+			if action.ActionType != actions[i] || action.ActionName != actions[i].String() {
+				t.Errorf("action %d is %s (%s), want %s", i, action.ActionName, action.ActionType, actions[i])
+			}
+			if !reflect.DeepEqual(action.Decoded, decoded[i]) {
+				t.Errorf("%s decoded as %+v, want %+v", actions[i], action.Decoded, decoded[i])
+			}
+			reencoded, err := NewV4Planner().AddActions(action.ActionType, action.Values())
+			if err != nil {
+				t.Fatalf("%s: encoding Values: %v", actions[i], err)
+			}
+			if string(reencoded.Params[0]) != string(planner.Params[i]) {
+				t.Errorf("%s: Values encode to different params", actions[i])
+			}
+		}
+	}
+}
+
+func TestParseCalldataInvalid(t *testing.T) {
+	planner := NewV4Planner()
+	if _, err := planner.AddMintPosition(parserTestKey, -600, 600, big.NewInt(1), big.NewInt(2), big.NewInt(3), parserTestOwner, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.AddSettlePair(parserTestCurrency("0x0000000000000000000000000000000000000001"), parserTestCurrency("0x0000000000000000000000000000000000000002")); err != nil {
+		t.Fatal(err)
+	}
+	valid, err := planner.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := EncodeModifyLiquidities(valid, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every truncation of the unlock data and of the modifyLiquidities call fails without panicking
+	for _, calldata := range [][]byte{valid, wrapped} {
+		for n := 0; n < len(calldata); n++ {
+			if _, err := ParseCalldata(calldata[:n]); !errors.Is(err, ErrInvalidActionParams) {
+				t.Fatalf("calldata truncated to %d bytes returned %v", n, err)
+			}
+		}
+	}
+
+	encode := func(actions []byte, params [][]byte) []byte {
+		data, err := unlockDataArguments.Pack(actions, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	tests := []struct {
+		name     string
+		calldata []byte
+		err      error
+	}{
+		{"unknown action", encode([]byte{0xff}, [][]byte{{}}), ErrUnknownAction},
+		{"more actions than params", encode(append(planner.Actions, byte(SWEEP)), planner.Params), ErrInvalidActionParams},
+		{"params of another action", encode([]byte{byte(MINT_POSITION)}, planner.Params[1:]), ErrInvalidActionParams},
+		{"truncated params", encode([]byte{byte(MINT_POSITION)}, [][]byte{planner.Params[0][:200]}), ErrInvalidActionParams},
+	}
+	for _, tt := range tests {
+		if _, err := ParseCalldata(tt.calldata); !errors.Is(err, tt.err) {
+			t.Errorf("%s: returned %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// TestParseCalldataRejectsOutOfRangeTicks sets the tick words of a mint to values go-ethereum decodes without range checks
+func TestParseCalldataRejectsOutOfRangeTicks(t *testing.T) {
+	planner, err := NewV4Planner().AddMintPosition(parserTestKey, -600, 600, big.NewInt(1), big.NewInt(2), big.NewInt(3), parserTestOwner, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the pool key takes the first five words, the ticks are the sixth and seventh
+	const tickLowerWord, tickUpperWord, feeWord = 5, 6, 2
+	tests := []struct {
+		name  string
+		word  int
+		value *big.Int
+	}{
+		{"tickLower above int24", tickLowerWord, big.NewInt(1 << 23)},
+		{"tickUpper below int24", tickUpperWord, big.NewInt(-(1 << 23) - 1)},
+		{"tick above int64", tickUpperWord, new(big.Int).Lsh(big.NewInt(1), 70)},
+		{"fee above uint24", feeWord, big.NewInt(1 << 24)},
+	}
+	for _, tt := range tests {
+		params := append([]byte{}, planner.Params[0]...)
+		word := params[tt.word*32 : (tt.word+1)*32]
+		value := new(big.Int).Set(tt.value)
+		if value.Sign() < 0 {
+			value.Add(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		value.FillBytes(word)
+
+		calldata, err := unlockDataArguments.Pack(planner.Actions, [][]byte{params})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseCalldata(calldata); !errors.Is(err, ErrInvalidActionParams) {
+			t.Errorf("%s: returned %v", tt.name, err)
+		}
+	}
+
+	// the limits of int24 are accepted
+	planner, err = NewV4Planner().AddMintPosition(parserTestKey, -(1 << 23), 1<<23-1, big.NewInt(1), big.NewInt(2), big.NewInt(3), parserTestOwner, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockData, err := planner.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := ParseCalldata(unlockData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mint := call.Actions[0].Decoded.(MintPosition); mint.TickLower != -(1<<23) || mint.TickUpper != 1<<23-1 {
+		t.Errorf("ticks %d %d", mint.TickLower, mint.TickUpper)
+	}
+}
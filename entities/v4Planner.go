@@ -2,18 +2,19 @@ package entities
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
-	v3constants "github.com/KyberNetwork/pancake-v3-sdk/constants"
 	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	core "github.com/daoleno/uniswap-sdk-core/entities"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var ErrActionsParamsMismatch = errors.New("number of actions and params do not match")
+
 type Actions byte
 
-// Các hằng số mô phỏng enum Actionss với giá trị hex đã xác định.
+// Các hằng số mô phỏng enum Actionss với giá trị hex đã xác định, theo Actions.sol của v4-periphery.
 const (
 	// pool actions
 	// liquidity actions
@@ -23,8 +24,8 @@ const (
 	BURN_POSITION      Actions = 0x03
 
 	// for fee on transfer tokens
-	// INCREASE_LIQUIDITY_FROM_DELTAS = 0x04,
-	// MINT_POSITION_FROM_DELTAS = 0x05,
+	INCREASE_LIQUIDITY_FROM_DELTAS Actions = 0x04
+	MINT_POSITION_FROM_DELTAS      Actions = 0x05
 
 	// swapping
 	SWAP_EXACT_IN_SINGLE  Actions = 0x06
@@ -32,6 +33,10 @@ const (
 	SWAP_EXACT_OUT_SINGLE Actions = 0x08
 	SWAP_EXACT_OUT        Actions = 0x09
 
+	// donate
+	// note this is not supported in the position manager or router
+	DONATE Actions = 0x0a
+
 	// settling
 	SETTLE      Actions = 0x0b
 	SETTLE_ALL  Actions = 0x0c
@@ -44,14 +49,55 @@ const (
 	TAKE_PAIR    Actions = 0x11
 
 	CLOSE_CURRENCY Actions = 0x12
-	// CLEAR_OR_TAKE = 0x13,
-	SWEEP Actions = 0x14
+	CLEAR_OR_TAKE  Actions = 0x13
+	SWEEP          Actions = 0x14
 
 	// for wrapping/unwrapping native
-	// WRAP = 0x15,
+	WRAP   Actions = 0x15
 	UNWRAP Actions = 0x16
+
+	// minting/burning 6909s to close deltas
+	// note this is not supported in the position manager or router
+	MINT_6909 Actions = 0x17
+	BURN_6909 Actions = 0x18
 )
 
+var actionNames = map[Actions]string{
+	INCREASE_LIQUIDITY:             "INCREASE_LIQUIDITY",
+	DECREASE_LIQUIDITY:             "DECREASE_LIQUIDITY",
+	MINT_POSITION:                  "MINT_POSITION",
+	BURN_POSITION:                  "BURN_POSITION",
+	INCREASE_LIQUIDITY_FROM_DELTAS: "INCREASE_LIQUIDITY_FROM_DELTAS",
+	MINT_POSITION_FROM_DELTAS:      "MINT_POSITION_FROM_DELTAS",
+	SWAP_EXACT_IN_SINGLE:           "SWAP_EXACT_IN_SINGLE",
+	SWAP_EXACT_IN:                  "SWAP_EXACT_IN",
+	SWAP_EXACT_OUT_SINGLE:          "SWAP_EXACT_OUT_SINGLE",
+	SWAP_EXACT_OUT:                 "SWAP_EXACT_OUT",
+	DONATE:                         "DONATE",
+	SETTLE:                         "SETTLE",
+	SETTLE_ALL:                     "SETTLE_ALL",
+	SETTLE_PAIR:                    "SETTLE_PAIR",
+	TAKE:                           "TAKE",
+	TAKE_ALL:                       "TAKE_ALL",
+	TAKE_PORTION:                   "TAKE_PORTION",
+	TAKE_PAIR:                      "TAKE_PAIR",
+	CLOSE_CURRENCY:                 "CLOSE_CURRENCY",
+	CLEAR_OR_TAKE:                  "CLEAR_OR_TAKE",
+	SWEEP:                          "SWEEP",
+	WRAP:                           "WRAP",
+	UNWRAP:                         "UNWRAP",
+	MINT_6909:                      "MINT_6909",
+	BURN_6909:                      "BURN_6909",
+}
+
+// String returns the name of the action as in Actions.sol
+func (a Actions) String() string {
+	if name, ok := actionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN_ACTION_0x%02x", byte(a))
+}
+
 type Subparser int
 
 const (
@@ -69,7 +115,7 @@ type ParamType struct {
 }
 
 const POOL_KEY_STRUCT = "(address currency0,address currency1,uint24 fee,int24 tickSpacing,address hooks)"
-const PATH_KEY_STRUCT = "(address intermediateCurrency,uint256 fee,int24 tickSpacing,address hooks,bytes hookData)"
+const PATH_KEY_STRUCT = "(address intermediateCurrency,uint24 fee,int24 tickSpacing,address hooks,bytes hookData)"
 
 const SWAP_EXACT_IN_SINGLE_STRUCT = "(" + POOL_KEY_STRUCT + " poolKey,bool zeroForOne,uint128 amountIn,uint128 amountOutMinimum,bytes hookData)"
 
@@ -110,6 +156,21 @@ var V4_BASE_ACTIONS_ABI_DEFINITION = map[Actions][]ParamType{
 		{Name: "amount1Min", Type: "uint128"},
 		{Name: "hookData", Type: "bytes"},
 	},
+	INCREASE_LIQUIDITY_FROM_DELTAS: {
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "amount0Max", Type: "uint128"},
+		{Name: "amount1Max", Type: "uint128"},
+		{Name: "hookData", Type: "bytes"},
+	},
+	MINT_POSITION_FROM_DELTAS: {
+		{Name: "poolKey", Type: POOL_KEY_STRUCT, Subparser: Poolkey},
+		{Name: "tickLower", Type: "int24"},
+		{Name: "tickUpper", Type: "int24"},
+		{Name: "amount0Max", Type: "uint128"},
+		{Name: "amount1Max", Type: "uint128"},
+		{Name: "owner", Type: "address"},
+		{Name: "hookData", Type: "bytes"},
+	},
 
 	// swapping commands
 	SWAP_EXACT_IN_SINGLE: {
@@ -124,6 +185,12 @@ var V4_BASE_ACTIONS_ABI_DEFINITION = map[Actions][]ParamType{
 	SWAP_EXACT_OUT: {
 		{Name: "swap", Type: SWAP_EXACT_OUT_STRUCT, Subparser: V4SwapExactOut},
 	},
+	DONATE: {
+		{Name: "poolKey", Type: POOL_KEY_STRUCT, Subparser: Poolkey},
+		{Name: "amount0", Type: "uint256"},
+		{Name: "amount1", Type: "uint256"},
+		{Name: "hookData", Type: "bytes"},
+	},
 	SETTLE: {
 		{Name: "currency", Type: "address"},
 		{Name: "amount", Type: "uint256"},
@@ -159,13 +226,29 @@ var V4_BASE_ACTIONS_ABI_DEFINITION = map[Actions][]ParamType{
 	CLOSE_CURRENCY: {
 		{Name: "currency", Type: "address"},
 	},
+	CLEAR_OR_TAKE: {
+		{Name: "currency", Type: "address"},
+		{Name: "amountMax", Type: "uint256"},
+	},
 	SWEEP: {
 		{Name: "currency", Type: "address"},
 		{Name: "recipient", Type: "address"},
 	},
+	WRAP: {
+		{Name: "amount", Type: "uint256"},
+	},
 	UNWRAP: {
 		{Name: "amount", Type: "uint256"},
 	},
+	MINT_6909: {
+		{Name: "currency", Type: "address"},
+		{Name: "recipient", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	},
+	BURN_6909: {
+		{Name: "currency", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	},
 }
 
 const FULL_DELTA_AMOUNT = 0
@@ -177,11 +260,34 @@ type V4Planner struct {
 
 func NewV4Planner() *V4Planner {
 	return &V4Planner{
-		Actions: []byte(constants.EmptyBytes),
+		Actions: []byte{},
 		Params:  [][]byte{},
 	}
 }
 
+// unlockDataArguments is the abi layout of the data passed to unlock, abi.encode(bytes actions, bytes[] params)
+var unlockDataArguments = mustNewArguments("bytes actions", "bytes[] params")
+
+/**
+ * Returns the abi encoded (bytes actions, bytes[] params) of the planned actions, which is the unlockData
+ * expected by PositionManager.modifyLiquidities and the input of the Universal Router V4_SWAP command
+ */
+func (p *V4Planner) Finalize() ([]byte, error) {
+	if len(p.Actions) != len(p.Params) {
+		return nil, ErrActionsParamsMismatch
+	}
+	return unlockDataArguments.Pack(p.Actions, p.Params)
+}
+
+// V4SwapInput returns the Universal Router V4_SWAP command together with its input, the finalized unlockData
+func (p *V4Planner) V4SwapInput() (CommandType, []byte, error) {
+	input, err := p.Finalize()
+	if err != nil {
+		return 0, nil, err
+	}
+	return V4_SWAP, input, nil
+}
+
 func (p *V4Planner) AddActions(typ Actions, parameters []interface{}) (*V4Planner, error) {
 	command, err := createAction(typ, parameters)
 	if err != nil {
@@ -193,72 +299,205 @@ func (p *V4Planner) AddActions(typ Actions, parameters []interface{}) (*V4Planne
 	return p, nil
 }
 
-func (p *V4Planner) AddTrade(trade Trade, slippageTolerance *core.Percent) (*V4Planner, error) {
-	exactOutput := true
-	if trade.TradeType == core.ExactOutput {
-		exactOutput = false
-	}
-	if exactOutput {
-		if slippageTolerance == nil || slippageTolerance.LessThan(v3constants.PercentZero) {
-			return nil, ErrInvalidSlippageTolerance
-		}
+// HookDataProvider returns the hook data passed to the hook of a pool that a trade swaps through, nil for none
+type HookDataProvider func(pool *Pool) []byte
+
+// tradeSettlement is what the routes of one or more trades owe to and receive from the pool manager in total
+type tradeSettlement struct {
+	currencyIn, currencyOut   core.Currency
+	maxAmountIn, minAmountOut *big.Int
+	exactOutput               bool
+}
+
+func newTradeSettlement() *tradeSettlement {
+	return &tradeSettlement{maxAmountIn: big.NewInt(0), minAmountOut: big.NewInt(0)}
+}
+
+// addRoute adds the bounds of a route, whose path currencies must be those of the routes added before
+func (s *tradeSettlement) addRoute(route *Route, maxAmountIn, minAmountOut *big.Int) error {
+	if s.currencyIn == nil {
+		s.currencyIn, s.currencyOut = route.PathInput, route.PathOutput
+	} else if !route.PathInput.Equal(s.currencyIn) || !route.PathOutput.Equal(s.currencyOut) {
+		return ErrTradesCurrencyMismatch
 	}
-	if len(trade.Swaps) != 1 {
-		return nil, errors.New("only accepts Trades with 1 swap (must break swaps into individual trades)")
+	s.maxAmountIn.Add(s.maxAmountIn, maxAmountIn)
+	s.minAmountOut.Add(s.minAmountOut, minAmountOut)
+	return nil
+}
+
+/**
+ * Adds a swap action for every route of the trade, then settles the input and takes the output of all routes at once
+ * with SETTLE_ALL bounded by the sum of the maximum inputs and TAKE_ALL bounded by the sum of the minimum outputs.
+ * One hop routes use SWAP_EXACT_IN_SINGLE or SWAP_EXACT_OUT_SINGLE, which cost less gas than the path variants.
+ * @param trade The trade, its routes must share their path input and output currency
+ * @param slippageTolerance Bounds the output of exact input routes and the input of exact output routes, each route
+ * separately. Optional for exact input trades, whose output is then not bounded
+ */
+func (p *V4Planner) AddTrade(trade Trade, slippageTolerance *core.Percent) (*V4Planner, error) {
+	return p.AddTradeWithHookData(trade, slippageTolerance, nil)
+}
+
+// AddTradeWithHookData is AddTrade passing the hook data returned by hookData to the hook of each pool
+func (p *V4Planner) AddTradeWithHookData(trade Trade, slippageTolerance *core.Percent, hookData HookDataProvider) (*V4Planner, error) {
+	settlement := newTradeSettlement()
+	if err := p.addTradeSwaps(&trade, slippageTolerance, hookData, settlement); err != nil {
+		return nil, err
 	}
-	actionType := SWAP_EXACT_IN
-	if exactOutput {
-		actionType = SWAP_EXACT_OUT
+	if _, err := p.AddSettleAll(&settlement.currencyIn, settlement.maxAmountIn); err != nil {
+		return nil, err
 	}
-
-	r, err := trade.Route()
-	if err != nil {
+	if _, err := p.AddTakeAll(&settlement.currencyOut, settlement.minAmountOut); err != nil {
 		return nil, err
 	}
+	return p, nil
+}
 
-	currencyIn := currencyAddress(r.PathInput)
-	currencyOut := currencyAddress(r.PathOutput)
+// addTradeSwaps adds the swap actions of the routes of the trade and their bounds to the settlement
+func (p *V4Planner) addTradeSwaps(trade *Trade, slippageTolerance *core.Percent, hookData HookDataProvider, settlement *tradeSettlement) error {
+	exactOutput := trade.TradeType == core.ExactOutput
+	if exactOutput && slippageTolerance == nil {
+		return ErrInvalidSlippageTolerance
+	}
+	if slippageTolerance != nil && slippageTolerance.LessThan(constants.PercentZero) {
+		return ErrInvalidSlippageTolerance
+	}
+	if len(trade.Swaps) == 0 {
+		return ErrNoTrades
+	}
+	if hookData == nil {
+		hookData = func(*Pool) []byte { return nil }
+	}
 
-	if !exactOutput {
-		encoded, err := EncodeRouteToPath(r, exactOutput)
-		if err != nil {
-			return nil, err
-		}
-		amountInMax, err := trade.MaximumAmountIn(slippageTolerance, nil)
-		if err != nil {
-			return nil, err
+	for _, swap := range trade.Swaps {
+		var err error
+		if exactOutput {
+			err = p.addExactOutputSwap(trade, swap, slippageTolerance, hookData, settlement)
+		} else {
+			err = p.addExactInputSwap(trade, swap, slippageTolerance, hookData, settlement)
 		}
-		act, err := p.AddActions(actionType, []interface{}{
-			currencyOut,
-			encoded,
-			amountInMax,
-			trade.outputAmount.Quotient().String(),
-		})
 		if err != nil {
-			return nil, err
+			return err
 		}
-		return act, nil
-	} else {
-		encoded, err := EncodeRouteToPath(r, exactOutput)
-		if err != nil {
-			return nil, err
-		}
-		amountOutMin, err := trade.MininumAmountOut(slippageTolerance, nil)
+	}
+	settlement.exactOutput = settlement.exactOutput || exactOutput
+	return nil
+}
+
+func (p *V4Planner) addExactInputSwap(trade *Trade, swap *Swap, slippageTolerance *core.Percent, hookData HookDataProvider, settlement *tradeSettlement) error {
+	amountIn := swap.InputAmount.Quotient()
+	amountOutMin := big.NewInt(0)
+	if slippageTolerance != nil {
+		minimumAmountOut, err := trade.MininumAmountOut(slippageTolerance, swap.OutputAmount)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		act, err := p.AddActions(actionType, []interface{}{
-			currencyIn,
-			encoded,
-			trade.inputAmount.Quotient().String(),
-			amountOutMin,
-		})
-		if err != nil {
-			return nil, err
+		amountOutMin = minimumAmountOut.Quotient()
+	}
+	route := swap.Route
+	if err := settlement.addRoute(route, amountIn, amountOutMin); err != nil {
+		return err
+	}
+
+	if len(route.Pools) == 1 {
+		pool := route.Pools[0]
+		zeroForOne := currencyAddress(route.PathInput) == pool.PoolKey.Currency0
+		_, err := p.AddSwapExactInSingle(pool.PoolKey, zeroForOne, amountIn, amountOutMin, hookData(pool))
+		return err
+	}
+	path, err := encodeRouteToPathWithHookData(route, false, hookData)
+	if err != nil {
+		return err
+	}
+	_, err = p.AddSwapExactIn(&route.PathInput, path, amountIn, amountOutMin)
+	return err
+}
+
+func (p *V4Planner) addExactOutputSwap(trade *Trade, swap *Swap, slippageTolerance *core.Percent, hookData HookDataProvider, settlement *tradeSettlement) error {
+	amountOut := swap.OutputAmount.Quotient()
+	maximumAmountIn, err := trade.MaximumAmountIn(slippageTolerance, swap.InputAmount)
+	if err != nil {
+		return err
+	}
+	amountInMax := maximumAmountIn.Quotient()
+	route := swap.Route
+	if err := settlement.addRoute(route, amountInMax, amountOut); err != nil {
+		return err
+	}
+
+	if len(route.Pools) == 1 {
+		pool := route.Pools[0]
+		zeroForOne := currencyAddress(route.PathInput) == pool.PoolKey.Currency0
+		_, err := p.AddSwapExactOutSingle(pool.PoolKey, zeroForOne, amountOut, amountInMax, hookData(pool))
+		return err
+	}
+	path, err := encodeRouteToPathWithHookData(route, true, hookData)
+	if err != nil {
+		return err
+	}
+	_, err = p.AddSwapExactOut(&route.PathOutput, path, amountOut, amountInMax)
+	return err
+}
+
+// encodeRouteToPathWithHookData is EncodeRouteToPath with the hook data of each pool, whose path keys are in the
+// order of the pools for both directions
+func encodeRouteToPathWithHookData(route *Route, exactOutput bool, hookData HookDataProvider) ([]PathKey, error) {
+	path, err := EncodeRouteToPath(route, exactOutput)
+	if err != nil {
+		return nil, err
+	}
+	for i, pool := range route.Pools {
+		if data := hookData(pool); data != nil {
+			path[i].HookData = data
 		}
-		return act, nil
 	}
+	return path, nil
+}
+
+func (p *V4Planner) AddIncreaseLiquidity(tokenId, liquidity, amount0Max, amount1Max *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(INCREASE_LIQUIDITY, []interface{}{tokenId, liquidity, amount0Max, amount1Max, hookData})
+}
+
+func (p *V4Planner) AddDecreaseLiquidity(tokenId, liquidity, amount0Min, amount1Min *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(DECREASE_LIQUIDITY, []interface{}{tokenId, liquidity, amount0Min, amount1Min, hookData})
+}
+
+func (p *V4Planner) AddMintPosition(poolKey PoolKey, tickLower, tickUpper int, liquidity, amount0Max, amount1Max *big.Int, owner common.Address, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(MINT_POSITION, []interface{}{poolKey, tickLower, tickUpper, liquidity, amount0Max, amount1Max, owner, hookData})
+}
+
+func (p *V4Planner) AddBurnPosition(tokenId, amount0Min, amount1Min *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(BURN_POSITION, []interface{}{tokenId, amount0Min, amount1Min, hookData})
+}
+
+// AddIncreaseLiquidityFromDeltas increases liquidity using the open deltas, e.g. what a fee on transfer token settled
+func (p *V4Planner) AddIncreaseLiquidityFromDeltas(tokenId, amount0Max, amount1Max *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(INCREASE_LIQUIDITY_FROM_DELTAS, []interface{}{tokenId, amount0Max, amount1Max, hookData})
+}
+
+// AddMintPositionFromDeltas mints a position with the liquidity the open deltas can pay for
+func (p *V4Planner) AddMintPositionFromDeltas(poolKey PoolKey, tickLower, tickUpper int, amount0Max, amount1Max *big.Int, owner common.Address, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(MINT_POSITION_FROM_DELTAS, []interface{}{poolKey, tickLower, tickUpper, amount0Max, amount1Max, owner, hookData})
+}
+
+func (p *V4Planner) AddSwapExactInSingle(poolKey PoolKey, zeroForOne bool, amountIn, amountOutMinimum *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(SWAP_EXACT_IN_SINGLE, []interface{}{[]interface{}{poolKey, zeroForOne, amountIn, amountOutMinimum, hookData}})
+}
 
+func (p *V4Planner) AddSwapExactIn(currencyIn *core.Currency, path []PathKey, amountIn, amountOutMinimum *big.Int) (*V4Planner, error) {
+	return p.AddActions(SWAP_EXACT_IN, []interface{}{[]interface{}{currencyAddress(*currencyIn), path, amountIn, amountOutMinimum}})
+}
+
+func (p *V4Planner) AddSwapExactOutSingle(poolKey PoolKey, zeroForOne bool, amountOut, amountInMaximum *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(SWAP_EXACT_OUT_SINGLE, []interface{}{[]interface{}{poolKey, zeroForOne, amountOut, amountInMaximum, hookData}})
+}
+
+func (p *V4Planner) AddSwapExactOut(currencyOut *core.Currency, path []PathKey, amountOut, amountInMaximum *big.Int) (*V4Planner, error) {
+	return p.AddActions(SWAP_EXACT_OUT, []interface{}{[]interface{}{currencyAddress(*currencyOut), path, amountOut, amountInMaximum}})
+}
+
+// AddDonate encodes a donation to the pool, note that neither the position manager nor the router handle it
+func (p *V4Planner) AddDonate(poolKey PoolKey, amount0, amount1 *big.Int, hookData []byte) (*V4Planner, error) {
+	return p.AddActions(DONATE, []interface{}{poolKey, amount0, amount1, hookData})
 }
 
 func (p *V4Planner) AddSettle(currency *core.Currency, payerIsUser bool, amount *big.Int) (*V4Planner, error) {
@@ -269,6 +508,14 @@ func (p *V4Planner) AddSettle(currency *core.Currency, payerIsUser bool, amount
 	return act, err
 }
 
+func (p *V4Planner) AddSettleAll(currency *core.Currency, maxAmount *big.Int) (*V4Planner, error) {
+	return p.AddActions(SETTLE_ALL, []interface{}{currencyAddress(*currency), maxAmount})
+}
+
+func (p *V4Planner) AddSettlePair(currency0, currency1 *core.Currency) (*V4Planner, error) {
+	return p.AddActions(SETTLE_PAIR, []interface{}{currencyAddress(*currency0), currencyAddress(*currency1)})
+}
+
 func (p *V4Planner) AddTake(currency *core.Currency, recipient common.Address, amount *big.Int) (*V4Planner, error) {
 	takeAmount := amount
 	if amount == nil {
@@ -277,10 +524,50 @@ func (p *V4Planner) AddTake(currency *core.Currency, recipient common.Address, a
 	return p.AddActions(TAKE, []interface{}{currencyAddress(*currency), recipient, takeAmount})
 }
 
+func (p *V4Planner) AddTakeAll(currency *core.Currency, minAmount *big.Int) (*V4Planner, error) {
+	return p.AddActions(TAKE_ALL, []interface{}{currencyAddress(*currency), minAmount})
+}
+
+// AddTakePortion takes bips (in basis points) of the open delta of currency
+func (p *V4Planner) AddTakePortion(currency *core.Currency, recipient common.Address, bips *big.Int) (*V4Planner, error) {
+	return p.AddActions(TAKE_PORTION, []interface{}{currencyAddress(*currency), recipient, bips})
+}
+
+func (p *V4Planner) AddTakePair(currency0, currency1 *core.Currency, recipient common.Address) (*V4Planner, error) {
+	return p.AddActions(TAKE_PAIR, []interface{}{currencyAddress(*currency0), currencyAddress(*currency1), recipient})
+}
+
+func (p *V4Planner) AddCloseCurrency(currency *core.Currency) (*V4Planner, error) {
+	return p.AddActions(CLOSE_CURRENCY, []interface{}{currencyAddress(*currency)})
+}
+
+// AddClearOrTake forfeits the open delta of currency if it is at most amountMax and takes it otherwise
+func (p *V4Planner) AddClearOrTake(currency *core.Currency, amountMax *big.Int) (*V4Planner, error) {
+	return p.AddActions(CLEAR_OR_TAKE, []interface{}{currencyAddress(*currency), amountMax})
+}
+
+func (p *V4Planner) AddSweep(currency *core.Currency, recipient common.Address) (*V4Planner, error) {
+	return p.AddActions(SWEEP, []interface{}{currencyAddress(*currency), recipient})
+}
+
+func (p *V4Planner) AddWrap(amount *big.Int) (*V4Planner, error) {
+	return p.AddActions(WRAP, []interface{}{amount})
+}
+
 func (p *V4Planner) AddUnwrap(amount *big.Int) (*V4Planner, error) {
 	return p.AddActions(UNWRAP, []interface{}{amount})
 }
 
+// AddMint6909 mints ERC-6909 claims of currency to the recipient, note that neither the position manager nor the router handle it
+func (p *V4Planner) AddMint6909(currency *core.Currency, recipient common.Address, amount *big.Int) (*V4Planner, error) {
+	return p.AddActions(MINT_6909, []interface{}{currencyAddress(*currency), recipient, amount})
+}
+
+// AddBurn6909 burns ERC-6909 claims of currency, note that neither the position manager nor the router handle it
+func (p *V4Planner) AddBurn6909(currency *core.Currency, amount *big.Int) (*V4Planner, error) {
+	return p.AddActions(BURN_6909, []interface{}{currencyAddress(*currency), amount})
+}
+
 func currencyAddress(curr core.Currency) common.Address {
 	if curr.IsNative() {
 		return constants.AddressZero
@@ -293,29 +580,23 @@ type RouterAction struct {
 	encodedInput []byte
 }
 
+/**
+ * Encodes the params of an action according to V4_BASE_ACTIONS_ABI_DEFINITION
+ * @param action The action to encode
+ * @param parameters The params in definition order, see toAbiValue for the accepted go types
+ */
 func createAction(action Actions, parameters []interface{}) (*RouterAction, error) {
-	paramTypes := V4_BASE_ACTIONS_ABI_DEFINITION[action]
-	var typeStrings []string
-	for _, v := range paramTypes {
-		typeStrings = append(typeStrings, v.Type)
+	arguments, err := actionArguments(action)
+	if err != nil {
+		return nil, err
 	}
-
-	// Create string for abi.NewType
-	types := make([]abi.ArgumentMarshaling, len(typeStrings))
-	for i, t := range typeStrings {
-		types[i] = abi.ArgumentMarshaling{Type: t}
+	if len(parameters) != len(arguments) {
+		return nil, fmt.Errorf("%w: %s expects %d params, got %d", ErrInvalidParamsLength, action, len(arguments), len(parameters))
 	}
-	arguments := abi.Arguments{}
-	for _, t := range types {
-		typ, err := abi.NewType(t.Type, "", nil)
-		if err != nil {
-			return nil, err
-		}
-		arguments = append(arguments, abi.Argument{Type: typ})
-	}
-	encodeInput, err := arguments.Pack(parameters...)
+
+	encodeInput, err := encodeArguments(arguments, parameters...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s %w", action, err)
 	}
 
 	return &RouterAction{
@@ -0,0 +1,167 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var plannerTestToken2 = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000003"), 18, "T2", "token2")
+
+// plannerTestRoutes returns a one hop token0 -> token1 route and a two hop token0 -> token2 -> token1 route
+func plannerTestRoutes(t *testing.T) (direct, twoHop *Route) {
+	t.Helper()
+	direct, err := NewRoute([]*Pool{testPoolWithCurrencies(t, testToken0, testToken1, 3000, common.Address{})}, testToken0, testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	twoHop, err = NewRoute([]*Pool{
+		testPoolWithCurrencies(t, testToken0, plannerTestToken2, 3000, common.Address{}),
+		testPoolWithCurrencies(t, testToken1, plannerTestToken2, 3000, common.Address{}),
+	}, testToken0, testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return direct, twoHop
+}
+
+// plannerTestTrade splits a trade over both routes, 600000 in for 590000 out and 400000 in for 390000 out
+func plannerTestTrade(t *testing.T, tradeType core.TradeType) *Trade {
+	t.Helper()
+	direct, twoHop := plannerTestRoutes(t)
+	trade, err := CreateUncheckedTradeWithMultipleRoutes([]*Swap{
+		{Route: direct, InputAmount: core.FromRawAmount(testToken0, big.NewInt(600000)), OutputAmount: core.FromRawAmount(testToken1, big.NewInt(590000))},
+		{Route: twoHop, InputAmount: core.FromRawAmount(testToken0, big.NewInt(400000)), OutputAmount: core.FromRawAmount(testToken1, big.NewInt(390000))},
+	}, tradeType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trade
+}
+
+func parsePlannerActions(t *testing.T, planner *V4Planner) []interface{} {
+	t.Helper()
+	unlockData, err := planner.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := ParseCalldata(unlockData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := make([]interface{}, len(call.Actions))
+	for i, action := range call.Actions {
+		actions[i] = action.Decoded
+	}
+	return actions
+}
+
+func TestAddTradeMultipleRoutesExactInput(t *testing.T) {
+	trade := plannerTestTrade(t, core.ExactInput)
+	planner, err := NewV4Planner().AddTrade(*trade, swapTestSlippage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := trade.Swaps[0].Route
+	// each route is bounded by its own output less 1%: 590000 / 1.01 and 390000 / 1.01 rounded down, whose sum is
+	// one less than the bound of the whole trade
+	expectSwapActions(t, parsePlannerActions(t, planner),
+		SwapExactInSingle{PoolKey: direct.Pools[0].PoolKey, ZeroForOne: true, AmountIn: big.NewInt(600000), AmountOutMinimum: big.NewInt(584158), HookData: []byte{}},
+		SwapExactIn{CurrencyIn: testToken0.Address, Path: []PathKey{
+			{IntermediateCurrency: plannerTestToken2.Address, Fee: 3000, TickSpacing: 60, HookData: []byte{}},
+			{IntermediateCurrency: testToken1.Address, Fee: 3000, TickSpacing: 60, HookData: []byte{}},
+		}, AmountIn: big.NewInt(400000), AmountOutMinimum: big.NewInt(386138)},
+		SettleAll{Currency: testToken0.Address, MaxAmount: big.NewInt(1000000)},
+		TakeAll{Currency: testToken1.Address, MinAmount: big.NewInt(970296)},
+	)
+
+	// without slippage tolerance the output is not bounded
+	planner, err = NewV4Planner().AddTrade(*trade, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if take := parsePlannerActions(t, planner)[3].(TakeAll); take.MinAmount.Sign() != 0 {
+		t.Errorf("minimum output %s without slippage tolerance", take.MinAmount)
+	}
+}
+
+func TestAddTradeMultipleRoutesExactOutput(t *testing.T) {
+	trade := plannerTestTrade(t, core.ExactOutput)
+	planner, err := NewV4Planner().AddTrade(*trade, swapTestSlippage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := trade.Swaps[0].Route
+	// each route is bounded by its own input plus 1%
+	expectSwapActions(t, parsePlannerActions(t, planner),
+		SwapExactOutSingle{PoolKey: direct.Pools[0].PoolKey, ZeroForOne: true, AmountOut: big.NewInt(590000), AmountInMaximum: big.NewInt(606000), HookData: []byte{}},
+		SwapExactOut{CurrencyOut: testToken1.Address, Path: []PathKey{
+			{IntermediateCurrency: testToken0.Address, Fee: 3000, TickSpacing: 60, HookData: []byte{}},
+			{IntermediateCurrency: plannerTestToken2.Address, Fee: 3000, TickSpacing: 60, HookData: []byte{}},
+		}, AmountOut: big.NewInt(390000), AmountInMaximum: big.NewInt(404000)},
+		SettleAll{Currency: testToken0.Address, MaxAmount: big.NewInt(1010000)},
+		TakeAll{Currency: testToken1.Address, MinAmount: big.NewInt(980000)},
+	)
+
+	if _, err := NewV4Planner().AddTrade(*trade, nil); !errors.Is(err, ErrInvalidSlippageTolerance) {
+		t.Errorf("exact output without slippage tolerance returned %v", err)
+	}
+}
+
+func TestAddTradeWithHookData(t *testing.T) {
+	trade := plannerTestTrade(t, core.ExactOutput)
+	direct, twoHop := trade.Swaps[0].Route, trade.Swaps[1].Route
+	hookData := func(pool *Pool) []byte {
+		switch pool {
+		case direct.Pools[0]:
+			return []byte{0xaa}
+		case twoHop.Pools[1]:
+			return []byte{0xb1}
+		}
+		return nil
+	}
+	planner, err := NewV4Planner().AddTradeWithHookData(*trade, swapTestSlippage, hookData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := parsePlannerActions(t, planner)
+	if single := actions[0].(SwapExactOutSingle); string(single.HookData) != "\xaa" {
+		t.Errorf("single hop hook data %x", single.HookData)
+	}
+	// the path keys of exact output swaps are in the order of the pools as well
+	path := actions[1].(SwapExactOut).Path
+	if len(path[0].HookData) != 0 || string(path[1].HookData) != "\xb1" {
+		t.Errorf("path hook data %x %x", path[0].HookData, path[1].HookData)
+	}
+}
+
+func TestAddTradeRoutesCurrencyMismatch(t *testing.T) {
+	// both routes swap ETH, one through a native pool and the other through a WETH pool
+	nativeRoute, err := NewRoute([]*Pool{testPoolWithCurrencies(t, pmTestNative, testToken1, 3000, common.Address{})}, pmTestNative, testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wethRoute, err := NewRoute([]*Pool{testPoolWithCurrencies(t, testToken1, pmTestNative.Wrapped(), 3000, common.Address{})}, pmTestNative, testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount := func(currency core.Currency) *core.CurrencyAmount {
+		return core.FromRawAmount(currency, big.NewInt(1000))
+	}
+	trade, err := CreateUncheckedTradeWithMultipleRoutes([]*Swap{
+		{Route: nativeRoute, InputAmount: amount(pmTestNative), OutputAmount: amount(testToken1)},
+		{Route: wethRoute, InputAmount: amount(pmTestNative), OutputAmount: amount(testToken1)},
+	}, core.ExactInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewV4Planner().AddTrade(*trade, swapTestSlippage); !errors.Is(err, ErrTradesCurrencyMismatch) {
+		t.Errorf("returned %v", err)
+	}
+	if _, err := NewV4Planner().AddTrade(Trade{TradeType: core.ExactInput}, swapTestSlippage); !errors.Is(err, ErrNoTrades) {
+		t.Errorf("trade without swaps returned %v", err)
+	}
+}
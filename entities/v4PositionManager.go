@@ -1,96 +1,379 @@
 package entities
 
 import (
+	"errors"
 	"math/big"
 
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
 	"github.com/ethereum/go-ethereum/common"
 
 	core "github.com/daoleno/uniswap-sdk-core/entities"
 )
 
-type V4PositionPlanner struct {
-	V4Planner
+var (
+	ErrNilDeadline       = errors.New("deadline is required")
+	ErrZeroLiquidity     = errors.New("zero liquidity")
+	ErrNativeNotSet      = errors.New("UseNative must be set if and only if currency0 is native")
+	ErrCannotBurn        = errors.New("cannot burn the position unless 100% of its liquidity is removed")
+	ErrNoSqrtPrice       = errors.New("sqrtPriceX96 is required to create the pool")
+	ErrInvalidPercentage = errors.New("liquidity percentage must be between 0 and 100%")
+)
+
+var (
+	modifyLiquiditiesMethod = positionManagerMethod("modifyLiquidities(bytes,uint256)")
+	initializePoolMethod    = positionManagerMethod("initializePool((address,address,uint24,int24,address),uint160)")
+)
+
+// Options shared by all the liquidity operations
+type CommonOptions struct {
+	// How much the pool price is allowed to move
+	SlippageTolerance *core.Percent
+	// Optional data to pass to hooks
+	HookData []byte
+	// When the transaction expires, in epoch seconds
+	Deadline *big.Int
 }
 
-func (p *V4PositionPlanner) AddMint(pool Pool, tickLower, tickUpper int, liquidity *big.Int, amount0Max, amount1Max *big.Int, owner common.Address, hookData byte) error {
-	poolKey, err := GetPoolKey(pool.Currency0, pool.Currency1, pool.Fee, pool.TickSpacing, pool.Hooks)
-	if err != nil {
-		return err
-	}
-	inputs := []interface{}{
-		poolKey,
-		tickLower,
-		tickUpper,
-		liquidity.String(),
-		amount0Max.String(),
-		amount1Max.String(),
-		owner,
-		hookData,
-	}
-	_, err = p.AddActions(MINT_POSITION, inputs)
-	return err
+type MintSpecificOptions struct {
+	// The account that should receive the minted NFT
+	Recipient common.Address
+	// Creates pool if not initialized before mint
+	CreatePool bool
+	// Initial price to set on the pool if creating
+	SqrtPriceX96 *big.Int
+	// Whether the mint is part of a migration from V3 to V4, the tokens are then already held by the position manager
+	Migrate bool
+}
+
+type AddLiquidityOptions struct {
+	CommonOptions
+	MintSpecificOptions
+	// The id of the position to increase, nil to mint a new position
+	TokenId *big.Int
+	// Whether to spend ether, it must be set if and only if currency0 of the pool is native
+	UseNative bool
+	// Optional Permit2 allowances of the position manager, submitted before the liquidity is added
+	BatchPermit *BatchPermitOptions
+}
+
+type RemoveLiquidityOptions struct {
+	CommonOptions
+	// The id of the position to remove liquidity from
+	TokenId *big.Int
+	// The percentage of position liquidity to exit
+	LiquidityPercentage *core.Percent
+	// Whether the NFT should be burned if the entire position is being exited, by default false
+	BurnToken bool
+	// Optional permit of the owner approving the sender for the position, submitted before the liquidity is removed
+	Permit *NFTPermitOptions
+}
+
+type CollectOptions struct {
+	// The id of the position to collect fees of
+	TokenId *big.Int
+	// The currencies of the pool of the position
+	Currency0 core.Currency
+	Currency1 core.Currency
+	// The account that should receive the fees
+	Recipient common.Address
+	// Optional data to pass to hooks
+	HookData []byte
+	// When the transaction expires, in epoch seconds
+	Deadline *big.Int
 }
 
-func (p *V4PositionPlanner) AddDecrease(tokenId, liquidity, amount0Min, amount1Min *big.Int, hookData byte) error {
-	inputs := []interface{}{
-		tokenId.String(),
-		amount0Min.String(),
-		amount1Min.String(),
-		hookData,
+/**
+ * Produces the calldata for initializing a pool
+ * @param poolKey The key of the pool to initialize
+ * @param sqrtPriceX96 The initial price of the pool
+ */
+func CreateCallParameters(poolKey PoolKey, sqrtPriceX96 *big.Int) (*utils.MethodParameters, error) {
+	calldata, err := EncodeInitializePool(poolKey, sqrtPriceX96)
+	if err != nil {
+		return nil, err
 	}
-	_, err := p.AddActions(TAKE_PAIR, inputs)
-	return err
+	return &utils.MethodParameters{Calldata: calldata, Value: big.NewInt(0)}, nil
 }
 
-func (p *V4PositionPlanner) AddBurn(tokenId, amount0Min, amount1Min *big.Int, hookData byte) error {
-	inputs := []interface{}{
-		tokenId.String(),
-		amount0Min.String(),
-		amount1Min.String(),
-		hookData,
+/**
+ * Produces the calldata for minting a position or increasing the liquidity of an existing one
+ * @param position The position to mint or the liquidity to add to the position
+ * @param options Options for the transaction, a nil TokenId mints a new position
+ */
+func AddCallParameters(position *Position, options *AddLiquidityOptions) (*utils.MethodParameters, error) {
+	if position.Liquidity == nil || position.Liquidity.Sign() <= 0 {
+		return nil, ErrZeroLiquidity
 	}
-	_, err := p.AddActions(BURN_POSITION, inputs)
-	return err
+	if options.SlippageTolerance == nil {
+		return nil, ErrInvalidSlippageTolerance
+	}
+	pool := position.Pool
+	if pool.Currency0.IsNative() != options.UseNative {
+		return nil, ErrNativeNotSet
+	}
+
+	var calldataList [][]byte
+	planner := NewV4PositionPlanner()
+	isMint := options.TokenId == nil
+
+	// initializePool is not an action, so it is its own call in the multicall
+	if isMint && options.CreatePool {
+		if options.SqrtPriceX96 == nil {
+			return nil, ErrNoSqrtPrice
+		}
+		calldata, err := EncodeInitializePool(pool.PoolKey, options.SqrtPriceX96)
+		if err != nil {
+			return nil, err
+		}
+		calldataList = append(calldataList, calldata)
+	}
+
+	// permits if necessary
+	if options.BatchPermit != nil {
+		calldata, err := EncodePermit2Batch(options.BatchPermit.Owner, options.BatchPermit.PermitBatch, options.BatchPermit.Signature)
+		if err != nil {
+			return nil, err
+		}
+		calldataList = append(calldataList, calldata)
+	}
+
+	// adjust for slippage
+	maximumAmounts, err := position.MintAmountsWithSlippage(options.SlippageTolerance)
+	if err != nil {
+		return nil, err
+	}
+	amount0Max, amount1Max := maximumAmounts.Amount0, maximumAmounts.Amount1
+
+	if isMint {
+		err = planner.AddMint(pool, position.TickLower, position.TickUpper, position.Liquidity, amount0Max, amount1Max, options.Recipient, options.HookData)
+	} else {
+		err = planner.AddIncrease(options.TokenId, position.Liquidity, amount0Max, amount1Max, options.HookData)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value := big.NewInt(0)
+	if isMint && options.Migrate {
+		// the position manager holds the tokens, so it is the payer and sweeps what is left to the recipient
+		if options.UseNative {
+			// unwrap the exact amount needed to send to the pool manager
+			if _, err := planner.AddUnwrap(big.NewInt(FULL_DELTA_AMOUNT)); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := planner.V4Planner.AddSettle(&pool.Currency0, false, nil); err != nil {
+			return nil, err
+		}
+		if _, err := planner.V4Planner.AddSettle(&pool.Currency1, false, nil); err != nil {
+			return nil, err
+		}
+		sweepCurrency0 := pool.Currency0
+		if options.UseNative {
+			// sweep any leftover wrapped native that was not unwrapped
+			sweepCurrency0 = pool.Currency0.Wrapped()
+		}
+		if err := planner.AddSweep(&sweepCurrency0, options.Recipient); err != nil {
+			return nil, err
+		}
+		if err := planner.AddSweep(&pool.Currency1, options.Recipient); err != nil {
+			return nil, err
+		}
+	} else {
+		// the user pays both currencies
+		if err := planner.AddSettlePair(&pool.Currency0, &pool.Currency1); err != nil {
+			return nil, err
+		}
+		if options.UseNative {
+			// native is always currency0, any ether left after settling is swept back to the sender
+			value = amount0Max
+			if err := planner.AddSweep(&pool.Currency0, constants.MsgSender); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	calldata, err := planner.ModifyLiquiditiesCalldata(options.Deadline)
+	if err != nil {
+		return nil, err
+	}
+	calldataList = append(calldataList, calldata)
+
+	calldata, err = utils.EncodeMulticall(calldataList)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{Calldata: calldata, Value: value}, nil
 }
 
-func (p *V4PositionPlanner) AddSettlePair(currency0, currency1 *core.Currency) error {
-	var err error
-	curr0, err := utils.ToAddress(*currency0)
+/**
+ * Produces the calldata for completely or partially exiting a position
+ * @param position The position to exit
+ * @param options Additional information necessary for generating the calldata
+ */
+func RemoveCallParameters(position *Position, options *RemoveLiquidityOptions) (*utils.MethodParameters, error) {
+	if options.SlippageTolerance == nil {
+		return nil, ErrInvalidSlippageTolerance
+	}
+	if options.LiquidityPercentage == nil ||
+		options.LiquidityPercentage.LessThan(constants.PercentZero) ||
+		options.LiquidityPercentage.GreaterThan(fractionOne) {
+		return nil, ErrInvalidPercentage
+	}
+	pool := position.Pool
+	planner := NewV4PositionPlanner()
+
+	var calldataList [][]byte
+	// the sender must be approved to modify the position of its owner
+	if options.Permit != nil {
+		calldata, err := EncodeNFTPermit(options.Permit)
+		if err != nil {
+			return nil, err
+		}
+		calldataList = append(calldataList, calldata)
+	}
+
+	if options.BurnToken {
+		if !options.LiquidityPercentage.EqualTo(fractionOne) {
+			return nil, ErrCannotBurn
+		}
+		// slippage adjusted amounts derived from the current position liquidity
+		minimumAmounts, err := position.BurnAmountsWithSlippage(options.SlippageTolerance)
+		if err != nil {
+			return nil, err
+		}
+		if err := planner.AddBurn(options.TokenId, minimumAmounts.Amount0, minimumAmounts.Amount1, options.HookData); err != nil {
+			return nil, err
+		}
+	} else {
+		// a partial position with the percentage of liquidity to remove
+		liquidity := core.NewFraction(position.Liquidity, big.NewInt(1)).Multiply(options.LiquidityPercentage.Fraction).Quotient()
+		// zero liquidity is a collect, see CollectCallParameters
+		if liquidity.Sign() <= 0 {
+			return nil, ErrZeroLiquidity
+		}
+		partialPosition, err := NewPosition(pool, liquidity, position.TickLower, position.TickUpper)
+		if err != nil {
+			return nil, err
+		}
+		minimumAmounts, err := partialPosition.BurnAmountsWithSlippage(options.SlippageTolerance)
+		if err != nil {
+			return nil, err
+		}
+		if err := planner.AddDecrease(options.TokenId, liquidity, minimumAmounts.Amount0, minimumAmounts.Amount1, options.HookData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := planner.AddTakePair(&pool.Currency0, &pool.Currency1, constants.MsgSender); err != nil {
+		return nil, err
+	}
+	calldata, err := planner.ModifyLiquiditiesCalldata(options.Deadline)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	curr1, err := utils.ToAddress(*currency1)
+	calldata, err = utils.EncodeMulticall(append(calldataList, calldata))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	inputs := []interface{}{curr0, curr1}
-	_, err = p.AddActions(SETTLE_PAIR, inputs)
-	return err
+	return &utils.MethodParameters{Calldata: calldata, Value: big.NewInt(0)}, nil
 }
 
-func (p *V4PositionPlanner) AddTakePair(currency0, currency1 *core.Currency, recipient common.Address) error {
-	var err error
-	curr0, err := utils.ToAddress(*currency0)
+/**
+ * Produces the calldata for collecting the fees of a position, done by decreasing its liquidity by 0
+ * and taking both currencies
+ * @param options Additional information necessary for generating the calldata
+ */
+func CollectCallParameters(options *CollectOptions) (*utils.MethodParameters, error) {
+	planner := NewV4PositionPlanner()
+	zero := big.NewInt(0)
+	if err := planner.AddDecrease(options.TokenId, zero, zero, zero, options.HookData); err != nil {
+		return nil, err
+	}
+	if err := planner.AddTakePair(&options.Currency0, &options.Currency1, options.Recipient); err != nil {
+		return nil, err
+	}
+	calldata, err := planner.ModifyLiquiditiesCalldata(options.Deadline)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	curr1, err := utils.ToAddress(*currency1)
+	calldata, err = utils.EncodeMulticall([][]byte{calldata})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &utils.MethodParameters{Calldata: calldata, Value: big.NewInt(0)}, nil
+}
 
-	inputs := []interface{}{curr0, curr1, recipient.Hex()}
-	_, err = p.AddActions(TAKE_PAIR, inputs)
-	return err
+var fractionOne = core.NewFraction(big.NewInt(1), big.NewInt(1))
+
+// EncodeInitializePool encodes the calldata of PositionManager.initializePool
+func EncodeInitializePool(poolKey PoolKey, sqrtPriceX96 *big.Int) ([]byte, error) {
+	return encodeMethodCall(initializePoolMethod, poolKey, sqrtPriceX96)
 }
 
-func (p *V4PositionPlanner) AddSweep(currency *core.Currency, to common.Address) error {
-	curr, err := utils.ToAddress(*currency)
+/**
+ * Encodes the calldata of PositionManager.modifyLiquidities
+ * @param unlockData The finalized actions and params, see V4Planner.Finalize
+ * @param deadline The timestamp after which the call reverts
+ * @returns The calldata
+ */
+func EncodeModifyLiquidities(unlockData []byte, deadline *big.Int) ([]byte, error) {
+	if deadline == nil {
+		return nil, ErrNilDeadline
+	}
+	return encodeMethodCall(modifyLiquiditiesMethod, unlockData, deadline)
+}
+
+type V4PositionPlanner struct {
+	V4Planner
+}
+
+// NewV4PositionPlanner returns an empty planner for PositionManager actions
+func NewV4PositionPlanner() *V4PositionPlanner {
+	return &V4PositionPlanner{V4Planner: *NewV4Planner()}
+}
+
+// ModifyLiquiditiesCalldata finalizes the planned actions and wraps them into modifyLiquidities calldata
+func (p *V4PositionPlanner) ModifyLiquiditiesCalldata(deadline *big.Int) ([]byte, error) {
+	unlockData, err := p.Finalize()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	input := []interface{}{curr, to}
-	_, err = p.AddActions(SWEEP, input)
+	return EncodeModifyLiquidities(unlockData, deadline)
+}
+
+func (p *V4PositionPlanner) AddMint(pool *Pool, tickLower, tickUpper int, liquidity *big.Int, amount0Max, amount1Max *big.Int, owner common.Address, hookData []byte) error {
+	_, err := p.AddMintPosition(pool.PoolKey, tickLower, tickUpper, liquidity, amount0Max, amount1Max, owner, hookData)
+	return err
+}
+
+func (p *V4PositionPlanner) AddIncrease(tokenId, liquidity, amount0Max, amount1Max *big.Int, hookData []byte) error {
+	_, err := p.AddIncreaseLiquidity(tokenId, liquidity, amount0Max, amount1Max, hookData)
+	return err
+}
+
+func (p *V4PositionPlanner) AddDecrease(tokenId, liquidity, amount0Min, amount1Min *big.Int, hookData []byte) error {
+	_, err := p.AddDecreaseLiquidity(tokenId, liquidity, amount0Min, amount1Min, hookData)
+	return err
+}
+
+func (p *V4PositionPlanner) AddBurn(tokenId, amount0Min, amount1Min *big.Int, hookData []byte) error {
+	_, err := p.AddBurnPosition(tokenId, amount0Min, amount1Min, hookData)
+	return err
+}
+
+func (p *V4PositionPlanner) AddSettlePair(currency0, currency1 *core.Currency) error {
+	_, err := p.V4Planner.AddSettlePair(currency0, currency1)
+	return err
+}
+
+func (p *V4PositionPlanner) AddTakePair(currency0, currency1 *core.Currency, recipient common.Address) error {
+	_, err := p.V4Planner.AddTakePair(currency0, currency1, recipient)
+	return err
+}
+
+func (p *V4PositionPlanner) AddSweep(currency *core.Currency, to common.Address) error {
+	_, err := p.V4Planner.AddSweep(currency, to)
 	return err
 }
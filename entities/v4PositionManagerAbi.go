@@ -0,0 +1,276 @@
+package entities
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PositionManagerABI is the parsed utils.PositionManagerABI
+var PositionManagerABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(utils.PositionManagerABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// positionManagerMethod looks a method up by signature, overloads such as the ERC721 and permit2 permit
+// get numbered names in go-ethereum so their names cannot be relied on
+func positionManagerMethod(sig string) abi.Method {
+	for _, method := range PositionManagerABI.Methods {
+		if method.Sig == sig {
+			return method
+		}
+	}
+	panic("PositionManager ABI has no method " + sig)
+}
+
+var (
+	pmGetPoolAndPositionInfo = positionManagerMethod("getPoolAndPositionInfo(uint256)")
+	pmGetPositionLiquidity   = positionManagerMethod("getPositionLiquidity(uint256)")
+	pmPositionInfo           = positionManagerMethod("positionInfo(uint256)")
+	pmNextTokenId            = positionManagerMethod("nextTokenId()")
+	pmPermit                 = positionManagerMethod("permit(address,uint256,uint256,uint256,bytes)")
+	pmPermitForAll           = positionManagerMethod("permitForAll(address,address,bool,uint256,uint256,bytes)")
+	pmPermit2Single          = positionManagerMethod("permit(address,((address,uint160,uint48,uint48),address,uint256),bytes)")
+	pmPermit2Batch           = positionManagerMethod("permitBatch(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)")
+	pmSubscribe              = positionManagerMethod("subscribe(uint256,address,bytes)")
+	pmUnsubscribe            = positionManagerMethod("unsubscribe(uint256)")
+	pmSubscriber             = positionManagerMethod("subscriber(uint256)")
+	pmBalanceOf              = positionManagerMethod("balanceOf(address)")
+	pmOwnerOf                = positionManagerMethod("ownerOf(uint256)")
+	pmApprove                = positionManagerMethod("approve(address,uint256)")
+	pmGetApproved            = positionManagerMethod("getApproved(uint256)")
+	pmSetApprovalForAll      = positionManagerMethod("setApprovalForAll(address,bool)")
+	pmIsApprovedForAll       = positionManagerMethod("isApprovedForAll(address,address)")
+	pmTransferFrom           = positionManagerMethod("transferFrom(address,address,uint256)")
+	pmSafeTransferFrom       = positionManagerMethod("safeTransferFrom(address,address,uint256)")
+	pmSafeTransferFromData   = positionManagerMethod("safeTransferFrom(address,address,uint256,bytes)")
+	pmTokenURI               = positionManagerMethod("tokenURI(uint256)")
+)
+
+// unpackResult decodes the return data of the method and checks the number of values
+func unpackResult(method abi.Method, data []byte) (*paramReader, error) {
+	values, err := method.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method.Name, err)
+	}
+	if len(values) != len(method.Outputs) {
+		return nil, fmt.Errorf("%s: %w", method.Name, ErrInvalidActionParams)
+	}
+	return &paramReader{values: values}, nil
+}
+
+func EncodeGetPoolAndPositionInfo(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmGetPoolAndPositionInfo, tokenId)
+}
+
+// DecodeGetPoolAndPositionInfoResult returns the key of the pool of the position and its decoded position info
+func DecodeGetPoolAndPositionInfoResult(data []byte) (PoolKey, PositionInfo, error) {
+	r, err := unpackResult(pmGetPoolAndPositionInfo, data)
+	if err != nil {
+		return PoolKey{}, PositionInfo{}, err
+	}
+	poolKey, packed := r.poolKey(0), r.bigInt(1)
+	if r.err != nil {
+		return PoolKey{}, PositionInfo{}, r.err
+	}
+	info, err := DecodePositionInfo(packed)
+	return poolKey, info, err
+}
+
+func EncodeGetPositionLiquidity(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmGetPositionLiquidity, tokenId)
+}
+
+func DecodeGetPositionLiquidityResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(pmGetPositionLiquidity, data)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := r.bigInt(0)
+	return liquidity, r.err
+}
+
+// EncodePositionInfoCall encodes the positionInfo view of the position manager, see EncodePositionInfo for packing a PositionInfo
+func EncodePositionInfoCall(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmPositionInfo, tokenId)
+}
+
+// DecodePositionInfoResult returns the decoded position info
+func DecodePositionInfoResult(data []byte) (PositionInfo, error) {
+	r, err := unpackResult(pmPositionInfo, data)
+	if err != nil {
+		return PositionInfo{}, err
+	}
+	packed := r.bigInt(0)
+	if r.err != nil {
+		return PositionInfo{}, r.err
+	}
+	return DecodePositionInfo(packed)
+}
+
+// DecodeInitializePoolResult returns the initial tick of the pool
+func DecodeInitializePoolResult(data []byte) (int, error) {
+	r, err := unpackResult(initializePoolMethod, data)
+	if err != nil {
+		return 0, err
+	}
+	tick := r.int(0)
+	return tick, r.err
+}
+
+func EncodeNextTokenId() ([]byte, error) {
+	return encodeMethodCall(pmNextTokenId)
+}
+
+func DecodeNextTokenIdResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(pmNextTokenId, data)
+	if err != nil {
+		return nil, err
+	}
+	tokenId := r.bigInt(0)
+	return tokenId, r.err
+}
+
+/**
+ * Encodes the ERC721 permit of the position manager, approving spender for the position with a signature of its owner
+ * @param spender The account to approve
+ * @param tokenId The id of the position
+ * @param deadline The timestamp after which the signature is no longer valid
+ * @param nonce The unordered nonce of the signature
+ * @param signature The signature of the owner
+ */
+func EncodeERC721Permit(spender common.Address, tokenId, deadline, nonce *big.Int, signature []byte) ([]byte, error) {
+	return encodeMethodCall(pmPermit, spender, tokenId, deadline, nonce, signature)
+}
+
+// EncodePermitForAll encodes the permitForAll of the position manager, setting operator's approval for all positions of owner
+func EncodePermitForAll(owner, operator common.Address, approved bool, deadline, nonce *big.Int, signature []byte) ([]byte, error) {
+	return encodeMethodCall(pmPermitForAll, owner, operator, approved, deadline, nonce, signature)
+}
+
+// EncodePermit2Single encodes the position manager submitting a Permit2 allowance of one token signed by owner to Permit2
+func EncodePermit2Single(owner common.Address, permit PermitSingle, signature []byte) ([]byte, error) {
+	return encodeMethodCall(pmPermit2Single, owner, permit, signature)
+}
+
+// EncodePermit2Batch encodes the position manager submitting Permit2 allowances of several tokens signed by owner to Permit2
+func EncodePermit2Batch(owner common.Address, permit PermitBatch, signature []byte) ([]byte, error) {
+	return encodeMethodCall(pmPermit2Batch, owner, permit, signature)
+}
+
+// EncodeSubscribe encodes subscribing newSubscriber to the notifications of the position
+func EncodeSubscribe(tokenId *big.Int, newSubscriber common.Address, data []byte) ([]byte, error) {
+	return encodeMethodCall(pmSubscribe, tokenId, newSubscriber, data)
+}
+
+func EncodeUnsubscribe(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmUnsubscribe, tokenId)
+}
+
+func EncodeSubscriber(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmSubscriber, tokenId)
+}
+
+func DecodeSubscriberResult(data []byte) (common.Address, error) {
+	r, err := unpackResult(pmSubscriber, data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	subscriber := r.address(0)
+	return subscriber, r.err
+}
+
+func EncodeBalanceOf(owner common.Address) ([]byte, error) {
+	return encodeMethodCall(pmBalanceOf, owner)
+}
+
+func DecodeBalanceOfResult(data []byte) (*big.Int, error) {
+	r, err := unpackResult(pmBalanceOf, data)
+	if err != nil {
+		return nil, err
+	}
+	balance := r.bigInt(0)
+	return balance, r.err
+}
+
+func EncodeOwnerOf(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmOwnerOf, tokenId)
+}
+
+func DecodeOwnerOfResult(data []byte) (common.Address, error) {
+	r, err := unpackResult(pmOwnerOf, data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	owner := r.address(0)
+	return owner, r.err
+}
+
+func EncodeApprove(spender common.Address, tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmApprove, spender, tokenId)
+}
+
+func EncodeGetApproved(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmGetApproved, tokenId)
+}
+
+func DecodeGetApprovedResult(data []byte) (common.Address, error) {
+	r, err := unpackResult(pmGetApproved, data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	approved := r.address(0)
+	return approved, r.err
+}
+
+func EncodeSetApprovalForAll(operator common.Address, approved bool) ([]byte, error) {
+	return encodeMethodCall(pmSetApprovalForAll, operator, approved)
+}
+
+func EncodeIsApprovedForAll(owner, operator common.Address) ([]byte, error) {
+	return encodeMethodCall(pmIsApprovedForAll, owner, operator)
+}
+
+func DecodeIsApprovedForAllResult(data []byte) (bool, error) {
+	r, err := unpackResult(pmIsApprovedForAll, data)
+	if err != nil {
+		return false, err
+	}
+	approved := r.bool(0)
+	return approved, r.err
+}
+
+func EncodeTransferFrom(from, to common.Address, tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmTransferFrom, from, to, tokenId)
+}
+
+// EncodeSafeTransferFrom encodes safeTransferFrom, the overload with data is used when data is not nil
+func EncodeSafeTransferFrom(from, to common.Address, tokenId *big.Int, data []byte) ([]byte, error) {
+	if data == nil {
+		return encodeMethodCall(pmSafeTransferFrom, from, to, tokenId)
+	}
+	return encodeMethodCall(pmSafeTransferFromData, from, to, tokenId, data)
+}
+
+func EncodeTokenURI(tokenId *big.Int) ([]byte, error) {
+	return encodeMethodCall(pmTokenURI, tokenId)
+}
+
+func DecodeTokenURIResult(data []byte) (string, error) {
+	r, err := unpackResult(pmTokenURI, data)
+	if err != nil {
+		return "", err
+	}
+	uri, ok := r.value(0).(string)
+	if !ok {
+		r.fail(0)
+	}
+	return uri, r.err
+}
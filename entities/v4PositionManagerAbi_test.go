@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// abiWords concatenates hex encoded 32 byte words into abi encoded data
+func abiWords(t *testing.T, words ...string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(strings.Join(words, ""))
+	if err != nil || len(data)%32 != 0 {
+		t.Fatalf("invalid words %v", words)
+	}
+	return data
+}
+
+func TestPositionManagerSelectors(t *testing.T) {
+	tests := []struct {
+		method   string
+		selector string
+	}{
+		{"getPoolAndPositionInfo(uint256)", "7ba03aad"},
+		{"getPositionLiquidity(uint256)", "1efeed33"},
+		{"positionInfo(uint256)", "89097a6a"},
+		{"nextTokenId()", "75794a3c"},
+		{"permit(address,uint256,uint256,uint256,bytes)", "0f5730f1"},
+		{"permitForAll(address,address,bool,uint256,uint256,bytes)", "3aea60f0"},
+		{"permit(address,((address,uint160,uint48,uint48),address,uint256),bytes)", "2b67b570"},
+		{"permitBatch(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)", "002a3e3a"},
+		{"subscribe(uint256,address,bytes)", "2b9261de"},
+		{"unsubscribe(uint256)", "ad0b27fb"},
+		{"subscriber(uint256)", "16a24131"},
+		{"balanceOf(address)", "70a08231"},
+		{"ownerOf(uint256)", "6352211e"},
+		{"approve(address,uint256)", "095ea7b3"},
+		{"getApproved(uint256)", "081812fc"},
+		{"setApprovalForAll(address,bool)", "a22cb465"},
+		{"isApprovedForAll(address,address)", "e985e9c5"},
+		{"transferFrom(address,address,uint256)", "23b872dd"},
+		{"safeTransferFrom(address,address,uint256)", "42842e0e"},
+		{"safeTransferFrom(address,address,uint256,bytes)", "b88d4fde"},
+		{"tokenURI(uint256)", "c87b56dd"},
+		{"modifyLiquidities(bytes,uint256)", "dd46508f"},
+		{"modifyLiquiditiesWithoutUnlock(bytes,bytes[])", "4afe393c"},
+		{"initializePool((address,address,uint24,int24,address),uint160)", "f7020405"},
+		{"multicall(bytes[])", "ac9650d8"},
+	}
+	for _, tt := range tests {
+		if selector := hex.EncodeToString(positionManagerMethod(tt.method).ID); selector != tt.selector {
+			t.Errorf("%s: selector %s, want %s", tt.method, selector, tt.selector)
+		}
+	}
+}
+
+func TestPositionManagerCallEncoding(t *testing.T) {
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	operator := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	var (
+		ownerWord    = "00000000000000000000000000000000000000000000000000000000000000aa"
+		operatorWord = "00000000000000000000000000000000000000000000000000000000000000bb"
+		one          = "0000000000000000000000000000000000000000000000000000000000000001"
+		seven        = "0000000000000000000000000000000000000000000000000000000000000007"
+	)
+	encode := func(calldata []byte, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hex.EncodeToString(calldata)
+	}
+	tests := []struct {
+		name     string
+		calldata string
+		want     string
+	}{
+		{"ownerOf", encode(EncodeOwnerOf(big.NewInt(1))), "6352211e" + one},
+		{"balanceOf", encode(EncodeBalanceOf(owner)), "70a08231" + ownerWord},
+		{"approve", encode(EncodeApprove(operator, big.NewInt(7))), "095ea7b3" + operatorWord + seven},
+		{"setApprovalForAll", encode(EncodeSetApprovalForAll(operator, true)), "a22cb465" + operatorWord + one},
+		{"isApprovedForAll", encode(EncodeIsApprovedForAll(owner, operator)), "e985e9c5" + ownerWord + operatorWord},
+		{"transferFrom", encode(EncodeTransferFrom(owner, operator, big.NewInt(7))), "23b872dd" + ownerWord + operatorWord + seven},
+		{"safeTransferFrom", encode(EncodeSafeTransferFrom(owner, operator, big.NewInt(7), nil)), "42842e0e" + ownerWord + operatorWord + seven},
+		{"safeTransferFrom with data", encode(EncodeSafeTransferFrom(owner, operator, big.NewInt(7), []byte{0xca, 0xfe})), "b88d4fde" + ownerWord + operatorWord + seven +
+			"0000000000000000000000000000000000000000000000000000000000000080" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"cafe000000000000000000000000000000000000000000000000000000000000"},
+		// an empty data still selects the overload with data
+		{"safeTransferFrom with empty data", encode(EncodeSafeTransferFrom(owner, operator, big.NewInt(7), []byte{})), "b88d4fde" + ownerWord + operatorWord + seven +
+			"0000000000000000000000000000000000000000000000000000000000000080" +
+			"0000000000000000000000000000000000000000000000000000000000000000"},
+		{"nextTokenId", encode(EncodeNextTokenId()), "75794a3c"},
+		{"getPoolAndPositionInfo", encode(EncodeGetPoolAndPositionInfo(big.NewInt(1))), "7ba03aad" + one},
+		{"getPositionLiquidity", encode(EncodeGetPositionLiquidity(big.NewInt(7))), "1efeed33" + seven},
+		{"positionInfo", encode(EncodePositionInfoCall(big.NewInt(7))), "89097a6a" + seven},
+		{"unsubscribe", encode(EncodeUnsubscribe(big.NewInt(1))), "ad0b27fb" + one},
+		{"subscriber", encode(EncodeSubscriber(big.NewInt(1))), "16a24131" + one},
+		{"subscribe", encode(EncodeSubscribe(big.NewInt(1), operator, nil)), "2b9261de" + one + operatorWord +
+			"0000000000000000000000000000000000000000000000000000000000000060" +
+			"0000000000000000000000000000000000000000000000000000000000000000"},
+		{"ERC721 permit", encode(EncodeERC721Permit(operator, big.NewInt(1), big.NewInt(7), big.NewInt(1), []byte{0x1b})), "0f5730f1" + operatorWord + one + seven + one +
+			"00000000000000000000000000000000000000000000000000000000000000a0" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"1b00000000000000000000000000000000000000000000000000000000000000"},
+		{"permitForAll", encode(EncodePermitForAll(owner, operator, true, big.NewInt(7), big.NewInt(1), []byte{0x1b})), "3aea60f0" + ownerWord + operatorWord + one + seven + one +
+			"00000000000000000000000000000000000000000000000000000000000000c0" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"1b00000000000000000000000000000000000000000000000000000000000000"},
+		{"permit2 single", encode(EncodePermit2Single(owner, PermitSingle{
+			Details: PermitDetails{Token: operator, Amount: big.NewInt(7), Expiration: big.NewInt(7), Nonce: big.NewInt(1)},
+			Spender: owner, SigDeadline: big.NewInt(7),
+		}, []byte{0x1b})), "2b67b570" + ownerWord + operatorWord + seven + seven + one + ownerWord + seven +
+			"0000000000000000000000000000000000000000000000000000000000000100" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"1b00000000000000000000000000000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		if tt.calldata != tt.want {
+			t.Errorf("%s: calldata %s, want %s", tt.name, tt.calldata, tt.want)
+		}
+	}
+}
+
+func TestPositionManagerResultDecoding(t *testing.T) {
+	var (
+		addressWord = "000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		trueWord    = "0000000000000000000000000000000000000000000000000000000000000001"
+		seven       = "0000000000000000000000000000000000000000000000000000000000000007"
+	)
+
+	owner, err := DecodeOwnerOfResult(abiWords(t, addressWord))
+	if err != nil || owner != common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48") {
+		t.Errorf("ownerOf decoded as %s, %v", owner, err)
+	}
+	approved, err := DecodeGetApprovedResult(abiWords(t, addressWord))
+	if err != nil || approved != owner {
+		t.Errorf("getApproved decoded as %s, %v", approved, err)
+	}
+	subscriber, err := DecodeSubscriberResult(abiWords(t, addressWord))
+	if err != nil || subscriber != owner {
+		t.Errorf("subscriber decoded as %s, %v", subscriber, err)
+	}
+	approvedForAll, err := DecodeIsApprovedForAllResult(abiWords(t, trueWord))
+	if err != nil || !approvedForAll {
+		t.Errorf("isApprovedForAll decoded as %v, %v", approvedForAll, err)
+	}
+	for name, decode := range map[string]func([]byte) (*big.Int, error){
+		"balanceOf":            DecodeBalanceOfResult,
+		"nextTokenId":          DecodeNextTokenIdResult,
+		"getPositionLiquidity": DecodeGetPositionLiquidityResult,
+	} {
+		if value, err := decode(abiWords(t, seven)); err != nil || value.Int64() != 7 {
+			t.Errorf("%s decoded as %v, %v", name, value, err)
+		}
+		if _, err := decode(abiWords(t)); err == nil {
+			t.Errorf("%s decoded empty data", name)
+		}
+	}
+	uri, err := DecodeTokenURIResult(abiWords(t,
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"000000000000000000000000000000000000000000000000000000000000000d",
+		"646174613a2c7b2269223a317d00000000000000000000000000000000000000",
+	))
+	if err != nil || uri != `data:,{"i":1}` {
+		t.Errorf("tokenURI decoded as %q, %v", uri, err)
+	}
+	// the string runs past the end of the data
+	if _, err := DecodeTokenURIResult(abiWords(t,
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+	)); err == nil {
+		t.Error("truncated tokenURI decoded")
+	}
+
+	// initializePool returns the int24 tick, -1 when the pool is already initialized
+	tick, err := DecodeInitializePoolResult(abiWords(t, "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	if err != nil || tick != -1 {
+		t.Errorf("initializePool decoded as %d, %v", tick, err)
+	}
+}
+
+func TestDecodeGetPoolAndPositionInfoResult(t *testing.T) {
+	// the pool key of parserTestKey followed by the info of the range [-600, 600] with a subscriber
+	info := "11111111111111111111111111111111111111111111111111" + "000258" + "fffda8" + "01"
+	data := abiWords(t,
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		"0000000000000000000000000000000000000000000000000000000000000bb8",
+		"000000000000000000000000000000000000000000000000000000000000003c",
+		"0000000000000000000000000000000000000000000000000000000000000080",
+		info,
+	)
+	poolKey, positionInfo, err := DecodeGetPoolAndPositionInfoResult(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if poolKey != parserTestKey {
+		t.Errorf("pool key decoded as %+v", poolKey)
+	}
+	var poolId [25]byte
+	for i := range poolId {
+		poolId[i] = 0x11
+	}
+	want := PositionInfo{PoolId: poolId, TickUpper: 600, TickLower: -600, HasSubscriber: true}
+	if positionInfo != want {
+		t.Errorf("position info decoded as %+v, want %+v", positionInfo, want)
+	}
+
+	// positionInfo returns the same packed word
+	positionInfo, err = DecodePositionInfoResult(abiWords(t, info))
+	if err != nil || positionInfo != want {
+		t.Errorf("positionInfo decoded as %+v, %v", positionInfo, err)
+	}
+
+	if _, _, err := DecodeGetPoolAndPositionInfoResult(data[:len(data)-32]); err == nil {
+		t.Error("result without the position info decoded")
+	}
+}
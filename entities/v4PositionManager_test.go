@@ -0,0 +1,287 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/utils"
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// the fixtures of the v4 SDK V4PositionManager tests
+var (
+	pmTestNative    core.Currency = core.EtherOnChain(1)
+	pmTestRecipient               = common.HexToAddress("0x0000000000000000000000000000000000000003")
+	pmTestTokenId                 = big.NewInt(1)
+	pmTestDeadline                = big.NewInt(123)
+	pmTestSlippage                = core.NewPercent(big.NewInt(1), big.NewInt(100))
+)
+
+func pmTestPool(t *testing.T, currency0, currency1 core.Currency) *Pool {
+	t.Helper()
+	pool, err := NewPool(currency0, currency1, 3000, 60, common.Address{},
+		utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1)), big.NewInt(0), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func pmTestPosition(t *testing.T, pool *Pool) *Position {
+	t.Helper()
+	position, err := NewPosition(pool, big.NewInt(5000000), -60, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return position
+}
+
+// expectModifyLiquidities checks the calldata is the given calls followed by modifyLiquidities of the planner
+func expectModifyLiquidities(t *testing.T, params *utils.MethodParameters, planner *V4PositionPlanner, value *big.Int, calls ...[]byte) {
+	t.Helper()
+	modifyLiquidities, err := planner.ModifyLiquiditiesCalldata(pmTestDeadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := utils.EncodeMulticall(append(calls, modifyLiquidities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(params.Calldata, expected) {
+		t.Errorf("calldata mismatch\n got: %x\nwant: %x", params.Calldata, expected)
+	}
+	if params.Value.Cmp(value) != 0 {
+		t.Errorf("value %s, want %s", params.Value, value)
+	}
+}
+
+func TestAddCallParametersMaximumAmounts(t *testing.T) {
+	position := pmTestPosition(t, pmTestPool(t, testToken0, testToken1))
+	maximum, err := position.MintAmountsWithSlippage(pmTestSlippage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5000000 liquidity over ticks -60..60 at price 1 needs 14977 of each token, a 1% move of the price takes it out
+	// of the range so each maximum is the amount of the whole range in that token
+	if maximum.Amount0.String() != "29999" || maximum.Amount1.String() != "29999" {
+		t.Fatalf("unexpected maximum amounts %s %s", maximum.Amount0, maximum.Amount1)
+	}
+
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := ParseCalldata(params.Calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mint, ok := call.Actions[0].Decoded.(MintPosition)
+	if !ok {
+		t.Fatalf("first action is %s", call.Actions[0].ActionName)
+	}
+	mintAmounts, err := position.MintAmounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mint.Amount0Max.Cmp(mintAmounts.Amount0) < 0 || mint.Amount1Max.Cmp(mintAmounts.Amount1) < 0 {
+		t.Errorf("maximum amounts %s %s do not cover the mint amounts %s %s",
+			mint.Amount0Max, mint.Amount1Max, mintAmounts.Amount0, mintAmounts.Amount1)
+	}
+}
+
+func TestAddCallParametersMint(t *testing.T) {
+	pool := pmTestPool(t, testToken0, testToken1)
+	position := pmTestPosition(t, pool)
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddMintPosition(pool.PoolKey, -60, 60, big.NewInt(5000000), maximum.Amount0, maximum.Amount1, pmTestRecipient, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.V4Planner.AddSettlePair(&pool.Currency0, &pool.Currency1); err != nil {
+		t.Fatal(err)
+	}
+	expectModifyLiquidities(t, params, planner, big.NewInt(0))
+}
+
+func TestAddCallParametersCreatePool(t *testing.T) {
+	pool := pmTestPool(t, testToken0, testToken1)
+	position := pmTestPosition(t, pool)
+	sqrtPriceX96 := utils.EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient, CreatePool: true, SqrtPriceX96: sqrtPriceX96},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initializePool, err := EncodeInitializePool(pool.PoolKey, sqrtPriceX96)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddMintPosition(pool.PoolKey, -60, 60, big.NewInt(5000000), maximum.Amount0, maximum.Amount1, pmTestRecipient, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.V4Planner.AddSettlePair(&pool.Currency0, &pool.Currency1); err != nil {
+		t.Fatal(err)
+	}
+	expectModifyLiquidities(t, params, planner, big.NewInt(0), initializePool)
+}
+
+func TestAddCallParametersIncrease(t *testing.T) {
+	pool := pmTestPool(t, testToken0, testToken1)
+	position, err := NewPosition(pool, big.NewInt(666), -60, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions: CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		TokenId:       pmTestTokenId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddIncreaseLiquidity(pmTestTokenId, big.NewInt(666), maximum.Amount0, maximum.Amount1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.V4Planner.AddSettlePair(&pool.Currency0, &pool.Currency1); err != nil {
+		t.Fatal(err)
+	}
+	expectModifyLiquidities(t, params, planner, big.NewInt(0))
+}
+
+func TestAddCallParametersNative(t *testing.T) {
+	pool := pmTestPool(t, pmTestNative, testToken1)
+	position := pmTestPosition(t, pool)
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient},
+		UseNative:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddMintPosition(pool.PoolKey, -60, 60, big.NewInt(5000000), maximum.Amount0, maximum.Amount1, pmTestRecipient, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.V4Planner.AddSettlePair(&pool.Currency0, &pool.Currency1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.V4Planner.AddSweep(&pool.Currency0, constants.MsgSender); err != nil {
+		t.Fatal(err)
+	}
+	// the maximum amount of ether is sent and the remainder swept back
+	expectModifyLiquidities(t, params, planner, maximum.Amount0)
+}
+
+func TestAddCallParametersMigrate(t *testing.T) {
+	pool := pmTestPool(t, testToken0, testToken1)
+	position := pmTestPosition(t, pool)
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient, Migrate: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddMintPosition(pool.PoolKey, -60, 60, big.NewInt(5000000), maximum.Amount0, maximum.Amount1, pmTestRecipient, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, currency := range []*core.Currency{&pool.Currency0, &pool.Currency1} {
+		if _, err := planner.V4Planner.AddSettle(currency, false, big.NewInt(FULL_DELTA_AMOUNT)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, currency := range []*core.Currency{&pool.Currency0, &pool.Currency1} {
+		if _, err := planner.V4Planner.AddSweep(currency, pmTestRecipient); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectModifyLiquidities(t, params, planner, big.NewInt(0))
+}
+
+func TestAddCallParametersMigrateNative(t *testing.T) {
+	pool := pmTestPool(t, pmTestNative, testToken1)
+	position := pmTestPosition(t, pool)
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient, Migrate: true},
+		UseNative:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maximum, _ := position.MintAmountsWithSlippage(pmTestSlippage)
+	planner := NewV4PositionPlanner()
+	if _, err := planner.AddMintPosition(pool.PoolKey, -60, 60, big.NewInt(5000000), maximum.Amount0, maximum.Amount1, pmTestRecipient, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.AddUnwrap(big.NewInt(FULL_DELTA_AMOUNT)); err != nil {
+		t.Fatal(err)
+	}
+	for _, currency := range []*core.Currency{&pool.Currency0, &pool.Currency1} {
+		if _, err := planner.V4Planner.AddSettle(currency, false, big.NewInt(FULL_DELTA_AMOUNT)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the weth left over after unwrapping is swept
+	weth := pool.Currency0.Wrapped()
+	for _, currency := range []core.Currency{weth, pool.Currency1} {
+		if _, err := planner.V4Planner.AddSweep(&currency, pmTestRecipient); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectModifyLiquidities(t, params, planner, big.NewInt(0))
+}
+
+func TestAddCallParametersCalldata(t *testing.T) {
+	position := pmTestPosition(t, pmTestPool(t, testToken0, testToken1))
+	params, err := AddCallParameters(position, &AddLiquidityOptions{
+		CommonOptions:       CommonOptions{SlippageTolerance: pmTestSlippage, Deadline: pmTestDeadline},
+		MintSpecificOptions: MintSpecificOptions{Recipient: pmTestRecipient},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// modifyLiquidities(abi.encode(MINT_POSITION ‖ SETTLE_PAIR, params), 123)
+	const expected = "dd46508f" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"000000000000000000000000000000000000000000000000000000000000007b"
+	if got := hex.EncodeToString(params.Calldata[:68]); got != expected {
+		t.Errorf("calldata head %s, want %s", got, expected)
+	}
+	call, err := ParseCalldata(params.Calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(call.Actions) != 2 || call.Actions[0].ActionType != MINT_POSITION || call.Actions[1].ActionType != SETTLE_PAIR {
+		t.Fatalf("unexpected actions %v", call.Actions)
+	}
+}
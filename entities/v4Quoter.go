@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	ErrQuoteMultipleSwaps = errors.New("only trades with a single swap can be quoted in one call")
+	ErrUnexpectedRevert   = errors.New("unexpected quoter revert")
+)
+
+const QUOTE_EXACT_SINGLE_PARAMS_STRUCT = "(" + POOL_KEY_STRUCT + " poolKey,bool zeroForOne,uint128 exactAmount,bytes hookData)"
+const QUOTE_EXACT_PARAMS_STRUCT = "(address exactCurrency," + PATH_KEY_STRUCT + "[] path,uint128 exactAmount)"
+
+var (
+	quoteExactInputSingleMethod  = mustNewMethod("quoteExactInputSingle", []string{QUOTE_EXACT_SINGLE_PARAMS_STRUCT + " params"}, []string{"uint256 amountOut", "uint256 gasEstimate"})
+	quoteExactInputMethod        = mustNewMethod("quoteExactInput", []string{QUOTE_EXACT_PARAMS_STRUCT + " params"}, []string{"uint256 amountOut", "uint256 gasEstimate"})
+	quoteExactOutputSingleMethod = mustNewMethod("quoteExactOutputSingle", []string{QUOTE_EXACT_SINGLE_PARAMS_STRUCT + " params"}, []string{"uint256 amountIn", "uint256 gasEstimate"})
+	quoteExactOutputMethod       = mustNewMethod("quoteExactOutput", []string{QUOTE_EXACT_PARAMS_STRUCT + " params"}, []string{"uint256 amountIn", "uint256 gasEstimate"})
+
+	// QuoteSwap carries the quoted amount out of the unlock callback of the quoter
+	quoteSwapError             = mustNewError("QuoteSwap", "uint256 amount")
+	unexpectedRevertBytesError = mustNewError("UnexpectedRevertBytes", "bytes revertData")
+	// Error(string) is the revert reason of require and revert with a message
+	revertReasonError = mustNewError("Error", "string reason")
+)
+
+// QuoteResult is the result of a V4Quoter quote
+type QuoteResult struct {
+	// The amount out for exact input quotes, the amount in for exact output quotes
+	Amount      *big.Int
+	GasEstimate *big.Int
+}
+
+// quoteMethod returns the quoter method for a route of the given length and trade type
+func quoteMethod(singlePool bool, tradeType core.TradeType) abi.Method {
+	switch {
+	case singlePool && tradeType == core.ExactInput:
+		return quoteExactInputSingleMethod
+	case singlePool:
+		return quoteExactOutputSingleMethod
+	case tradeType == core.ExactInput:
+		return quoteExactInputMethod
+	default:
+		return quoteExactOutputMethod
+	}
+}
+
+/**
+ * Produces the V4Quoter calldata to quote the route, single pool routes use the Single variants
+ * @param route The route to quote
+ * @param amount The exact amount in for exact input, the exact amount out for exact output
+ * @param tradeType The type of the trade
+ * @param hookData Optional data passed to the hooks of single pool quotes
+ */
+func QuoteCallParameters(route *Route, amount *core.CurrencyAmount, tradeType core.TradeType, hookData []byte) ([]byte, error) {
+	if len(route.Pools) == 1 {
+		pool := route.Pools[0]
+		zeroForOne := currencyAddress(route.PathInput) == pool.PoolKey.Currency0
+		params := []interface{}{pool.PoolKey, zeroForOne, amount, hookData}
+		return encodeMethodCall(quoteMethod(true, tradeType), params)
+	}
+
+	exactOutput := tradeType == core.ExactOutput
+	path, err := EncodeRouteToPath(route, exactOutput)
+	if err != nil {
+		return nil, err
+	}
+	exactCurrency := route.PathInput
+	if exactOutput {
+		exactCurrency = route.PathOutput
+	}
+	params := []interface{}{exactCurrency, path, amount}
+	return encodeMethodCall(quoteMethod(false, tradeType), params)
+}
+
+// TradeQuoteCallParameters produces the V4Quoter calldata to quote the single swap of the trade
+func TradeQuoteCallParameters(trade *Trade, hookData []byte) ([]byte, error) {
+	if len(trade.Swaps) != 1 {
+		return nil, ErrQuoteMultipleSwaps
+	}
+	swap := trade.Swaps[0]
+	amount := swap.InputAmount
+	if trade.TradeType == core.ExactOutput {
+		amount = swap.OutputAmount
+	}
+	return QuoteCallParameters(swap.Route, amount, trade.TradeType, hookData)
+}
+
+/**
+ * Decodes the return data of a quote call
+ * @param singlePool Whether the quote was of a single pool route
+ * @param tradeType The type of the quoted trade
+ * @param data The return data
+ */
+func DecodeQuoteResult(singlePool bool, tradeType core.TradeType, data []byte) (*QuoteResult, error) {
+	r, err := unpackResult(quoteMethod(singlePool, tradeType), data)
+	if err != nil {
+		return nil, err
+	}
+	result := &QuoteResult{Amount: r.bigInt(0), GasEstimate: r.bigInt(1)}
+	return result, r.err
+}
+
+/**
+ * Decodes the revert data of the quoter. The quoter ends each simulated swap with a QuoteSwap(amount) revert, which
+ * is returned as the amount. UnexpectedRevertBytes and Error(string) reverts are returned as errors wrapping
+ * ErrUnexpectedRevert.
+ * @param data The revert data
+ */
+func DecodeQuoteRevert(data []byte) (*big.Int, error) {
+	switch {
+	case bytes.HasPrefix(data, quoteSwapError.ID[:4]):
+		values, err := quoteSwapError.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+		r := &paramReader{values: values}
+		amount := r.bigInt(0)
+		return amount, r.err
+	case bytes.HasPrefix(data, unexpectedRevertBytesError.ID[:4]):
+		values, err := unexpectedRevertBytesError.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+		r := &paramReader{values: values}
+		revertData := r.bytes(0)
+		if r.err != nil {
+			return nil, r.err
+		}
+		// the inner revert is usually the reason the pool or a hook reverted
+		if reason, err := decodeRevertReason(revertData); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedRevert, reason)
+		}
+		return nil, fmt.Errorf("%w: 0x%x", ErrUnexpectedRevert, revertData)
+	}
+	if reason, err := decodeRevertReason(data); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedRevert, reason)
+	}
+	return nil, fmt.Errorf("%w: 0x%x", ErrUnexpectedRevert, data)
+}
+
+func decodeRevertReason(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, revertReasonError.ID[:4]) {
+		return "", ErrUnexpectedRevert
+	}
+	values, err := revertReasonError.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", err
+	}
+	reason, ok := values[0].(string)
+	if !ok {
+		return "", ErrUnexpectedRevert
+	}
+	return reason, nil
+}
@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestQuoteCallParameters(t *testing.T) {
+	direct, twoHop := plannerTestRoutes(t)
+	var (
+		token0   = "0000000000000000000000000000000000000000000000000000000000000001"
+		token1   = "0000000000000000000000000000000000000000000000000000000000000002"
+		token2   = "0000000000000000000000000000000000000000000000000000000000000003"
+		fee      = "0000000000000000000000000000000000000000000000000000000000000bb8"
+		spacing  = "000000000000000000000000000000000000000000000000000000000000003c"
+		zero     = "0000000000000000000000000000000000000000000000000000000000000000"
+		one      = "0000000000000000000000000000000000000000000000000000000000000001"
+		amount   = "00000000000000000000000000000000000000000000000000000000000003e8"
+		offset20 = "0000000000000000000000000000000000000000000000000000000000000020"
+	)
+	// the head of a PathKey with empty hook data, whose offset is after the 5 words of the head
+	pathKey := func(currency string) string {
+		return currency + fee + spacing + zero + "00000000000000000000000000000000000000000000000000000000000000a0" + zero
+	}
+	tests := []struct {
+		name      string
+		route     *Route
+		currency  core.Currency
+		tradeType core.TradeType
+		hookData  []byte
+		want      string
+	}{
+		{"quoteExactInputSingle", direct, testToken0, core.ExactInput, nil,
+			"aa9d21cb" + offset20 + token0 + token1 + fee + spacing + zero + one + amount +
+				"0000000000000000000000000000000000000000000000000000000000000100" + zero},
+		{"quoteExactOutputSingle", direct, testToken1, core.ExactOutput, []byte{0xab},
+			"58733073" + offset20 + token0 + token1 + fee + spacing + zero + one + amount +
+				"0000000000000000000000000000000000000000000000000000000000000100" + one +
+				"ab00000000000000000000000000000000000000000000000000000000000000"},
+		// the path of exact input quotes starts at the input, each path key holds the next currency
+		{"quoteExactInput", twoHop, testToken0, core.ExactInput, nil,
+			"ca253dc9" + offset20 + token0 + "0000000000000000000000000000000000000000000000000000000000000060" + amount +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000100" +
+				pathKey(token2) + pathKey(token1)},
+		// the path of exact output quotes starts at the output, each path key holds the previous currency
+		{"quoteExactOutput", twoHop, testToken1, core.ExactOutput, nil,
+			"147d2af9" + offset20 + token1 + "0000000000000000000000000000000000000000000000000000000000000060" + amount +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000100" +
+				pathKey(token0) + pathKey(token2)},
+	}
+	for _, tt := range tests {
+		calldata, err := QuoteCallParameters(tt.route, core.FromRawAmount(tt.currency, big.NewInt(1000)), tt.tradeType, tt.hookData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(calldata) != tt.want {
+			t.Errorf("%s: calldata %x\nwant %s", tt.name, calldata, tt.want)
+		}
+	}
+}
+
+func TestTradeQuoteCallParameters(t *testing.T) {
+	trade := plannerTestTrade(t, core.ExactOutput)
+	if _, err := TradeQuoteCallParameters(trade, nil); !errors.Is(err, ErrQuoteMultipleSwaps) {
+		t.Errorf("trade with two swaps returned %v", err)
+	}
+
+	// the single swap of an exact output trade is quoted by its output amount
+	trade.Swaps = trade.Swaps[1:]
+	calldata, err := TradeQuoteCallParameters(trade, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := QuoteCallParameters(trade.Swaps[0].Route, trade.Swaps[0].OutputAmount, core.ExactOutput, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(calldata) != string(want) {
+		t.Errorf("calldata %x, want %x", calldata, want)
+	}
+}
+
+func TestDecodeQuoteResult(t *testing.T) {
+	data := abiWords(t,
+		"00000000000000000000000000000000000000000000000000000000000f1b30",
+		"000000000000000000000000000000000000000000000000000000000001d4c0",
+	)
+	for _, singlePool := range []bool{true, false} {
+		for _, tradeType := range []core.TradeType{core.ExactInput, core.ExactOutput} {
+			result, err := DecodeQuoteResult(singlePool, tradeType, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Amount.Int64() != 990000 || result.GasEstimate.Int64() != 120000 {
+				t.Errorf("decoded as %s, %s", result.Amount, result.GasEstimate)
+			}
+		}
+	}
+	if _, err := DecodeQuoteResult(true, core.ExactInput, data[:32]); err == nil {
+		t.Error("result without the gas estimate decoded")
+	}
+}
+
+func TestDecodeQuoteRevert(t *testing.T) {
+	// Error("SafeCast: overflow")
+	reason := "08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000012" +
+		"53616665436173743a206f766572666c6f770000000000000000000000000000"
+	// UnexpectedRevertBytes(bytes) wrapping the 4 byte PoolNotInitialized() error
+	unexpected := "6190b2b0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"486aa30700000000000000000000000000000000000000000000000000000000"
+	// UnexpectedRevertBytes(bytes) wrapping the Error(string)
+	unexpectedReason := "6190b2b0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000064" +
+		reason + "00000000000000000000000000000000000000000000000000000000"
+
+	amount, err := DecodeQuoteRevert(common.FromHex("ecbd9804" + "00000000000000000000000000000000000000000000000000000000000f1b30"))
+	if err != nil || amount.Int64() != 990000 {
+		t.Errorf("QuoteSwap decoded as %v, %v", amount, err)
+	}
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"Error", reason, "SafeCast: overflow"},
+		{"UnexpectedRevertBytes", unexpected, "0x486aa307"},
+		{"UnexpectedRevertBytes with reason", unexpectedReason, "SafeCast: overflow"},
+		{"unknown", "deadbeef", "0xdeadbeef"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeQuoteRevert(common.FromHex(tt.data))
+		if !errors.Is(err, ErrUnexpectedRevert) || !strings.HasSuffix(err.Error(), ": "+tt.want) {
+			t.Errorf("%s: returned %v, want %s", tt.name, err, tt.want)
+		}
+	}
+	// the amount of QuoteSwap is missing
+	if _, err := DecodeQuoteRevert(common.FromHex("ecbd9804")); err == nil || errors.Is(err, ErrUnexpectedRevert) {
+		t.Errorf("truncated QuoteSwap returned %v", err)
+	}
+}
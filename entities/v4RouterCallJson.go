@@ -0,0 +1,179 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+/**
+ * Renders the decoded call as JSON for review. Params are keyed by their name in V4_BASE_ACTIONS_ABI_DEFINITION in
+ * definition order, integers are decimal strings, bytes are 0x prefixed hex and addresses are checksummed hex.
+ * @param registry Optional, currency addresses it resolves are rendered as {"address","symbol","decimals"} objects
+ */
+func (c V4RouterCall) ToJSON(registry TokenRegistry) ([]byte, error) {
+	w := &jsonWriter{registry: registry}
+	w.WriteString(`{"actions":[`)
+	for i, action := range c.Actions {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		action.writeJSON(w)
+	}
+	w.WriteString(`]}`)
+	return w.Bytes(), nil
+}
+
+func (c V4RouterCall) MarshalJSON() ([]byte, error) {
+	return c.ToJSON(nil)
+}
+
+// ToJSON renders the action like V4RouterCall.ToJSON
+func (a V4RouterAction) ToJSON(registry TokenRegistry) ([]byte, error) {
+	w := &jsonWriter{registry: registry}
+	a.writeJSON(w)
+	return w.Bytes(), nil
+}
+
+func (a V4RouterAction) MarshalJSON() ([]byte, error) {
+	return a.ToJSON(nil)
+}
+
+func (a V4RouterAction) writeJSON(w *jsonWriter) {
+	w.WriteString(`{"actionName":`)
+	w.writeString(a.ActionName)
+	w.WriteString(`,"actionType":`)
+	w.WriteString(strconv.Itoa(int(a.ActionType)))
+	w.WriteString(`,"params":{`)
+	for i, param := range a.Params {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.writeString(param.Name)
+		w.WriteByte(':')
+		w.writeValue(param.Name, reflect.ValueOf(param.Value))
+	}
+	w.WriteString(`}}`)
+}
+
+type jsonWriter struct {
+	bytes.Buffer
+	registry TokenRegistry
+}
+
+func (w *jsonWriter) writeString(s string) {
+	encoded, _ := json.Marshal(s)
+	w.Write(encoded)
+}
+
+var (
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(common.Address{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+)
+
+// writeValue writes the value of the param or tuple component with the given name
+func (w *jsonWriter) writeValue(name string, v reflect.Value) {
+	if !v.IsValid() {
+		w.WriteString("null")
+		return
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		if v.IsNil() {
+			w.WriteString("null")
+			return
+		}
+		w.writeString(v.Interface().(*big.Int).String())
+		return
+	case addressType:
+		w.writeAddress(name, v.Interface().(common.Address))
+		return
+	case bytesType:
+		w.writeString(hexutil.Encode(v.Bytes()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		w.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.writeString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		w.writeString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.String:
+		w.writeString(v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			w.WriteString("null")
+			return
+		}
+		w.writeValue(name, v.Elem())
+	case reflect.Slice, reflect.Array:
+		w.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.writeValue(name, v.Index(i))
+		}
+		w.WriteByte(']')
+	case reflect.Struct:
+		// tuples decoded by go-ethereum carry their abi component name in the json tag
+		w.WriteByte('{')
+		first := true
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := field.Name
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+				fieldName = tag
+			}
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			w.writeString(fieldName)
+			w.WriteByte(':')
+			w.writeValue(fieldName, v.Field(i))
+		}
+		w.WriteByte('}')
+	default:
+		encoded, err := json.Marshal(v.Interface())
+		if err != nil {
+			w.WriteString("null")
+			return
+		}
+		w.Write(encoded)
+	}
+}
+
+// writeAddress resolves the address through the registry only for currency params, so that e.g. a zero hooks
+// address is not shown as the native currency
+func (w *jsonWriter) writeAddress(name string, address common.Address) {
+	if w.registry == nil || !strings.Contains(strings.ToLower(name), "currency") {
+		w.writeString(address.Hex())
+		return
+	}
+	currency, ok := w.registry.Currency(address)
+	if !ok {
+		w.writeString(address.Hex())
+		return
+	}
+	w.WriteString(`{"address":`)
+	w.writeString(address.Hex())
+	w.WriteString(`,"symbol":`)
+	w.writeString(currency.Symbol())
+	w.WriteString(`,"decimals":`)
+	w.WriteString(strconv.FormatUint(uint64(currency.Decimals()), 10))
+	w.WriteByte('}')
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// jsonTestCall parses a swap with a path followed by a mint of a pool key, covering nested tuples and arrays
+func jsonTestCall(t *testing.T) *V4RouterCall {
+	t.Helper()
+	usdc := parserTestCurrency(testUSDC.Hex())
+	planner := NewV4Planner()
+	steps := []func() (*V4Planner, error){
+		func() (*V4Planner, error) {
+			return planner.AddSwapExactIn(usdc, parserTestPath[:1], big.NewInt(1000000), big.NewInt(990))
+		},
+		func() (*V4Planner, error) { return planner.AddSettleAll(usdc, big.NewInt(1000000)) },
+		func() (*V4Planner, error) {
+			return planner.AddTakeAll(parserTestCurrency(testToken1.Address.Hex()), big.NewInt(990))
+		},
+		func() (*V4Planner, error) {
+			return planner.AddMintPosition(parserTestKey, -600, 600, big.NewInt(5), big.NewInt(6), big.NewInt(7), parserTestOwner, []byte{0xbe, 0xef})
+		},
+	}
+	for _, step := range steps {
+		if _, err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unlockData, err := planner.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := ParseCalldata(unlockData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return call
+}
+
+// expectGoldenJSON compares the rendered JSON byte for byte, key order included, with the indented golden file
+func expectGoldenJSON(t *testing.T, name string, rendered []byte) {
+	t.Helper()
+	golden, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	if err := json.Compact(&want, golden); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rendered, want.Bytes()) {
+		t.Errorf("%s does not match\n%s", name, rendered)
+	}
+}
+
+func TestV4RouterCallJSON(t *testing.T) {
+	call := jsonTestCall(t)
+	rendered, err := call.ToJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectGoldenJSON(t, "v4RouterCall.golden.json", rendered)
+
+	marshaled, err := json.Marshal(call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(marshaled, rendered) {
+		t.Errorf("MarshalJSON differs from ToJSON\n%s", marshaled)
+	}
+
+	// an action renders as its element of the call
+	action, err := json.Marshal(call.Actions[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"actionName":"SETTLE_ALL","actionType":12,"params":{"currency":"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48","maxAmount":"1000000"}}`
+	if string(action) != want {
+		t.Errorf("action rendered as %s", action)
+	}
+}
+
+func TestV4RouterCallJSONWithRegistry(t *testing.T) {
+	registry := NewCurrencyMap(pmTestNative, testUSDCToken, testToken1)
+	rendered, err := jsonTestCall(t).ToJSON(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// currencies are resolved, the zero hooks address and the owner are not
+	expectGoldenJSON(t, "v4RouterCallRegistry.golden.json", rendered)
+}
+
+func TestCurrencyMap(t *testing.T) {
+	registry := NewCurrencyMap(pmTestNative, testUSDCToken)
+	if currency, ok := registry.Currency(testUSDC); !ok || !currency.Equal(testUSDCToken) {
+		t.Errorf("USDC resolved to %v, %t", currency, ok)
+	}
+	// the native currency is registered at the zero address
+	if currency, ok := registry.Currency(pmTestNative.Wrapped().Address); ok {
+		t.Errorf("WETH resolved to %s", currency.Symbol())
+	}
+	if currency, ok := registry.Currency(parserTestKey.Currency0); !ok || !currency.IsNative() {
+		t.Errorf("zero address resolved to %v, %t", currency, ok)
+	}
+	registry.Add(testToken1)
+	if _, ok := registry.Currency(testToken1.Address); !ok {
+		t.Error("added token not resolved")
+	}
+}
@@ -2,17 +2,24 @@ module github.com/dangthanhduong01/uniswapv4-sdk
 
 go 1.24.5
 
-require github.com/daoleno/uniswap-sdk-core v0.1.7
+require (
+	github.com/daoleno/uniswap-sdk-core v0.1.7
+	github.com/holiman/uint256 v1.3.2
+)
 
 require (
-	github.com/KyberNetwork/pancake-v3-sdk v0.2.2 // indirect
-	github.com/btcsuite/btcd/btcec/v2 v2.2.0 // indirect
+	github.com/bits-and-blooms/bitset v1.20.0 // indirect
+	github.com/consensys/gnark-crypto v0.18.0 // indirect
+	github.com/crate-crypto/go-eth-kzg v1.4.0 // indirect
+	github.com/crate-crypto/go-ipa v0.0.0-20240724233137-53bbb0ceb27a // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.0.1 // indirect
-	github.com/holiman/uint256 v1.3.2 // indirect
+	github.com/ethereum/c-kzg-4844/v2 v2.1.3 // indirect
+	github.com/ethereum/go-verkle v0.2.2 // indirect
+	github.com/supranational/blst v0.3.16-0.20250831170142-f48500c1fdbe // indirect
+	golang.org/x/sync v0.12.0 // indirect
 )
 
 require (
-	github.com/charliego3/uniswapv3-sdk v0.4.3
 	github.com/ethereum/go-ethereum v1.16.5
 	github.com/shopspring/decimal v1.3.1 // indirect
 	golang.org/x/crypto v0.36.0 // indirect
@@ -1 +0,0 @@
-package uniswapv4sdk
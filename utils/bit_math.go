@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/holiman/uint256"
+)
+
+var ErrInvalidInput = errors.New("invalid input")
+
+/**
+ * Returns the index of the most significant bit of the number, where the least significant bit is at index 0
+ * and the most significant bit is at index 255
+ * @param x the value for which to compute the most significant bit, must be greater than 0
+ */
+func MostSignificantBit(x *big.Int) (int64, error) {
+	var x256 uint256.Int
+	if x.Sign() <= 0 || x256.SetFromBig(x) {
+		return 0, ErrInvalidInput
+	}
+	return int64(MostSignificantBitU256(&x256)), nil
+}
+
+// MostSignificantBitU256 is MostSignificantBit on a uint256 value, x must be greater than 0
+func MostSignificantBitU256(x *uint256.Int) int {
+	return x.BitLen() - 1
+}
+
+/**
+ * Returns the index of the least significant bit of the number, where the least significant bit is at index 0
+ * and the most significant bit is at index 255
+ * @param x the value for which to compute the least significant bit, must be greater than 0
+ */
+func LeastSignificantBit(x *big.Int) (int64, error) {
+	var x256 uint256.Int
+	if x.Sign() <= 0 || x256.SetFromBig(x) {
+		return 0, ErrInvalidInput
+	}
+	return int64(LeastSignificantBitU256(&x256)), nil
+}
+
+// LeastSignificantBitU256 is LeastSignificantBit on a uint256 value, x must be greater than 0
+func LeastSignificantBitU256(x *uint256.Int) int {
+	for i, word := range x {
+		if word != 0 {
+			return i*64 + bits.TrailingZeros64(word)
+		}
+	}
+	return 256
+}
@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"github.com/KyberNetwork/pancake-v3-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	core "github.com/daoleno/uniswap-sdk-core/entities"
 	"github.com/ethereum/go-ethereum/common"
 )
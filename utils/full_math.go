@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+var (
+	ErrZeroDenominator = errors.New("denominator is zero")
+	ErrMulDivOverflow  = errors.New("result overflows uint256")
+)
+
+/**
+ * Calculates floor(a×b÷denominator) with full precision. Fails if result overflows a uint256 or denominator == 0
+ * @param a The multiplicand
+ * @param b The multiplier
+ * @param denominator The divisor
+ * @returns The 256-bit result
+ */
+func MulDiv(a, b, denominator *big.Int) (*big.Int, error) {
+	var a256, b256, denominator256, result uint256.Int
+	if err := fromBig(a, &a256); err != nil {
+		return nil, err
+	}
+	if err := fromBig(b, &b256); err != nil {
+		return nil, err
+	}
+	if err := fromBig(denominator, &denominator256); err != nil {
+		return nil, err
+	}
+	if err := MulDivU256(&a256, &b256, &denominator256, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// MulDivU256 is MulDiv on uint256 values, the result is written to result
+func MulDivU256(a, b, denominator, result *uint256.Int) error {
+	if denominator.IsZero() {
+		return ErrZeroDenominator
+	}
+	if _, overflow := result.MulDivOverflow(a, b, denominator); overflow {
+		return ErrMulDivOverflow
+	}
+	return nil
+}
+
+/**
+ * Calculates ceil(a×b÷denominator) with full precision. Fails if result overflows a uint256 or denominator == 0
+ * @param a The multiplicand
+ * @param b The multiplier
+ * @param denominator The divisor
+ * @returns The 256-bit result
+ */
+func MulDivRoundingUp(a, b, denominator *big.Int) (*big.Int, error) {
+	var a256, b256, denominator256, result uint256.Int
+	if err := fromBig(a, &a256); err != nil {
+		return nil, err
+	}
+	if err := fromBig(b, &b256); err != nil {
+		return nil, err
+	}
+	if err := fromBig(denominator, &denominator256); err != nil {
+		return nil, err
+	}
+	if err := MulDivRoundingUpU256(&a256, &b256, &denominator256, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// MulDivRoundingUpU256 is MulDivRoundingUp on uint256 values, the result is written to result
+// which must not alias the inputs
+func MulDivRoundingUpU256(a, b, denominator, result *uint256.Int) error {
+	if err := MulDivU256(a, b, denominator, result); err != nil {
+		return err
+	}
+	var remainder uint256.Int
+	if !remainder.MulMod(a, b, denominator).IsZero() {
+		if result.Eq(maxUint256) {
+			return ErrMulDivOverflow
+		}
+		result.AddUint64(result, 1)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+var (
+	testQ128       = new(big.Int).Lsh(big.NewInt(1), 128)
+	testMaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+// mustBig parses a decimal integer of the test vectors
+func mustBig(t *testing.T, s string) *big.Int {
+	t.Helper()
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid integer %q", s)
+	}
+	return x
+}
+
+// q128 returns numerator/denominator of Q128
+func q128(numerator, denominator int64) *big.Int {
+	x := new(big.Int).Mul(testQ128, big.NewInt(numerator))
+	return x.Div(x, big.NewInt(denominator))
+}
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		name             string
+		a, b, d          *big.Int
+		floor, roundedUp string
+	}{
+		{"all max inputs", testMaxUint256, testMaxUint256, testMaxUint256, testMaxUint256.String(), testMaxUint256.String()},
+		{"accurate without phantom overflow", testQ128, q128(50, 100), q128(150, 100),
+			"113427455640312821154458202477256070485", "113427455640312821154458202477256070486"},
+		{"accurate with phantom overflow", testQ128, q128(35, 1), q128(8, 1),
+			"1488735355279105777652263907513985925120", "1488735355279105777652263907513985925120"},
+		{"accurate with phantom overflow and repeating decimal", testQ128, q128(1000, 1), q128(3000, 1),
+			"113427455640312821154458202477256070485", "113427455640312821154458202477256070486"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor, err := MulDiv(tt.a, tt.b, tt.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if floor.String() != tt.floor {
+				t.Errorf("mulDiv %s, want %s", floor, tt.floor)
+			}
+			roundedUp, err := MulDivRoundingUp(tt.a, tt.b, tt.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roundedUp.String() != tt.roundedUp {
+				t.Errorf("mulDivRoundingUp %s, want %s", roundedUp, tt.roundedUp)
+			}
+		})
+	}
+}
+
+func TestMulDivErrors(t *testing.T) {
+	if _, err := MulDiv(testQ128, big.NewInt(5), big.NewInt(0)); !errors.Is(err, ErrZeroDenominator) {
+		t.Errorf("zero denominator returned %v", err)
+	}
+	if _, err := MulDivRoundingUp(testQ128, big.NewInt(5), big.NewInt(0)); !errors.Is(err, ErrZeroDenominator) {
+		t.Errorf("zero denominator rounding up returned %v", err)
+	}
+	if _, err := MulDiv(testQ128, testQ128, big.NewInt(1)); !errors.Is(err, ErrMulDivOverflow) {
+		t.Errorf("overflowing product returned %v", err)
+	}
+	if _, err := MulDiv(testMaxUint256, testMaxUint256, new(big.Int).Sub(testMaxUint256, big.NewInt(1))); !errors.Is(err, ErrMulDivOverflow) {
+		t.Errorf("overflow with all max inputs returned %v", err)
+	}
+}
+
+func TestMulDivRoundingUpOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, d string
+	}{
+		{"overflow after rounding up", "535006138814359", "432862656469423142931042426214547535783388063929571229938474969", "2"},
+		{"overflow after rounding up case 2",
+			"115792089237316195423570985008687907853269984659341747863450311749907997002549",
+			"115792089237316195423570985008687907853269984659341747863450311749907997002550",
+			"115792089237316195423570985008687907853269984653042931687443039491902864365164"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b, d := mustBig(t, tt.a), mustBig(t, tt.b), mustBig(t, tt.d)
+			// the floor fits in 256 bits, rounding it up does not
+			floor, err := MulDiv(a, b, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if floor.Cmp(testMaxUint256) != 0 {
+				t.Errorf("mulDiv %s, want max uint256", floor)
+			}
+			if _, err := MulDivRoundingUp(a, b, d); !errors.Is(err, ErrMulDivOverflow) {
+				t.Errorf("mulDivRoundingUp returned %v", err)
+			}
+		})
+	}
+}
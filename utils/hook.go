@@ -4,6 +4,8 @@ import (
 	"errors"
 	"math/big"
 	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
@@ -175,6 +177,17 @@ func (h *Hook) HasDonatePermissions(address string) (bool, error) {
 	return perm1 || perm2, nil
 }
 
+// HasHookAddressPermission is HasPermission for an address that is already parsed, it reads the flag from the
+// lowest 14 bits of the address without formatting it, e.g. on every swap of a pool
+func HasHookAddressPermission(addr common.Address, hookOption HookOption) bool {
+	index, ok := hookFlagIndex[hookOption]
+	if !ok {
+		return false
+	}
+	flags := uint16(addr[common.AddressLength-2])<<8 | uint16(addr[common.AddressLength-1])
+	return flags&(1<<index) != 0
+}
+
 func _checkAddress(addr string) error {
 	if !isAddressValid(addr) {
 		return errors.New(ErrInvalidAddress)
@@ -182,9 +195,10 @@ func _checkAddress(addr string) error {
 	return nil
 }
 
+var addressRegexp = regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
+
 func isAddressValid(addr string) bool {
-	re := regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
-	if !re.Match([]byte(addr)) {
+	if !addressRegexp.MatchString(addr) {
 		return false
 	}
 	return len(addr) > 2 && addr[:2] == "0x"
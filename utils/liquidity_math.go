@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+var ErrLiquidityOverflow = errors.New("liquidity overflows uint128")
+
+var MaxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+/**
+ * Add a signed liquidity delta to liquidity and revert if it overflows or underflows
+ * @param x The liquidity before change
+ * @param y The delta by which liquidity should be changed
+ * @returns The liquidity delta
+ */
+func AddDelta(x, y *big.Int) (*big.Int, error) {
+	var x256, y256, result uint256.Int
+	if err := fromBig(x, &x256); err != nil {
+		return nil, ErrLiquidityOverflow
+	}
+	if err := fromBigSigned(y, &y256); err != nil {
+		return nil, ErrLiquidityOverflow
+	}
+	if err := AddDeltaU256(&x256, &y256, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// AddDeltaU256 is AddDelta on uint256 values, y is a two's complement signed delta
+func AddDeltaU256(x, y, result *uint256.Int) error {
+	if x.Gt(maxUint128) {
+		return ErrLiquidityOverflow
+	}
+	if y.Sign() < 0 {
+		var abs uint256.Int
+		abs.Neg(y)
+		if x.Lt(&abs) {
+			return ErrLiquidityOverflow
+		}
+		result.Sub(x, &abs)
+		return nil
+	}
+	if _, overflow := result.AddOverflow(x, y); overflow || result.Gt(maxUint128) {
+		return ErrLiquidityOverflow
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	ErrNoCalls          = errors.New("no calls to encode")
+	ErrCalldataTooShort = errors.New("calldata is shorter than a selector")
+	ErrInvalidMulticall = errors.New("invalid multicall calldata")
+)
+
+// multicallMethod is multicall(bytes[] data), its selector is 0xac9650d8
+var multicallMethod = func() abi.Method {
+	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+	return abi.NewMethod("multicall", "multicall", abi.Function, "", false, false, abi.Arguments{{Name: "data", Type: bytesArrayType}}, nil)
+}()
+
+/**
+ * Encodes the calls into a single multicall(bytes[]) call, as supported by the PositionManager and the
+ * V4Router. A single call does not need to be batched and is returned as is.
+ * @param calldatas The calls to batch
+ * @returns The calldata
+ */
+func EncodeMulticall(calldatas [][]byte) ([]byte, error) {
+	if len(calldatas) == 0 {
+		return nil, ErrNoCalls
+	}
+	if len(calldatas) == 1 {
+		return calldatas[0], nil
+	}
+	encoded, err := multicallMethod.Inputs.Pack(calldatas)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, multicallMethod.ID...), encoded...), nil
+}
+
+/**
+ * Decodes a multicall(bytes[]) call into the calls it batches. Any other call is returned as the only call,
+ * mirroring EncodeMulticall which does not batch a single call.
+ * @param calldata The calldata to decode
+ * @returns The batched calls
+ */
+func DecodeMulticall(calldata []byte) ([][]byte, error) {
+	if len(calldata) < len(multicallMethod.ID) {
+		return nil, ErrCalldataTooShort
+	}
+	if !IsMulticall(calldata) {
+		return [][]byte{calldata}, nil
+	}
+	decoded, err := multicallMethod.Inputs.Unpack(calldata[len(multicallMethod.ID):])
+	if err != nil {
+		return nil, err
+	}
+	calls, ok := decoded[0].([][]byte)
+	if !ok {
+		return nil, ErrInvalidMulticall
+	}
+	return calls, nil
+}
+
+// IsMulticall returns true if the calldata is a call to multicall(bytes[])
+func IsMulticall(calldata []byte) bool {
+	return len(calldata) >= len(multicallMethod.ID) && bytes.Equal(calldata[:len(multicallMethod.ID)], multicallMethod.ID)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeMulticall(t *testing.T) {
+	calls := [][]byte{{0x12, 0x34, 0x56, 0x78}, {0xab, 0xcd, 0xef}}
+	calldata, err := EncodeMulticall(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ac9650d8" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"1234567800000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"abcdef0000000000000000000000000000000000000000000000000000000000"
+	if hex.EncodeToString(calldata) != want {
+		t.Errorf("calldata %x", calldata)
+	}
+	if !IsMulticall(calldata) {
+		t.Error("multicall not recognized")
+	}
+
+	decoded, err := DecodeMulticall(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, calls) {
+		t.Errorf("decoded %x", decoded)
+	}
+}
+
+func TestMulticallSingleCall(t *testing.T) {
+	call := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	calldata, err := EncodeMulticall([][]byte{call})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(calldata, call) || IsMulticall(calldata) {
+		t.Errorf("single call encoded as %x", calldata)
+	}
+	decoded, err := DecodeMulticall(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || !bytes.Equal(decoded[0], call) {
+		t.Errorf("single call decoded as %x", decoded)
+	}
+}
+
+func TestMulticallErrors(t *testing.T) {
+	if _, err := EncodeMulticall(nil); !errors.Is(err, ErrNoCalls) {
+		t.Errorf("no calls returned %v", err)
+	}
+	if _, err := DecodeMulticall([]byte{0xac, 0x96, 0x50}); !errors.Is(err, ErrCalldataTooShort) {
+		t.Errorf("short calldata returned %v", err)
+	}
+	calldata, err := EncodeMulticall([][]byte{{1}, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeMulticall(calldata[:len(calldata)-32]); err == nil {
+		t.Error("truncated multicall decoded")
+	}
+}
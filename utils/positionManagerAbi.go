@@ -1,1316 +1,1316 @@
 package utils
 
-// TODO: import this from npm
-const positionManagerAbi = `[
+// PositionManagerABI is the JSON ABI of the v4-periphery PositionManager
+const PositionManagerABI = `[
   {
-    type: 'constructor',
-    inputs: [
+    "type": "constructor",
+    "inputs": [
       {
-        name: '_poolManager',
-        type: 'address',
-        internalType: 'contract IPoolManager',
+        "name": "_poolManager",
+        "type": "address",
+        "internalType": "contract IPoolManager"
       },
       {
-        name: '_permit2',
-        type: 'address',
-        internalType: 'contract IAllowanceTransfer',
+        "name": "_permit2",
+        "type": "address",
+        "internalType": "contract IAllowanceTransfer"
       },
       {
-        name: '_unsubscribeGasLimit',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "_unsubscribeGasLimit",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: '_tokenDescriptor',
-        type: 'address',
-        internalType: 'contract IPositionDescriptor',
+        "name": "_tokenDescriptor",
+        "type": "address",
+        "internalType": "contract IPositionDescriptor"
       },
       {
-        name: '_weth9',
-        type: 'address',
-        internalType: 'contract IWETH9',
-      },
+        "name": "_weth9",
+        "type": "address",
+        "internalType": "contract IWETH9"
+      }
     ],
-    stateMutability: 'nonpayable',
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'receive',
-    stateMutability: 'payable',
+    "type": "receive",
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'DOMAIN_SEPARATOR',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "DOMAIN_SEPARATOR",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'bytes32',
-        internalType: 'bytes32',
-      },
+        "name": "",
+        "type": "bytes32",
+        "internalType": "bytes32"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'WETH9',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "WETH9",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'contract IWETH9',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "contract IWETH9"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'approve',
-    inputs: [
+    "type": "function",
+    "name": "approve",
+    "inputs": [
       {
-        name: 'spender',
-        type: 'address',
-        internalType: 'address',
+        "name": "spender",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'nonpayable',
+    "outputs": [],
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'balanceOf',
-    inputs: [
+    "type": "function",
+    "name": "balanceOf",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
-      },
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'getApproved',
-    inputs: [
+    "type": "function",
+    "name": "getApproved",
+    "inputs": [
       {
-        name: '',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'address',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "address"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'getPoolAndPositionInfo',
-    inputs: [
+    "type": "function",
+    "name": "getPoolAndPositionInfo",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'poolKey',
-        type: 'tuple',
-        internalType: 'struct PoolKey',
-        components: [
+        "name": "poolKey",
+        "type": "tuple",
+        "internalType": "struct PoolKey",
+        "components": [
           {
-            name: 'currency0',
-            type: 'address',
-            internalType: 'Currency',
+            "name": "currency0",
+            "type": "address",
+            "internalType": "Currency"
           },
           {
-            name: 'currency1',
-            type: 'address',
-            internalType: 'Currency',
+            "name": "currency1",
+            "type": "address",
+            "internalType": "Currency"
           },
           {
-            name: 'fee',
-            type: 'uint24',
-            internalType: 'uint24',
+            "name": "fee",
+            "type": "uint24",
+            "internalType": "uint24"
           },
           {
-            name: 'tickSpacing',
-            type: 'int24',
-            internalType: 'int24',
+            "name": "tickSpacing",
+            "type": "int24",
+            "internalType": "int24"
           },
           {
-            name: 'hooks',
-            type: 'address',
-            internalType: 'contract IHooks',
-          },
-        ],
+            "name": "hooks",
+            "type": "address",
+            "internalType": "contract IHooks"
+          }
+        ]
       },
       {
-        name: 'info',
-        type: 'uint256',
-        internalType: 'PositionInfo',
-      },
+        "name": "info",
+        "type": "uint256",
+        "internalType": "PositionInfo"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'getPositionLiquidity',
-    inputs: [
+    "type": "function",
+    "name": "getPositionLiquidity",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'liquidity',
-        type: 'uint128',
-        internalType: 'uint128',
-      },
+        "name": "liquidity",
+        "type": "uint128",
+        "internalType": "uint128"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'initializePool',
-    inputs: [
+    "type": "function",
+    "name": "initializePool",
+    "inputs": [
       {
-        name: 'key',
-        type: 'tuple',
-        internalType: 'struct PoolKey',
-        components: [
+        "name": "key",
+        "type": "tuple",
+        "internalType": "struct PoolKey",
+        "components": [
           {
-            name: 'currency0',
-            type: 'address',
-            internalType: 'Currency',
+            "name": "currency0",
+            "type": "address",
+            "internalType": "Currency"
           },
           {
-            name: 'currency1',
-            type: 'address',
-            internalType: 'Currency',
+            "name": "currency1",
+            "type": "address",
+            "internalType": "Currency"
           },
           {
-            name: 'fee',
-            type: 'uint24',
-            internalType: 'uint24',
+            "name": "fee",
+            "type": "uint24",
+            "internalType": "uint24"
           },
           {
-            name: 'tickSpacing',
-            type: 'int24',
-            internalType: 'int24',
+            "name": "tickSpacing",
+            "type": "int24",
+            "internalType": "int24"
           },
           {
-            name: 'hooks',
-            type: 'address',
-            internalType: 'contract IHooks',
-          },
-        ],
+            "name": "hooks",
+            "type": "address",
+            "internalType": "contract IHooks"
+          }
+        ]
       },
       {
-        name: 'sqrtPriceX96',
-        type: 'uint160',
-        internalType: 'uint160',
-      },
+        "name": "sqrtPriceX96",
+        "type": "uint160",
+        "internalType": "uint160"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'int24',
-        internalType: 'int24',
-      },
+        "name": "",
+        "type": "int24",
+        "internalType": "int24"
+      }
     ],
-    stateMutability: 'payable',
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'isApprovedForAll',
-    inputs: [
+    "type": "function",
+    "name": "isApprovedForAll",
+    "inputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'address',
+        "name": "",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: '',
-        type: 'address',
-        internalType: 'address',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "address"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'bool',
-        internalType: 'bool',
-      },
+        "name": "",
+        "type": "bool",
+        "internalType": "bool"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'modifyLiquidities',
-    inputs: [
+    "type": "function",
+    "name": "modifyLiquidities",
+    "inputs": [
       {
-        name: 'unlockData',
-        type: 'bytes',
-        internalType: 'bytes',
+        "name": "unlockData",
+        "type": "bytes",
+        "internalType": "bytes"
       },
       {
-        name: 'deadline',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "deadline",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'modifyLiquiditiesWithoutUnlock',
-    inputs: [
+    "type": "function",
+    "name": "modifyLiquiditiesWithoutUnlock",
+    "inputs": [
       {
-        name: 'actions',
-        type: 'bytes',
-        internalType: 'bytes',
+        "name": "actions",
+        "type": "bytes",
+        "internalType": "bytes"
       },
       {
-        name: 'params',
-        type: 'bytes[]',
-        internalType: 'bytes[]',
-      },
+        "name": "params",
+        "type": "bytes[]",
+        "internalType": "bytes[]"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'msgSender',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "msgSender",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'address',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "address"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'multicall',
-    inputs: [
+    "type": "function",
+    "name": "multicall",
+    "inputs": [
       {
-        name: 'data',
-        type: 'bytes[]',
-        internalType: 'bytes[]',
-      },
+        "name": "data",
+        "type": "bytes[]",
+        "internalType": "bytes[]"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'results',
-        type: 'bytes[]',
-        internalType: 'bytes[]',
-      },
+        "name": "results",
+        "type": "bytes[]",
+        "internalType": "bytes[]"
+      }
     ],
-    stateMutability: 'payable',
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'name',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "name",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'string',
-        internalType: 'string',
-      },
+        "name": "",
+        "type": "string",
+        "internalType": "string"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'nextTokenId',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "nextTokenId",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'nonces',
-    inputs: [
+    "type": "function",
+    "name": "nonces",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'word',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "word",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'bitmap',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "bitmap",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'ownerOf',
-    inputs: [
+    "type": "function",
+    "name": "ownerOf",
+    "inputs": [
       {
-        name: 'id',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
-      },
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'permit',
-    inputs: [
+    "type": "function",
+    "name": "permit",
+    "inputs": [
       {
-        name: 'spender',
-        type: 'address',
-        internalType: 'address',
+        "name": "spender",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'deadline',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "deadline",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'nonce',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "nonce",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'signature',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "signature",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'permit',
-    inputs: [
+    "type": "function",
+    "name": "permit",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'permitSingle',
-        type: 'tuple',
-        internalType: 'struct IAllowanceTransfer.PermitSingle',
-        components: [
+        "name": "permitSingle",
+        "type": "tuple",
+        "internalType": "struct IAllowanceTransfer.PermitSingle",
+        "components": [
           {
-            name: 'details',
-            type: 'tuple',
-            internalType: 'struct IAllowanceTransfer.PermitDetails',
-            components: [
+            "name": "details",
+            "type": "tuple",
+            "internalType": "struct IAllowanceTransfer.PermitDetails",
+            "components": [
               {
-                name: 'token',
-                type: 'address',
-                internalType: 'address',
+                "name": "token",
+                "type": "address",
+                "internalType": "address"
               },
               {
-                name: 'amount',
-                type: 'uint160',
-                internalType: 'uint160',
+                "name": "amount",
+                "type": "uint160",
+                "internalType": "uint160"
               },
               {
-                name: 'expiration',
-                type: 'uint48',
-                internalType: 'uint48',
+                "name": "expiration",
+                "type": "uint48",
+                "internalType": "uint48"
               },
               {
-                name: 'nonce',
-                type: 'uint48',
-                internalType: 'uint48',
-              },
-            ],
+                "name": "nonce",
+                "type": "uint48",
+                "internalType": "uint48"
+              }
+            ]
           },
           {
-            name: 'spender',
-            type: 'address',
-            internalType: 'address',
+            "name": "spender",
+            "type": "address",
+            "internalType": "address"
           },
           {
-            name: 'sigDeadline',
-            type: 'uint256',
-            internalType: 'uint256',
-          },
-        ],
+            "name": "sigDeadline",
+            "type": "uint256",
+            "internalType": "uint256"
+          }
+        ]
       },
       {
-        name: 'signature',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "signature",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'err',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "err",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    stateMutability: 'payable',
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'permit2',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "permit2",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'contract IAllowanceTransfer',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "contract IAllowanceTransfer"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'permitBatch',
-    inputs: [
+    "type": "function",
+    "name": "permitBatch",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: '_permitBatch',
-        type: 'tuple',
-        internalType: 'struct IAllowanceTransfer.PermitBatch',
-        components: [
+        "name": "_permitBatch",
+        "type": "tuple",
+        "internalType": "struct IAllowanceTransfer.PermitBatch",
+        "components": [
           {
-            name: 'details',
-            type: 'tuple[]',
-            internalType: 'struct IAllowanceTransfer.PermitDetails[]',
-            components: [
+            "name": "details",
+            "type": "tuple[]",
+            "internalType": "struct IAllowanceTransfer.PermitDetails[]",
+            "components": [
               {
-                name: 'token',
-                type: 'address',
-                internalType: 'address',
+                "name": "token",
+                "type": "address",
+                "internalType": "address"
               },
               {
-                name: 'amount',
-                type: 'uint160',
-                internalType: 'uint160',
+                "name": "amount",
+                "type": "uint160",
+                "internalType": "uint160"
               },
               {
-                name: 'expiration',
-                type: 'uint48',
-                internalType: 'uint48',
+                "name": "expiration",
+                "type": "uint48",
+                "internalType": "uint48"
               },
               {
-                name: 'nonce',
-                type: 'uint48',
-                internalType: 'uint48',
-              },
-            ],
+                "name": "nonce",
+                "type": "uint48",
+                "internalType": "uint48"
+              }
+            ]
           },
           {
-            name: 'spender',
-            type: 'address',
-            internalType: 'address',
+            "name": "spender",
+            "type": "address",
+            "internalType": "address"
           },
           {
-            name: 'sigDeadline',
-            type: 'uint256',
-            internalType: 'uint256',
-          },
-        ],
+            "name": "sigDeadline",
+            "type": "uint256",
+            "internalType": "uint256"
+          }
+        ]
       },
       {
-        name: 'signature',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "signature",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'err',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "err",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    stateMutability: 'payable',
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'permitForAll',
-    inputs: [
+    "type": "function",
+    "name": "permitForAll",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'operator',
-        type: 'address',
-        internalType: 'address',
+        "name": "operator",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'approved',
-        type: 'bool',
-        internalType: 'bool',
+        "name": "approved",
+        "type": "bool",
+        "internalType": "bool"
       },
       {
-        name: 'deadline',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "deadline",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'nonce',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "nonce",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'signature',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "signature",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'poolKeys',
-    inputs: [
+    "type": "function",
+    "name": "poolKeys",
+    "inputs": [
       {
-        name: 'poolId',
-        type: 'bytes25',
-        internalType: 'bytes25',
-      },
+        "name": "poolId",
+        "type": "bytes25",
+        "internalType": "bytes25"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'currency0',
-        type: 'address',
-        internalType: 'Currency',
+        "name": "currency0",
+        "type": "address",
+        "internalType": "Currency"
       },
       {
-        name: 'currency1',
-        type: 'address',
-        internalType: 'Currency',
+        "name": "currency1",
+        "type": "address",
+        "internalType": "Currency"
       },
       {
-        name: 'fee',
-        type: 'uint24',
-        internalType: 'uint24',
+        "name": "fee",
+        "type": "uint24",
+        "internalType": "uint24"
       },
       {
-        name: 'tickSpacing',
-        type: 'int24',
-        internalType: 'int24',
+        "name": "tickSpacing",
+        "type": "int24",
+        "internalType": "int24"
       },
       {
-        name: 'hooks',
-        type: 'address',
-        internalType: 'contract IHooks',
-      },
+        "name": "hooks",
+        "type": "address",
+        "internalType": "contract IHooks"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'poolManager',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "poolManager",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'contract IPoolManager',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "contract IPoolManager"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'positionInfo',
-    inputs: [
+    "type": "function",
+    "name": "positionInfo",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'info',
-        type: 'uint256',
-        internalType: 'PositionInfo',
-      },
+        "name": "info",
+        "type": "uint256",
+        "internalType": "PositionInfo"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'revokeNonce',
-    inputs: [
+    "type": "function",
+    "name": "revokeNonce",
+    "inputs": [
       {
-        name: 'nonce',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "nonce",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'safeTransferFrom',
-    inputs: [
+    "type": "function",
+    "name": "safeTransferFrom",
+    "inputs": [
       {
-        name: 'from',
-        type: 'address',
-        internalType: 'address',
+        "name": "from",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'to',
-        type: 'address',
-        internalType: 'address',
+        "name": "to",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'nonpayable',
+    "outputs": [],
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'safeTransferFrom',
-    inputs: [
+    "type": "function",
+    "name": "safeTransferFrom",
+    "inputs": [
       {
-        name: 'from',
-        type: 'address',
-        internalType: 'address',
+        "name": "from",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'to',
-        type: 'address',
-        internalType: 'address',
+        "name": "to",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "id",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'data',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "data",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [],
-    stateMutability: 'nonpayable',
+    "outputs": [],
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'setApprovalForAll',
-    inputs: [
+    "type": "function",
+    "name": "setApprovalForAll",
+    "inputs": [
       {
-        name: 'operator',
-        type: 'address',
-        internalType: 'address',
+        "name": "operator",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'approved',
-        type: 'bool',
-        internalType: 'bool',
-      },
+        "name": "approved",
+        "type": "bool",
+        "internalType": "bool"
+      }
     ],
-    outputs: [],
-    stateMutability: 'nonpayable',
+    "outputs": [],
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'subscribe',
-    inputs: [
+    "type": "function",
+    "name": "subscribe",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'newSubscriber',
-        type: 'address',
-        internalType: 'address',
+        "name": "newSubscriber",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'data',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "data",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'subscriber',
-    inputs: [
+    "type": "function",
+    "name": "subscriber",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'contract ISubscriber',
-      },
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "contract ISubscriber"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'supportsInterface',
-    inputs: [
+    "type": "function",
+    "name": "supportsInterface",
+    "inputs": [
       {
-        name: 'interfaceId',
-        type: 'bytes4',
-        internalType: 'bytes4',
-      },
+        "name": "interfaceId",
+        "type": "bytes4",
+        "internalType": "bytes4"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'bool',
-        internalType: 'bool',
-      },
+        "name": "",
+        "type": "bool",
+        "internalType": "bool"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'symbol',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "symbol",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'string',
-        internalType: 'string',
-      },
+        "name": "",
+        "type": "string",
+        "internalType": "string"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'tokenDescriptor',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "tokenDescriptor",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'address',
-        internalType: 'contract IPositionDescriptor',
-      },
+        "name": "",
+        "type": "address",
+        "internalType": "contract IPositionDescriptor"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'tokenURI',
-    inputs: [
+    "type": "function",
+    "name": "tokenURI",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'string',
-        internalType: 'string',
-      },
+        "name": "",
+        "type": "string",
+        "internalType": "string"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'function',
-    name: 'transferFrom',
-    inputs: [
+    "type": "function",
+    "name": "transferFrom",
+    "inputs": [
       {
-        name: 'from',
-        type: 'address',
-        internalType: 'address',
+        "name": "from",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'to',
-        type: 'address',
-        internalType: 'address',
+        "name": "to",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'nonpayable',
+    "outputs": [],
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'unlockCallback',
-    inputs: [
+    "type": "function",
+    "name": "unlockCallback",
+    "inputs": [
       {
-        name: 'data',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "data",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    outputs: [
+    "outputs": [
       {
-        name: '',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
+        "name": "",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
     ],
-    stateMutability: 'nonpayable',
+    "stateMutability": "nonpayable"
   },
   {
-    type: 'function',
-    name: 'unsubscribe',
-    inputs: [
+    "type": "function",
+    "name": "unsubscribe",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    outputs: [],
-    stateMutability: 'payable',
+    "outputs": [],
+    "stateMutability": "payable"
   },
   {
-    type: 'function',
-    name: 'unsubscribeGasLimit',
-    inputs: [],
-    outputs: [
+    "type": "function",
+    "name": "unsubscribeGasLimit",
+    "inputs": [],
+    "outputs": [
       {
-        name: '',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
+        "name": "",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
     ],
-    stateMutability: 'view',
+    "stateMutability": "view"
   },
   {
-    type: 'event',
-    name: 'Approval',
-    inputs: [
+    "type": "event",
+    "name": "Approval",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'spender',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "spender",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        indexed: true,
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "indexed": true,
+        "internalType": "uint256"
+      }
     ],
-    anonymous: false,
+    "anonymous": false
   },
   {
-    type: 'event',
-    name: 'ApprovalForAll',
-    inputs: [
+    "type": "event",
+    "name": "ApprovalForAll",
+    "inputs": [
       {
-        name: 'owner',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "owner",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'operator',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "operator",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'approved',
-        type: 'bool',
-        indexed: false,
-        internalType: 'bool',
-      },
+        "name": "approved",
+        "type": "bool",
+        "indexed": false,
+        "internalType": "bool"
+      }
     ],
-    anonymous: false,
+    "anonymous": false
   },
   {
-    type: 'event',
-    name: 'Subscription',
-    inputs: [
+    "type": "event",
+    "name": "Subscription",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        indexed: true,
-        internalType: 'uint256',
+        "name": "tokenId",
+        "type": "uint256",
+        "indexed": true,
+        "internalType": "uint256"
       },
       {
-        name: 'subscriber',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
-      },
+        "name": "subscriber",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
+      }
     ],
-    anonymous: false,
+    "anonymous": false
   },
   {
-    type: 'event',
-    name: 'Transfer',
-    inputs: [
+    "type": "event",
+    "name": "Transfer",
+    "inputs": [
       {
-        name: 'from',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "from",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'to',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
+        "name": "to",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
       },
       {
-        name: 'id',
-        type: 'uint256',
-        indexed: true,
-        internalType: 'uint256',
-      },
+        "name": "id",
+        "type": "uint256",
+        "indexed": true,
+        "internalType": "uint256"
+      }
     ],
-    anonymous: false,
+    "anonymous": false
   },
   {
-    type: 'event',
-    name: 'Unsubscription',
-    inputs: [
+    "type": "event",
+    "name": "Unsubscription",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        indexed: true,
-        internalType: 'uint256',
+        "name": "tokenId",
+        "type": "uint256",
+        "indexed": true,
+        "internalType": "uint256"
       },
       {
-        name: 'subscriber',
-        type: 'address',
-        indexed: true,
-        internalType: 'address',
-      },
+        "name": "subscriber",
+        "type": "address",
+        "indexed": true,
+        "internalType": "address"
+      }
     ],
-    anonymous: false,
+    "anonymous": false
   },
   {
-    type: 'error',
-    name: 'AlreadySubscribed',
-    inputs: [
+    "type": "error",
+    "name": "AlreadySubscribed",
+    "inputs": [
       {
-        name: 'tokenId',
-        type: 'uint256',
-        internalType: 'uint256',
+        "name": "tokenId",
+        "type": "uint256",
+        "internalType": "uint256"
       },
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'address',
-      },
-    ],
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "address"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'BurnNotificationReverted',
-    inputs: [
+    "type": "error",
+    "name": "BurnNotificationReverted",
+    "inputs": [
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'address',
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'reason',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
-    ],
+        "name": "reason",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'ContractLocked',
-    inputs: [],
+    "type": "error",
+    "name": "ContractLocked",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'DeadlinePassed',
-    inputs: [
+    "type": "error",
+    "name": "DeadlinePassed",
+    "inputs": [
       {
-        name: 'deadline',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
-    ],
+        "name": "deadline",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'DeltaNotNegative',
-    inputs: [
+    "type": "error",
+    "name": "DeltaNotNegative",
+    "inputs": [
       {
-        name: 'currency',
-        type: 'address',
-        internalType: 'Currency',
-      },
-    ],
+        "name": "currency",
+        "type": "address",
+        "internalType": "Currency"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'DeltaNotPositive',
-    inputs: [
+    "type": "error",
+    "name": "DeltaNotPositive",
+    "inputs": [
       {
-        name: 'currency',
-        type: 'address',
-        internalType: 'Currency',
-      },
-    ],
+        "name": "currency",
+        "type": "address",
+        "internalType": "Currency"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'GasLimitTooLow',
-    inputs: [],
+    "type": "error",
+    "name": "GasLimitTooLow",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InputLengthMismatch',
-    inputs: [],
+    "type": "error",
+    "name": "InputLengthMismatch",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InsufficientBalance',
-    inputs: [],
+    "type": "error",
+    "name": "InsufficientBalance",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InvalidContractSignature',
-    inputs: [],
+    "type": "error",
+    "name": "InvalidContractSignature",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InvalidEthSender',
-    inputs: [],
+    "type": "error",
+    "name": "InvalidEthSender",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InvalidSignature',
-    inputs: [],
+    "type": "error",
+    "name": "InvalidSignature",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InvalidSignatureLength',
-    inputs: [],
+    "type": "error",
+    "name": "InvalidSignatureLength",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'InvalidSigner',
-    inputs: [],
+    "type": "error",
+    "name": "InvalidSigner",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'MaximumAmountExceeded',
-    inputs: [
+    "type": "error",
+    "name": "MaximumAmountExceeded",
+    "inputs": [
       {
-        name: 'maximumAmount',
-        type: 'uint128',
-        internalType: 'uint128',
+        "name": "maximumAmount",
+        "type": "uint128",
+        "internalType": "uint128"
       },
       {
-        name: 'amountRequested',
-        type: 'uint128',
-        internalType: 'uint128',
-      },
-    ],
+        "name": "amountRequested",
+        "type": "uint128",
+        "internalType": "uint128"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'MinimumAmountInsufficient',
-    inputs: [
+    "type": "error",
+    "name": "MinimumAmountInsufficient",
+    "inputs": [
       {
-        name: 'minimumAmount',
-        type: 'uint128',
-        internalType: 'uint128',
+        "name": "minimumAmount",
+        "type": "uint128",
+        "internalType": "uint128"
       },
       {
-        name: 'amountReceived',
-        type: 'uint128',
-        internalType: 'uint128',
-      },
-    ],
+        "name": "amountReceived",
+        "type": "uint128",
+        "internalType": "uint128"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'ModifyLiquidityNotificationReverted',
-    inputs: [
+    "type": "error",
+    "name": "ModifyLiquidityNotificationReverted",
+    "inputs": [
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'address',
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'reason',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
-    ],
+        "name": "reason",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'NoCodeSubscriber',
-    inputs: [],
+    "type": "error",
+    "name": "NoCodeSubscriber",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'NoSelfPermit',
-    inputs: [],
+    "type": "error",
+    "name": "NoSelfPermit",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'NonceAlreadyUsed',
-    inputs: [],
+    "type": "error",
+    "name": "NonceAlreadyUsed",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'NotApproved',
-    inputs: [
+    "type": "error",
+    "name": "NotApproved",
+    "inputs": [
       {
-        name: 'caller',
-        type: 'address',
-        internalType: 'address',
-      },
-    ],
+        "name": "caller",
+        "type": "address",
+        "internalType": "address"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'NotPoolManager',
-    inputs: [],
+    "type": "error",
+    "name": "NotPoolManager",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'NotSubscribed',
-    inputs: [],
+    "type": "error",
+    "name": "NotSubscribed",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'PoolManagerMustBeLocked',
-    inputs: [],
+    "type": "error",
+    "name": "PoolManagerMustBeLocked",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'SignatureDeadlineExpired',
-    inputs: [],
+    "type": "error",
+    "name": "SignatureDeadlineExpired",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'SubscriptionReverted',
-    inputs: [
+    "type": "error",
+    "name": "SubscriptionReverted",
+    "inputs": [
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'address',
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'reason',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
-    ],
+        "name": "reason",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'TransferNotificationReverted',
-    inputs: [
+    "type": "error",
+    "name": "TransferNotificationReverted",
+    "inputs": [
       {
-        name: 'subscriber',
-        type: 'address',
-        internalType: 'address',
+        "name": "subscriber",
+        "type": "address",
+        "internalType": "address"
       },
       {
-        name: 'reason',
-        type: 'bytes',
-        internalType: 'bytes',
-      },
-    ],
+        "name": "reason",
+        "type": "bytes",
+        "internalType": "bytes"
+      }
+    ]
   },
   {
-    type: 'error',
-    name: 'Unauthorized',
-    inputs: [],
+    "type": "error",
+    "name": "Unauthorized",
+    "inputs": []
   },
   {
-    type: 'error',
-    name: 'UnsupportedAction',
-    inputs: [
+    "type": "error",
+    "name": "UnsupportedAction",
+    "inputs": [
       {
-        name: 'action',
-        type: 'uint256',
-        internalType: 'uint256',
-      },
-    ],
-  },
+        "name": "action",
+        "type": "uint256",
+        "internalType": "uint256"
+      }
+    ]
+  }
 ]`
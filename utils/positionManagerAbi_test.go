@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestPositionManagerABI(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(PositionManagerABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectors := make(map[string]string, len(parsed.Methods))
+	for _, method := range parsed.Methods {
+		selectors[method.Sig] = hex.EncodeToString(method.ID)
+	}
+	for sig, selector := range map[string]string{
+		"modifyLiquidities(bytes,uint256)":                                               "dd46508f",
+		"modifyLiquiditiesWithoutUnlock(bytes,bytes[])":                                  "4afe393c",
+		"initializePool((address,address,uint24,int24,address),uint160)":                 "f7020405",
+		"multicall(bytes[])":                                                             "ac9650d8",
+		"getPoolAndPositionInfo(uint256)":                                                "7ba03aad",
+		"permit(address,uint256,uint256,uint256,bytes)":                                  "0f5730f1",
+		"permitBatch(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)": "002a3e3a",
+		"safeTransferFrom(address,address,uint256,bytes)":                                "b88d4fde",
+	} {
+		if selectors[sig] != selector {
+			t.Errorf("%s: selector %q, want %s", sig, selectors[sig], selector)
+		}
+	}
+	for _, event := range []string{"Transfer", "Approval", "ApprovalForAll", "Subscription", "Unsubscription"} {
+		if _, ok := parsed.Events[event]; !ok {
+			t.Errorf("no %s event", event)
+		}
+	}
+}
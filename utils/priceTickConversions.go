@@ -3,11 +3,11 @@ package utils
 import (
 	"math/big"
 
-	"github.com/KyberNetwork/pancake-v3-sdk/constants"
+	"github.com/dangthanhduong01/uniswapv4-sdk/constants"
 	core "github.com/daoleno/uniswap-sdk-core/entities"
 )
 
-func TickToPrice(baseToken *core.Token, quoteToken *core.Token, tick int) (*core.Price, error) {
+func TickToPrice(baseToken, quoteToken core.Currency, tick int) (*core.Price, error) {
 	sqrtRatioX96, err := GetSqrtRatioAtTick(tick)
 	if err != nil {
 		return nil, err
@@ -24,7 +24,7 @@ func TickToPrice(baseToken *core.Token, quoteToken *core.Token, tick int) (*core
 	return core.NewPrice(baseToken, quoteToken, ratioX192, constants.Q192), nil
 }
 
-func PriceToClosestTick(price *core.Price, baseToken, quoteToken *core.Token) (int, error) {
+func PriceToClosestTick(price *core.Price, baseToken, quoteToken core.Currency) (int, error) {
 	sorted, err := SortsBefore(baseToken, quoteToken)
 	if err != nil {
 		return 0, nil
@@ -11,7 +11,9 @@ var (
 	ErrDifferentChain = errors.New("ChainIds of the two currencies are different")
 )
 
-func SortsBefore(currencyA, currencyB *core.Token) (bool, error) {
+// SortsBefore returns true if currencyA sorts before currencyB. Native currencies are
+// represented by the zero address in v4 and therefore always sort first.
+func SortsBefore(currencyA, currencyB core.Currency) (bool, error) {
 	if currencyA.IsNative() {
 		return true, nil
 	}
@@ -21,8 +23,9 @@ func SortsBefore(currencyA, currencyB *core.Token) (bool, error) {
 	if currencyA.ChainId() != currencyB.ChainId() {
 		return false, ErrDifferentChain
 	}
-	if currencyA.Address == currencyB.Address {
+	tokenA, tokenB := currencyA.Wrapped(), currencyB.Wrapped()
+	if tokenA.Address == tokenB.Address {
 		return false, nil
 	}
-	return bytes.Compare(currencyA.Address[:], currencyB.Address[:]) < 0, nil
+	return bytes.Compare(tokenA.Address[:], tokenB.Address[:]) < 0, nil
 }
@@ -0,0 +1,341 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+var (
+	ErrInvalidPriceOrLiquidity = errors.New("invalid price or liquidity")
+	ErrInvalidPrice            = errors.New("invalid price")
+	ErrNotEnoughLiquidity      = errors.New("not enough liquidity")
+	ErrPriceOverflow           = errors.New("price overflow")
+	ErrSafeCastOverflow        = errors.New("safe cast overflow")
+)
+
+var (
+	MaxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+	MaxInt256  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
+)
+
+// sqrtPriceArgs converts the arguments shared by the sqrt price functions
+func sqrtPriceArgs(sqrtPX96, liquidity, amount *big.Int, sqrtPX96U256, liquidityU256, amountU256 *uint256.Int) error {
+	if err := fromBig(sqrtPX96, sqrtPX96U256); err != nil {
+		return err
+	}
+	if err := fromBig(liquidity, liquidityU256); err != nil {
+		return err
+	}
+	return fromBig(amount, amountU256)
+}
+
+/**
+ * Gets the next sqrt price given a delta of currency0, always rounding up
+ * @param sqrtPX96 The starting price, i.e. before accounting for the currency0 delta
+ * @param liquidity The amount of usable liquidity
+ * @param amount How much of currency0 to add or remove from virtual reserves
+ * @param add Whether to add or remove the amount of currency0
+ * @returns The price after adding or removing amount, depending on add
+ */
+func GetNextSqrtPriceFromAmount0RoundingUp(sqrtPX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	var sqrtPX96U256, liquidityU256, amountU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPX96, liquidity, amount, &sqrtPX96U256, &liquidityU256, &amountU256); err != nil {
+		return nil, err
+	}
+	if err := GetNextSqrtPriceFromAmount0RoundingUpU256(&sqrtPX96U256, &liquidityU256, &amountU256, add, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetNextSqrtPriceFromAmount0RoundingUpU256 is GetNextSqrtPriceFromAmount0RoundingUp on uint256 values,
+// the result is written to result which must not alias the inputs
+func GetNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amount *uint256.Int, add bool, result *uint256.Int) error {
+	// we short circuit amount == 0 because the result is otherwise not guaranteed to equal the input price
+	if amount.IsZero() {
+		result.Set(sqrtPX96)
+		return nil
+	}
+	var numerator1, product, denominator uint256.Int
+	numerator1.Lsh(liquidity, 96)
+	_, productOverflows := product.MulOverflow(amount, sqrtPX96)
+
+	if add {
+		if !productOverflows {
+			if _, overflow := denominator.AddOverflow(&numerator1, &product); !overflow {
+				// always fits in 160 bits
+				return MulDivRoundingUpU256(&numerator1, sqrtPX96, &denominator, result)
+			}
+		}
+		// denominator is checked for overflow
+		denominator.Div(&numerator1, sqrtPX96)
+		if _, overflow := denominator.AddOverflow(&denominator, amount); overflow {
+			return ErrMulDivOverflow
+		}
+		DivRoundingUpU256(&numerator1, &denominator, result)
+		return nil
+	}
+
+	// if the product overflows, we know the denominator underflows
+	// in addition, we must check that the denominator does not underflow
+	if productOverflows || !numerator1.Gt(&product) {
+		return ErrPriceOverflow
+	}
+	denominator.Sub(&numerator1, &product)
+	if err := MulDivRoundingUpU256(&numerator1, sqrtPX96, &denominator, result); err != nil {
+		return err
+	}
+	if result.Gt(maxUint160) {
+		return ErrSafeCastOverflow
+	}
+	return nil
+}
+
+/**
+ * Gets the next sqrt price given a delta of currency1, always rounding down
+ * @param sqrtPX96 The starting price, i.e., before accounting for the currency1 delta
+ * @param liquidity The amount of usable liquidity
+ * @param amount How much of currency1 to add, or remove, from virtual reserves
+ * @param add Whether to add, or remove, the amount of currency1
+ * @returns The price after adding or removing `amount`
+ */
+func GetNextSqrtPriceFromAmount1RoundingDown(sqrtPX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	var sqrtPX96U256, liquidityU256, amountU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPX96, liquidity, amount, &sqrtPX96U256, &liquidityU256, &amountU256); err != nil {
+		return nil, err
+	}
+	if err := GetNextSqrtPriceFromAmount1RoundingDownU256(&sqrtPX96U256, &liquidityU256, &amountU256, add, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetNextSqrtPriceFromAmount1RoundingDownU256 is GetNextSqrtPriceFromAmount1RoundingDown on uint256 values,
+// the result is written to result which must not alias the inputs
+func GetNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amount *uint256.Int, add bool, result *uint256.Int) error {
+	var quotient uint256.Int
+	if add {
+		if err := MulDivU256(amount, q96, liquidity, &quotient); err != nil {
+			return err
+		}
+		if _, overflow := result.AddOverflow(sqrtPX96, &quotient); overflow || result.Gt(maxUint160) {
+			return ErrSafeCastOverflow
+		}
+		return nil
+	}
+
+	if err := MulDivRoundingUpU256(amount, q96, liquidity, &quotient); err != nil {
+		return err
+	}
+	if !sqrtPX96.Gt(&quotient) {
+		return ErrNotEnoughLiquidity
+	}
+	// always fits 160 bits
+	result.Sub(sqrtPX96, &quotient)
+	return nil
+}
+
+/**
+ * Gets the next sqrt price given an input amount of currency0 or currency1
+ * @param sqrtPX96 The starting price, i.e., before accounting for the input amount
+ * @param liquidity The amount of usable liquidity
+ * @param amountIn How much of currency0, or currency1, is being swapped in
+ * @param zeroForOne Whether the amount in is currency0 or currency1
+ * @returns The price after adding the input amount to currency0 or currency1
+ */
+func GetNextSqrtPriceFromInput(sqrtPX96, liquidity, amountIn *big.Int, zeroForOne bool) (*big.Int, error) {
+	var sqrtPX96U256, liquidityU256, amountInU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPX96, liquidity, amountIn, &sqrtPX96U256, &liquidityU256, &amountInU256); err != nil {
+		return nil, err
+	}
+	if err := GetNextSqrtPriceFromInputU256(&sqrtPX96U256, &liquidityU256, &amountInU256, zeroForOne, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetNextSqrtPriceFromInputU256 is GetNextSqrtPriceFromInput on uint256 values, the result is
+// written to result which must not alias the inputs
+func GetNextSqrtPriceFromInputU256(sqrtPX96, liquidity, amountIn *uint256.Int, zeroForOne bool, result *uint256.Int) error {
+	if sqrtPX96.IsZero() || liquidity.IsZero() {
+		return ErrInvalidPriceOrLiquidity
+	}
+	// round to make sure that we don't pass the target price
+	if zeroForOne {
+		return GetNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amountIn, true, result)
+	}
+	return GetNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amountIn, true, result)
+}
+
+/**
+ * Gets the next sqrt price given an output amount of currency0 or currency1
+ * @param sqrtPX96 The starting price before accounting for the output amount
+ * @param liquidity The amount of usable liquidity
+ * @param amountOut How much of currency0, or currency1, is being swapped out
+ * @param zeroForOne Whether the amount out is currency1 or currency0
+ * @returns The price after removing the output amount of currency0 or currency1
+ */
+func GetNextSqrtPriceFromOutput(sqrtPX96, liquidity, amountOut *big.Int, zeroForOne bool) (*big.Int, error) {
+	var sqrtPX96U256, liquidityU256, amountOutU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPX96, liquidity, amountOut, &sqrtPX96U256, &liquidityU256, &amountOutU256); err != nil {
+		return nil, err
+	}
+	if err := GetNextSqrtPriceFromOutputU256(&sqrtPX96U256, &liquidityU256, &amountOutU256, zeroForOne, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetNextSqrtPriceFromOutputU256 is GetNextSqrtPriceFromOutput on uint256 values, the result is
+// written to result which must not alias the inputs
+func GetNextSqrtPriceFromOutputU256(sqrtPX96, liquidity, amountOut *uint256.Int, zeroForOne bool, result *uint256.Int) error {
+	if sqrtPX96.IsZero() || liquidity.IsZero() {
+		return ErrInvalidPriceOrLiquidity
+	}
+	// round to make sure that we pass the target price
+	if zeroForOne {
+		return GetNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amountOut, false, result)
+	}
+	return GetNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amountOut, false, result)
+}
+
+/**
+ * Gets the amount0 delta between two prices, i.e. liquidity / sqrt(lower) - liquidity / sqrt(upper)
+ * @param sqrtPriceAX96 A sqrt price
+ * @param sqrtPriceBX96 Another sqrt price
+ * @param liquidity The amount of usable liquidity
+ * @param roundUp Whether to round the amount up or down
+ * @returns Amount of currency0 required to cover a position of size liquidity between the two passed prices
+ */
+func GetAmount0Delta(sqrtPriceAX96, sqrtPriceBX96, liquidity *big.Int, roundUp bool) (*big.Int, error) {
+	var sqrtPriceAX96U256, sqrtPriceBX96U256, liquidityU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPriceAX96, liquidity, sqrtPriceBX96, &sqrtPriceAX96U256, &liquidityU256, &sqrtPriceBX96U256); err != nil {
+		return nil, err
+	}
+	if err := GetAmount0DeltaU256(&sqrtPriceAX96U256, &sqrtPriceBX96U256, &liquidityU256, roundUp, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetAmount0DeltaU256 is GetAmount0Delta on uint256 values, the result is written to result which
+// must not alias the inputs
+func GetAmount0DeltaU256(sqrtPriceAX96, sqrtPriceBX96, liquidity *uint256.Int, roundUp bool, result *uint256.Int) error {
+	if sqrtPriceAX96.Gt(sqrtPriceBX96) {
+		sqrtPriceAX96, sqrtPriceBX96 = sqrtPriceBX96, sqrtPriceAX96
+	}
+	if sqrtPriceAX96.IsZero() {
+		return ErrInvalidPrice
+	}
+
+	var numerator1, numerator2 uint256.Int
+	numerator1.Lsh(liquidity, 96)
+	numerator2.Sub(sqrtPriceBX96, sqrtPriceAX96)
+
+	if roundUp {
+		var amount uint256.Int
+		if err := MulDivRoundingUpU256(&numerator1, &numerator2, sqrtPriceBX96, &amount); err != nil {
+			return err
+		}
+		DivRoundingUpU256(&amount, sqrtPriceAX96, result)
+		return nil
+	}
+	if err := MulDivU256(&numerator1, &numerator2, sqrtPriceBX96, result); err != nil {
+		return err
+	}
+	result.Div(result, sqrtPriceAX96)
+	return nil
+}
+
+/**
+ * Gets the amount1 delta between two prices, i.e. liquidity * (sqrt(upper) - sqrt(lower))
+ * @param sqrtPriceAX96 A sqrt price
+ * @param sqrtPriceBX96 Another sqrt price
+ * @param liquidity The amount of usable liquidity
+ * @param roundUp Whether to round the amount up, or down
+ * @returns Amount of currency1 required to cover a position of size liquidity between the two passed prices
+ */
+func GetAmount1Delta(sqrtPriceAX96, sqrtPriceBX96, liquidity *big.Int, roundUp bool) (*big.Int, error) {
+	var sqrtPriceAX96U256, sqrtPriceBX96U256, liquidityU256, result uint256.Int
+	if err := sqrtPriceArgs(sqrtPriceAX96, liquidity, sqrtPriceBX96, &sqrtPriceAX96U256, &liquidityU256, &sqrtPriceBX96U256); err != nil {
+		return nil, err
+	}
+	if err := GetAmount1DeltaU256(&sqrtPriceAX96U256, &sqrtPriceBX96U256, &liquidityU256, roundUp, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetAmount1DeltaU256 is GetAmount1Delta on uint256 values, the result is written to result which
+// must not alias the inputs
+func GetAmount1DeltaU256(sqrtPriceAX96, sqrtPriceBX96, liquidity *uint256.Int, roundUp bool, result *uint256.Int) error {
+	var numerator uint256.Int
+	if sqrtPriceAX96.Gt(sqrtPriceBX96) {
+		numerator.Sub(sqrtPriceAX96, sqrtPriceBX96)
+	} else {
+		numerator.Sub(sqrtPriceBX96, sqrtPriceAX96)
+	}
+	if roundUp {
+		return MulDivRoundingUpU256(liquidity, &numerator, q96, result)
+	}
+	return MulDivU256(liquidity, &numerator, q96, result)
+}
+
+/**
+ * Helper that gets signed currency0 delta
+ * @param sqrtPriceAX96 A sqrt price
+ * @param sqrtPriceBX96 Another sqrt price
+ * @param liquidity The change in liquidity for which to compute the amount0 delta
+ * @returns Amount of currency0 corresponding to the passed liquidityDelta between the two prices
+ */
+func GetAmount0DeltaSigned(sqrtPriceAX96, sqrtPriceBX96, liquidity *big.Int) (*big.Int, error) {
+	if liquidity.Sign() < 0 {
+		amount, err := GetAmount0Delta(sqrtPriceAX96, sqrtPriceBX96, new(big.Int).Neg(liquidity), false)
+		if err != nil {
+			return nil, err
+		}
+		return toInt256(amount)
+	}
+	amount, err := GetAmount0Delta(sqrtPriceAX96, sqrtPriceBX96, liquidity, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toInt256(amount); err != nil {
+		return nil, err
+	}
+	return amount.Neg(amount), nil
+}
+
+/**
+ * Helper that gets signed currency1 delta
+ * @param sqrtPriceAX96 A sqrt price
+ * @param sqrtPriceBX96 Another sqrt price
+ * @param liquidity The change in liquidity for which to compute the amount1 delta
+ * @returns Amount of currency1 corresponding to the passed liquidityDelta between the two prices
+ */
+func GetAmount1DeltaSigned(sqrtPriceAX96, sqrtPriceBX96, liquidity *big.Int) (*big.Int, error) {
+	if liquidity.Sign() < 0 {
+		amount, err := GetAmount1Delta(sqrtPriceAX96, sqrtPriceBX96, new(big.Int).Neg(liquidity), false)
+		if err != nil {
+			return nil, err
+		}
+		return toInt256(amount)
+	}
+	amount, err := GetAmount1Delta(sqrtPriceAX96, sqrtPriceBX96, liquidity, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toInt256(amount); err != nil {
+		return nil, err
+	}
+	return amount.Neg(amount), nil
+}
+
+func toInt256(x *big.Int) (*big.Int, error) {
+	if x.Cmp(MaxInt256) > 0 {
+		return nil, ErrSafeCastOverflow
+	}
+	return x, nil
+}
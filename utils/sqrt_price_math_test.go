@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+var testE18 = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// e18 returns numerator/denominator of 10^18
+func e18(numerator, denominator int64) *big.Int {
+	x := new(big.Int).Mul(testE18, big.NewInt(numerator))
+	return x.Div(x, big.NewInt(denominator))
+}
+
+func TestGetNextSqrtPriceFromInput(t *testing.T) {
+	price := EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	tests := []struct {
+		name       string
+		liquidity  *big.Int
+		amountIn   *big.Int
+		zeroForOne bool
+		want       string
+	}{
+		{"zero amount zero for one", e18(1, 10), big.NewInt(0), true, price.String()},
+		{"zero amount one for zero", e18(1, 10), big.NewInt(0), false, price.String()},
+		{"0.1 token1", testE18, e18(1, 10), false, "87150978765690771352898345369"},
+		{"0.1 token0", testE18, e18(1, 10), true, "72025602285694852357767227579"},
+		{"amount in above max uint96", e18(10, 1), new(big.Int).Lsh(big.NewInt(1), 100), true, "624999999995069620"},
+		{"returns 1 with enough amount in", big.NewInt(1), new(big.Int).Rsh(testMaxUint256, 1), true, "1"},
+	}
+	for _, tt := range tests {
+		next, err := GetNextSqrtPriceFromInput(price, tt.liquidity, tt.amountIn, tt.zeroForOne)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if next.String() != tt.want {
+			t.Errorf("%s: price %s, want %s", tt.name, next, tt.want)
+		}
+	}
+}
+
+func TestGetNextSqrtPriceFromOutput(t *testing.T) {
+	price := EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	tests := []struct {
+		name       string
+		amountOut  *big.Int
+		zeroForOne bool
+		want       string
+	}{
+		{"0.1 token1", e18(1, 10), true, "71305346262837903834189555302"},
+		{"0.1 token0", e18(1, 10), false, "88031291682515930659493278152"},
+	}
+	for _, tt := range tests {
+		next, err := GetNextSqrtPriceFromOutput(price, testE18, tt.amountOut, tt.zeroForOne)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if next.String() != tt.want {
+			t.Errorf("%s: price %s, want %s", tt.name, next, tt.want)
+		}
+	}
+
+	// the output can not take all the reserves
+	price = new(big.Int).Lsh(big.NewInt(1), 104)
+	if _, err := GetNextSqrtPriceFromOutput(price, big.NewInt(1024), big.NewInt(4), false); err == nil {
+		t.Error("output of the whole token0 reserve did not fail")
+	}
+	if _, err := GetNextSqrtPriceFromOutput(price, big.NewInt(1024), big.NewInt(262144), true); err == nil {
+		t.Error("output of the whole token1 reserve did not fail")
+	}
+}
+
+func TestGetAmountDelta(t *testing.T) {
+	priceA := EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	priceB := EncodeSqrtRatioX96(big.NewInt(121), big.NewInt(100))
+	tests := []struct {
+		name    string
+		delta   func(*big.Int, *big.Int, *big.Int, bool) (*big.Int, error)
+		roundUp bool
+		want    string
+	}{
+		{"amount0 rounded up", GetAmount0Delta, true, "90909090909090910"},
+		{"amount0 rounded down", GetAmount0Delta, false, "90909090909090909"},
+		{"amount1 rounded up", GetAmount1Delta, true, "100000000000000000"},
+		{"amount1 rounded down", GetAmount1Delta, false, "99999999999999999"},
+	}
+	for _, tt := range tests {
+		// the order of the prices does not matter
+		for _, prices := range [][2]*big.Int{{priceA, priceB}, {priceB, priceA}} {
+			amount, err := tt.delta(prices[0], prices[1], testE18, tt.roundUp)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if amount.String() != tt.want {
+				t.Errorf("%s: %s, want %s", tt.name, amount, tt.want)
+			}
+		}
+	}
+
+	amount, err := GetAmount0Delta(priceA, priceB, big.NewInt(0), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.Sign() != 0 {
+		t.Errorf("amount0 of zero liquidity %s", amount)
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+// MaxSwapFee is the maximum fee, in hundredths of a bip, that can be charged on a swap i.e. 100%
+const MaxSwapFee = 1000000
+
+/**
+ * Computes the sqrt price target for the next swap step
+ * @param zeroForOne The direction of the swap, true for currency0 to currency1, false for currency1 to currency0
+ * @param sqrtPriceNextX96 The Q64.96 sqrt price for the next initialized tick
+ * @param sqrtPriceLimitX96 The Q64.96 sqrt price limit. If zero for one, the price cannot be less than this value
+ * after the swap. If one for zero, the price cannot be greater than this value after the swap
+ * @returns The price target for the next swap step
+ */
+func GetSqrtPriceTarget(zeroForOne bool, sqrtPriceNextX96, sqrtPriceLimitX96 *big.Int) *big.Int {
+	if zeroForOne {
+		if sqrtPriceNextX96.Cmp(sqrtPriceLimitX96) < 0 {
+			return sqrtPriceLimitX96
+		}
+		return sqrtPriceNextX96
+	}
+	if sqrtPriceNextX96.Cmp(sqrtPriceLimitX96) > 0 {
+		return sqrtPriceLimitX96
+	}
+	return sqrtPriceNextX96
+}
+
+/**
+ * Computes the result of swapping some amount in, or amount out, given the parameters of the swap.
+ * The fee, plus the amount in, will never exceed the amount remaining if the swap's amountSpecified is negative
+ * @param sqrtPriceCurrentX96 The current sqrt price of the pool
+ * @param sqrtPriceTargetX96 The price that cannot be exceeded, from which the direction of the swap is inferred
+ * @param liquidity The usable liquidity
+ * @param amountRemaining How much input or output amount is remaining to be swapped in/out, negative for exact input
+ * @param feePips The fee taken from the input amount, expressed in hundredths of a bip
+ * @returns The price after swapping the amount in/out, not to exceed the price target, the amount to be swapped in,
+ * of either currency0 or currency1, based on the direction of the swap, the amount to be received, of either currency0
+ * or currency1, based on the direction of the swap and the amount of input that will be taken as a fee
+ */
+func ComputeSwapStep(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, amountRemaining *big.Int, feePips int64) (sqrtPriceNextX96, amountIn, amountOut, feeAmount *big.Int, err error) {
+	var sqrtPriceCurrentX96U256, sqrtPriceTargetX96U256, liquidityU256, amountRemainingU256 uint256.Int
+	if err := sqrtPriceArgs(sqrtPriceCurrentX96, liquidity, sqrtPriceTargetX96, &sqrtPriceCurrentX96U256, &liquidityU256, &sqrtPriceTargetX96U256); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := fromBigSigned(amountRemaining, &amountRemainingU256); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	var sqrtPriceNextX96U256, amountInU256, amountOutU256, feeAmountU256 uint256.Int
+	err = ComputeSwapStepU256(&sqrtPriceCurrentX96U256, &sqrtPriceTargetX96U256, &liquidityU256, &amountRemainingU256, feePips,
+		&sqrtPriceNextX96U256, &amountInU256, &amountOutU256, &feeAmountU256)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return sqrtPriceNextX96U256.ToBig(), amountInU256.ToBig(), amountOutU256.ToBig(), feeAmountU256.ToBig(), nil
+}
+
+// ComputeSwapStepU256 is ComputeSwapStep on uint256 values. amountRemaining is a two's complement
+// int256 and the results are written to the last four arguments, which must not alias the inputs.
+func ComputeSwapStepU256(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, amountRemaining *uint256.Int, feePips int64,
+	sqrtPriceNextX96, amountIn, amountOut, feeAmount *uint256.Int) error {
+	zeroForOne := !sqrtPriceCurrentX96.Lt(sqrtPriceTargetX96)
+	exactIn := amountRemaining.Sign() < 0
+
+	var err error
+	var feeComplement, maxSwapFee, fee uint256.Int
+	feeComplement.SetUint64(uint64(MaxSwapFee - feePips))
+	maxSwapFee.SetUint64(MaxSwapFee)
+	fee.SetUint64(uint64(feePips))
+
+	if exactIn {
+		var amountRemainingAbs, amountRemainingLessFee uint256.Int
+		amountRemainingAbs.Neg(amountRemaining)
+		if err := MulDivU256(&amountRemainingAbs, &feeComplement, &maxSwapFee, &amountRemainingLessFee); err != nil {
+			return err
+		}
+		if zeroForOne {
+			err = GetAmount0DeltaU256(sqrtPriceTargetX96, sqrtPriceCurrentX96, liquidity, true, amountIn)
+		} else {
+			err = GetAmount1DeltaU256(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, true, amountIn)
+		}
+		if err != nil {
+			return err
+		}
+		if !amountRemainingLessFee.Lt(amountIn) {
+			// amountIn is capped by the target price
+			sqrtPriceNextX96.Set(sqrtPriceTargetX96)
+			if feePips == MaxSwapFee {
+				feeAmount.Set(amountIn) // amountIn is always 0 here, as amountRemainingLessFee == 0 and amountRemainingLessFee >= amountIn
+			} else if err := MulDivRoundingUpU256(amountIn, &fee, &feeComplement, feeAmount); err != nil {
+				return err
+			}
+		} else {
+			// exhaust the remaining amount
+			amountIn.Set(&amountRemainingLessFee)
+			if err := GetNextSqrtPriceFromInputU256(sqrtPriceCurrentX96, liquidity, &amountRemainingLessFee, zeroForOne, sqrtPriceNextX96); err != nil {
+				return err
+			}
+			// we didn't reach the target, so take the remainder of the maximum input as fee
+			feeAmount.Sub(&amountRemainingAbs, amountIn)
+		}
+		if zeroForOne {
+			return GetAmount1DeltaU256(sqrtPriceNextX96, sqrtPriceCurrentX96, liquidity, false, amountOut)
+		}
+		return GetAmount0DeltaU256(sqrtPriceCurrentX96, sqrtPriceNextX96, liquidity, false, amountOut)
+	}
+
+	if zeroForOne {
+		err = GetAmount1DeltaU256(sqrtPriceTargetX96, sqrtPriceCurrentX96, liquidity, false, amountOut)
+	} else {
+		err = GetAmount0DeltaU256(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, false, amountOut)
+	}
+	if err != nil {
+		return err
+	}
+	if !amountRemaining.Lt(amountOut) {
+		// amountOut is capped by the target price
+		sqrtPriceNextX96.Set(sqrtPriceTargetX96)
+	} else {
+		// cap the output amount to not exceed the remaining output amount
+		amountOut.Set(amountRemaining)
+		if err := GetNextSqrtPriceFromOutputU256(sqrtPriceCurrentX96, liquidity, amountOut, zeroForOne, sqrtPriceNextX96); err != nil {
+			return err
+		}
+	}
+	if zeroForOne {
+		err = GetAmount0DeltaU256(sqrtPriceNextX96, sqrtPriceCurrentX96, liquidity, true, amountIn)
+	} else {
+		err = GetAmount1DeltaU256(sqrtPriceCurrentX96, sqrtPriceNextX96, liquidity, true, amountIn)
+	}
+	if err != nil {
+		return err
+	}
+	// feePips cannot be MaxSwapFee for exact out
+	return MulDivRoundingUpU256(amountIn, &fee, &feeComplement, feeAmount)
+}
+
+// GetSqrtPriceTargetU256 is GetSqrtPriceTarget on uint256 values, it returns one of its arguments
+func GetSqrtPriceTargetU256(zeroForOne bool, sqrtPriceNextX96, sqrtPriceLimitX96 *uint256.Int) *uint256.Int {
+	if zeroForOne == sqrtPriceNextX96.Lt(sqrtPriceLimitX96) {
+		return sqrtPriceLimitX96
+	}
+	return sqrtPriceNextX96
+}
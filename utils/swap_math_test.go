@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// swapStep is the expected result of ComputeSwapStep
+type swapStep struct {
+	sqrtPriceNextX96, amountIn, amountOut, feeAmount string
+}
+
+func TestComputeSwapStep(t *testing.T) {
+	price := EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	sqrtP := new(big.Int).Lsh(big.NewInt(1), 104)
+	capped := EncodeSqrtRatioX96(big.NewInt(101), big.NewInt(100))
+	scaled := func(x *big.Int, numerator, denominator int64) *big.Int {
+		y := new(big.Int).Mul(x, big.NewInt(numerator))
+		return y.Div(y, big.NewInt(denominator))
+	}
+	tests := []struct {
+		name                       string
+		sqrtPriceX96, targetX96    *big.Int
+		liquidity, amountRemaining *big.Int
+		feePips                    int64
+		want                       swapStep
+	}{
+		{"exact amount in capped at price target in one for zero", price, capped, e18(2, 1), e18(-1, 1), 600,
+			swapStep{capped.String(), "9975124224178055", "9925619580021728", "5988667735148"}},
+		{"exact amount out capped at price target in one for zero", price, capped, e18(2, 1), e18(1, 1), 600,
+			swapStep{capped.String(), "9975124224178055", "9925619580021728", "5988667735148"}},
+		{"exact amount in fully spent in one for zero", price, EncodeSqrtRatioX96(big.NewInt(1000), big.NewInt(100)), e18(2, 1), e18(-1, 1), 600,
+			swapStep{"", "999400000000000000", "666399946655997866", "600000000000000"}},
+		{"exact amount out fully received in one for zero", price, EncodeSqrtRatioX96(big.NewInt(10000), big.NewInt(100)), e18(2, 1), e18(1, 1), 600,
+			swapStep{"", "2000000000000000000", "1000000000000000000", "1200720432259356"}},
+		{"amount out capped at the desired amount out",
+			mustBig(t, "417332158212080721273783715441582"), mustBig(t, "1452870262520218020823638996"),
+			mustBig(t, "159344665391607089467575320103"), big.NewInt(1), 1,
+			swapStep{"417332158212080721273783715441581", "1", "1", "1"}},
+		{"target price of 1 uses partial input amount", big.NewInt(2), big.NewInt(1), big.NewInt(1),
+			mustBig(t, "-3915081100057732413702495386755767"), 1,
+			swapStep{"1", "39614081257132168796771975168", "0", "39614120871253040049813"}},
+		// v4 keeps the input less fee as amountIn when the price does not move, v3 took it all as fee
+		{"entire input amount taken as fee", big.NewInt(2413), big.NewInt(79887613182836312),
+			mustBig(t, "1985041575832132834610021537970"), big.NewInt(-10), 1872,
+			swapStep{"2413", "9", "0", "1"}},
+		{"intermediate insufficient liquidity in one for zero exact output", sqrtP, scaled(sqrtP, 11, 10), big.NewInt(1024), big.NewInt(4), 3000,
+			swapStep{scaled(sqrtP, 11, 10).String(), "26215", "0", "79"}},
+		{"intermediate insufficient liquidity in zero for one exact output", sqrtP, scaled(sqrtP, 9, 10), big.NewInt(1024), big.NewInt(263000), 3000,
+			swapStep{scaled(sqrtP, 9, 10).String(), "1", "26214", "1"}},
+		{"fee of 100% takes the whole exact input", price, capped, e18(2, 1), e18(-1, 1), MaxSwapFee,
+			swapStep{price.String(), "0", "0", "1000000000000000000"}},
+		{"fee of 100% at the price target", price, price, e18(2, 1), e18(-1, 1), MaxSwapFee,
+			swapStep{price.String(), "0", "0", "0"}},
+		{"fee of 100% zero for one", capped, price, e18(2, 1), big.NewInt(-12345), MaxSwapFee,
+			swapStep{capped.String(), "0", "0", "12345"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, amountIn, amountOut, feeAmount, err := ComputeSwapStep(tt.sqrtPriceX96, tt.targetX96, tt.liquidity, tt.amountRemaining, tt.feePips)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := swapStep{next.String(), amountIn.String(), amountOut.String(), feeAmount.String()}
+			want := tt.want
+			if want.sqrtPriceNextX96 == "" {
+				// the amount is used up before the target, at the price it moves to
+				var expected *big.Int
+				zeroForOne := tt.sqrtPriceX96.Cmp(tt.targetX96) >= 0
+				if tt.amountRemaining.Sign() < 0 {
+					expected, err = GetNextSqrtPriceFromInput(tt.sqrtPriceX96, tt.liquidity, amountIn, zeroForOne)
+				} else {
+					expected, err = GetNextSqrtPriceFromOutput(tt.sqrtPriceX96, tt.liquidity, amountOut, zeroForOne)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if next.Cmp(tt.targetX96) >= 0 {
+					t.Errorf("price %s reached the target %s", next, tt.targetX96)
+				}
+				want.sqrtPriceNextX96 = expected.String()
+			}
+			if got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+
+			// the input and fee never exceed an exact input, the output never exceeds an exact output
+			if tt.amountRemaining.Sign() < 0 {
+				spent := new(big.Int).Add(amountIn, feeAmount)
+				if spent.Cmp(new(big.Int).Neg(tt.amountRemaining)) > 0 {
+					t.Errorf("spent %s of %s", spent, tt.amountRemaining)
+				}
+			} else if amountOut.Cmp(tt.amountRemaining) > 0 {
+				t.Errorf("received %s of %s", amountOut, tt.amountRemaining)
+			}
+		})
+	}
+}
+
+func TestComputeSwapStepExactOutputMaxFee(t *testing.T) {
+	price := EncodeSqrtRatioX96(big.NewInt(1), big.NewInt(1))
+	target := EncodeSqrtRatioX96(big.NewInt(101), big.NewInt(100))
+	_, _, _, _, err := ComputeSwapStep(price, target, e18(2, 1), e18(1, 1), MaxSwapFee)
+	if !errors.Is(err, ErrZeroDenominator) {
+		t.Errorf("exact output with a fee of 100%% returned %v", err)
+	}
+}
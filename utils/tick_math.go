@@ -4,13 +4,15 @@ import (
 	"errors"
 	"math/big"
 
-	"github.com/KyberNetwork/pancake-v3-sdk/constants"
-	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/holiman/uint256"
 )
 
 const (
 	MinTick = -887272  // The minimum tick that can be used on any pool.
 	MaxTick = -MinTick // The maximum tick that can be used on any pool.
+
+	MinTickSpacing = 1     // The minimum tick spacing value drawn from the range of type int16 that is greater than 0
+	MaxTickSpacing = 32767 // The maximum tick spacing value drawn from the range of type int16
 )
 
 var (
@@ -24,33 +26,44 @@ var (
 	ErrInvalidSqrtRatio = errors.New("invalid sqrt ratio")
 )
 
-func mulShift(val *big.Int, mulBy *big.Int) *big.Int {
+// MinUsableTick returns the minimum tick usable by a pool with the given tick spacing
+func MinUsableTick(tickSpacing int) int {
+	return (MinTick / tickSpacing) * tickSpacing
+}
 
-	return new(big.Int).Rsh(new(big.Int).Mul(val, mulBy), 128)
+// MaxUsableTick returns the maximum tick usable by a pool with the given tick spacing
+func MaxUsableTick(tickSpacing int) int {
+	return (MaxTick / tickSpacing) * tickSpacing
 }
 
 var (
-	sqrtConst1, _  = new(big.Int).SetString("fffcb933bd6fad37aa2d162d1a594001", 16)
-	sqrtConst2, _  = new(big.Int).SetString("100000000000000000000000000000000", 16)
-	sqrtConst3, _  = new(big.Int).SetString("fff97272373d413259a46990580e213a", 16)
-	sqrtConst4, _  = new(big.Int).SetString("fff2e50f5f656932ef12357cf3c7fdcc", 16)
-	sqrtConst5, _  = new(big.Int).SetString("ffe5caca7e10e4e61c3624eaa0941cd0", 16)
-	sqrtConst6, _  = new(big.Int).SetString("ffcb9843d60f6159c9db58835c926644", 16)
-	sqrtConst7, _  = new(big.Int).SetString("ff973b41fa98c081472e6896dfb254c0", 16)
-	sqrtConst8, _  = new(big.Int).SetString("ff2ea16466c96a3843ec78b326b52861", 16)
-	sqrtConst9, _  = new(big.Int).SetString("fe5dee046a99a2a811c461f1969c3053", 16)
-	sqrtConst10, _ = new(big.Int).SetString("fcbe86c7900a88aedcffc83b479aa3a4", 16)
-	sqrtConst11, _ = new(big.Int).SetString("f987a7253ac413176f2b074cf7815e54", 16)
-	sqrtConst12, _ = new(big.Int).SetString("f3392b0822b70005940c7a398e4b70f3", 16)
-	sqrtConst13, _ = new(big.Int).SetString("e7159475a2c29b7443b29c7fa6e889d9", 16)
-	sqrtConst14, _ = new(big.Int).SetString("d097f3bdfd2022b8845ad8f792aa5825", 16)
-	sqrtConst15, _ = new(big.Int).SetString("a9f746462d870fdf8a65dc1f90e061e5", 16)
-	sqrtConst16, _ = new(big.Int).SetString("70d869a156d2a1b890bb3df62baf32f7", 16)
-	sqrtConst17, _ = new(big.Int).SetString("31be135f97d08fd981231505542fcfa6", 16)
-	sqrtConst18, _ = new(big.Int).SetString("9aa508b5b7a84e1c677de54f3e99bc9", 16)
-	sqrtConst19, _ = new(big.Int).SetString("5d6af8dedb81196699c329225ee604", 16)
-	sqrtConst20, _ = new(big.Int).SetString("2216e584f5fa1ea926041bedfe98", 16)
-	sqrtConst21, _ = new(big.Int).SetString("48a170391f7dc42444e8fa2", 16)
+	sqrtConst1 = uint256.MustFromHex("0xfffcb933bd6fad37aa2d162d1a594001")
+	sqrtConst2 = uint256.MustFromHex("0x100000000000000000000000000000000")
+	// sqrtRatioMultipliers[i] is applied when bit i+1 of the absolute tick is set
+	sqrtRatioMultipliers = [...]*uint256.Int{
+		uint256.MustFromHex("0xfff97272373d413259a46990580e213a"),
+		uint256.MustFromHex("0xfff2e50f5f656932ef12357cf3c7fdcc"),
+		uint256.MustFromHex("0xffe5caca7e10e4e61c3624eaa0941cd0"),
+		uint256.MustFromHex("0xffcb9843d60f6159c9db58835c926644"),
+		uint256.MustFromHex("0xff973b41fa98c081472e6896dfb254c0"),
+		uint256.MustFromHex("0xff2ea16466c96a3843ec78b326b52861"),
+		uint256.MustFromHex("0xfe5dee046a99a2a811c461f1969c3053"),
+		uint256.MustFromHex("0xfcbe86c7900a88aedcffc83b479aa3a4"),
+		uint256.MustFromHex("0xf987a7253ac413176f2b074cf7815e54"),
+		uint256.MustFromHex("0xf3392b0822b70005940c7a398e4b70f3"),
+		uint256.MustFromHex("0xe7159475a2c29b7443b29c7fa6e889d9"),
+		uint256.MustFromHex("0xd097f3bdfd2022b8845ad8f792aa5825"),
+		uint256.MustFromHex("0xa9f746462d870fdf8a65dc1f90e061e5"),
+		uint256.MustFromHex("0x70d869a156d2a1b890bb3df62baf32f7"),
+		uint256.MustFromHex("0x31be135f97d08fd981231505542fcfa6"),
+		uint256.MustFromHex("0x9aa508b5b7a84e1c677de54f3e99bc9"),
+		uint256.MustFromHex("0x5d6af8dedb81196699c329225ee604"),
+		uint256.MustFromHex("0x2216e584f5fa1ea926041bedfe98"),
+		uint256.MustFromHex("0x48a170391f7dc42444e8fa2"),
+	}
+
+	minSqrtRatio = uint256.MustFromBig(MinSqrtRatio)
+	maxSqrtRatio = uint256.MustFromBig(MaxSqrtRatio)
 )
 
 /**
@@ -58,92 +71,51 @@ var (
  * @param tick the tick for which to compute the sqrt ratio
  */
 func GetSqrtRatioAtTick(tick int) (*big.Int, error) {
+	var result uint256.Int
+	if err := GetSqrtRatioAtTickU256(tick, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
+}
+
+// GetSqrtRatioAtTickU256 is GetSqrtRatioAtTick writing the sqrt ratio to result
+func GetSqrtRatioAtTickU256(tick int, result *uint256.Int) error {
 	if tick < MinTick || tick > MaxTick {
-		return nil, ErrInvalidTick
+		return ErrInvalidTick
 	}
 	absTick := tick
 	if tick < 0 {
 		absTick = -tick
 	}
-	var ratio *big.Int
+	var ratio uint256.Int
 	if absTick&0x1 != 0 {
-		ratio = sqrtConst1
+		ratio.Set(sqrtConst1)
 	} else {
-		ratio = sqrtConst2
-	}
-	if (absTick & 0x2) != 0 {
-		ratio = mulShift(ratio, sqrtConst3)
-	}
-	if (absTick & 0x4) != 0 {
-		ratio = mulShift(ratio, sqrtConst4)
-	}
-	if (absTick & 0x8) != 0 {
-		ratio = mulShift(ratio, sqrtConst5)
-	}
-	if (absTick & 0x10) != 0 {
-		ratio = mulShift(ratio, sqrtConst6)
-	}
-	if (absTick & 0x20) != 0 {
-		ratio = mulShift(ratio, sqrtConst7)
-	}
-	if (absTick & 0x40) != 0 {
-		ratio = mulShift(ratio, sqrtConst8)
-	}
-	if (absTick & 0x80) != 0 {
-		ratio = mulShift(ratio, sqrtConst9)
-	}
-	if (absTick & 0x100) != 0 {
-		ratio = mulShift(ratio, sqrtConst10)
-	}
-	if (absTick & 0x200) != 0 {
-		ratio = mulShift(ratio, sqrtConst11)
-	}
-	if (absTick & 0x400) != 0 {
-		ratio = mulShift(ratio, sqrtConst12)
-	}
-	if (absTick & 0x800) != 0 {
-		ratio = mulShift(ratio, sqrtConst13)
+		ratio.Set(sqrtConst2)
 	}
-	if (absTick & 0x1000) != 0 {
-		ratio = mulShift(ratio, sqrtConst14)
-	}
-	if (absTick & 0x2000) != 0 {
-		ratio = mulShift(ratio, sqrtConst15)
-	}
-	if (absTick & 0x4000) != 0 {
-		ratio = mulShift(ratio, sqrtConst16)
-	}
-	if (absTick & 0x8000) != 0 {
-		ratio = mulShift(ratio, sqrtConst17)
-	}
-	if (absTick & 0x10000) != 0 {
-		ratio = mulShift(ratio, sqrtConst18)
-	}
-	if (absTick & 0x20000) != 0 {
-		ratio = mulShift(ratio, sqrtConst19)
-	}
-	if (absTick & 0x40000) != 0 {
-		ratio = mulShift(ratio, sqrtConst20)
-	}
-	if (absTick & 0x80000) != 0 {
-		ratio = mulShift(ratio, sqrtConst21)
+	for i, multiplier := range sqrtRatioMultipliers {
+		if absTick&(0x2<<i) != 0 {
+			ratio.Mul(&ratio, multiplier)
+			ratio.Rsh(&ratio, 128)
+		}
 	}
 	if tick > 0 {
-		ratio = new(big.Int).Div(entities.MaxUint256, ratio)
+		ratio.Div(maxUint256, &ratio)
 	}
 
-	// back to Q96
-	if new(big.Int).Rem(ratio, Q32).Cmp(constants.Zero) > 0 {
-		return new(big.Int).Add((new(big.Int).Div(ratio, Q32)), constants.One), nil
-	} else {
-		return new(big.Int).Div(ratio, Q32), nil
+	// back to Q96, rounding up
+	roundUp := ratio[0]&0xffffffff != 0
+	result.Rsh(&ratio, 32)
+	if roundUp {
+		result.AddUint64(result, 1)
 	}
+	return nil
 }
 
 var (
-	magicSqrt10001, _ = new(big.Int).SetString("255738958999603826347141", 10)
-	magicTickLow, _   = new(big.Int).SetString("3402992956809132418596140100660247210", 10)
-	magicTickHigh, _  = new(big.Int).SetString("291339464771989622907027621153398088495", 10)
+	magicSqrt10001 = uint256.MustFromDecimal("255738958999603826347141")
+	magicTickLow   = uint256.MustFromDecimal("3402992956809132418596140100660247210")
+	magicTickHigh  = uint256.MustFromDecimal("291339464771989622907027621153398088495")
 )
 
 /**
@@ -152,46 +124,64 @@ var (
  * @param sqrtRatioX96 the sqrt ratio as a Q64.96 for which to compute the tick
  */
 func GetTickAtSqrtRatio(sqrtRatioX96 *big.Int) (int, error) {
-	if sqrtRatioX96.Cmp(MinSqrtRatio) < 0 || sqrtRatioX96.Cmp(MaxSqrtRatio) >= 0 {
+	var sqrtRatioX96U256 uint256.Int
+	if err := fromBig(sqrtRatioX96, &sqrtRatioX96U256); err != nil {
 		return 0, ErrInvalidSqrtRatio
 	}
-	sqrtRatioX128 := new(big.Int).Lsh(sqrtRatioX96, 32)
-	msb, err := MostSignificantBit(sqrtRatioX128)
-	if err != nil {
-		return 0, err
+	return GetTickAtSqrtRatioU256(&sqrtRatioX96U256)
+}
+
+// GetTickAtSqrtRatioU256 is GetTickAtSqrtRatio on a uint256 sqrt ratio
+func GetTickAtSqrtRatioU256(sqrtRatioX96 *uint256.Int) (int, error) {
+	if sqrtRatioX96.Lt(minSqrtRatio) || !sqrtRatioX96.Lt(maxSqrtRatio) {
+		return 0, ErrInvalidSqrtRatio
 	}
-	var r *big.Int
-	if big.NewInt(msb).Cmp(big.NewInt(128)) >= 0 {
-		r = new(big.Int).Rsh(sqrtRatioX128, uint(msb-127))
+	var sqrtRatioX128, r uint256.Int
+	sqrtRatioX128.Lsh(sqrtRatioX96, 32)
+	msb := sqrtRatioX128.BitLen() - 1
+	if msb >= 128 {
+		r.Rsh(&sqrtRatioX128, uint(msb-127))
 	} else {
-		r = new(big.Int).Lsh(sqrtRatioX128, uint(127-msb))
+		r.Lsh(&sqrtRatioX128, uint(127-msb))
 	}
 
-	log2 := new(big.Int).Lsh(new(big.Int).Sub(big.NewInt(msb), big.NewInt(128)), 64)
+	// log2 is a signed 64.64 fixed point number in two's complement
+	var log2 uint256.Int
+	if msb >= 128 {
+		log2.SetUint64(uint64(msb - 128))
+	} else {
+		log2.SetUint64(uint64(128 - msb))
+		log2.Neg(&log2)
+	}
+	log2.Lsh(&log2, 64)
 
 	for i := 0; i < 14; i++ {
-		r = new(big.Int).Rsh(new(big.Int).Mul(r, r), 127)
-		f := new(big.Int).Rsh(r, 128)
-		log2 = new(big.Int).Or(log2, new(big.Int).Lsh(f, uint(63-i)))
-		r = new(big.Int).Rsh(r, uint(f.Int64()))
+		r.Mul(&r, &r)
+		r.Rsh(&r, 127)
+		if r.BitLen() > 128 {
+			log2[0] |= 1 << uint(63-i)
+			r.Rsh(&r, 1)
+		}
 	}
 
-	logSqrt10001 := new(big.Int).Mul(log2, magicSqrt10001)
+	var logSqrt10001, tickLow, tickHigh uint256.Int
+	logSqrt10001.Mul(&log2, magicSqrt10001)
+	tickLow.Sub(&logSqrt10001, magicTickLow)
+	tickLow.SRsh(&tickLow, 128)
+	tickHigh.Add(&logSqrt10001, magicTickHigh)
+	tickHigh.SRsh(&tickHigh, 128)
 
-	tickLow := new(big.Int).Rsh(new(big.Int).Sub(logSqrt10001, magicTickLow), 128).Int64()
-	tickHigh := new(big.Int).Rsh(new(big.Int).Add(logSqrt10001, magicTickHigh), 128).Int64()
-
-	if tickLow == tickHigh {
-		return int(tickLow), nil
+	low, high := int(int64(tickLow[0])), int(int64(tickHigh[0]))
+	if low == high {
+		return low, nil
 	}
 
-	sqrtRatio, err := GetSqrtRatioAtTick(int(tickHigh))
-	if err != nil {
+	var sqrtRatio uint256.Int
+	if err := GetSqrtRatioAtTickU256(high, &sqrtRatio); err != nil {
 		return 0, err
 	}
-	if sqrtRatio.Cmp(sqrtRatioX96) <= 0 {
-		return int(tickHigh), nil
-	} else {
-		return int(tickLow), nil
+	if !sqrtRatio.Gt(sqrtRatioX96) {
+		return high, nil
 	}
+	return low, nil
 }
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestGetSqrtRatioAtTick(t *testing.T) {
+	tests := []struct {
+		tick  int
+		ratio string
+	}{
+		{MinTick, "4295128739"},
+		{MinTick + 1, "4295343490"},
+		{MaxTick - 1, "1461373636630004318706518188784493106690254656249"},
+		{MaxTick, "1461446703485210103287273052203988822378723970342"},
+		{0, "79228162514264337593543950336"},
+	}
+	for _, tt := range tests {
+		ratio, err := GetSqrtRatioAtTick(tt.tick)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ratio.String() != tt.ratio {
+			t.Errorf("tick %d: ratio %s, want %s", tt.tick, ratio, tt.ratio)
+		}
+	}
+	if ratio, _ := GetSqrtRatioAtTick(MinTick); ratio.Cmp(MinSqrtRatio) != 0 {
+		t.Errorf("min tick ratio %s is not MinSqrtRatio", ratio)
+	}
+	if ratio, _ := GetSqrtRatioAtTick(MaxTick); ratio.Cmp(MaxSqrtRatio) != 0 {
+		t.Errorf("max tick ratio %s is not MaxSqrtRatio", ratio)
+	}
+
+	for _, tick := range []int{MinTick - 1, MaxTick + 1} {
+		if _, err := GetSqrtRatioAtTick(tick); !errors.Is(err, ErrInvalidTick) {
+			t.Errorf("tick %d returned %v", tick, err)
+		}
+	}
+}
+
+func TestGetTickAtSqrtRatio(t *testing.T) {
+	tests := []struct {
+		ratio *big.Int
+		tick  int
+	}{
+		{MinSqrtRatio, MinTick},
+		{big.NewInt(4295343490), MinTick + 1},
+		{new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1)), MaxTick - 1},
+		{mustBig(t, "1461373636630004318706518188784493106690254656249"), MaxTick - 1},
+		{mustBig(t, "1461373636630004318706518188784493106690254656248"), MaxTick - 2},
+		{new(big.Int).Lsh(big.NewInt(1), 96), 0},
+	}
+	for _, tt := range tests {
+		tick, err := GetTickAtSqrtRatio(tt.ratio)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tick != tt.tick {
+			t.Errorf("ratio %s: tick %d, want %d", tt.ratio, tick, tt.tick)
+		}
+	}
+
+	for _, ratio := range []*big.Int{new(big.Int).Sub(MinSqrtRatio, big.NewInt(1)), MaxSqrtRatio} {
+		if _, err := GetTickAtSqrtRatio(ratio); !errors.Is(err, ErrInvalidSqrtRatio) {
+			t.Errorf("ratio %s returned %v", ratio, err)
+		}
+	}
+}
+
+// TestTickAtSqrtRatioRoundTrip checks the tick of a ratio is the greatest tick whose ratio is not above it
+func TestTickAtSqrtRatioRoundTrip(t *testing.T) {
+	for _, tick := range []int{MinTick, MinTick + 1, -500000, -887, -60, -1, 0, 1, 60, 887, 500000, MaxTick - 1} {
+		ratio, err := GetSqrtRatioAtTick(tick)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, delta := range []int64{0, 1} {
+			got, err := GetTickAtSqrtRatio(new(big.Int).Add(ratio, big.NewInt(delta)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tick {
+				t.Errorf("ratio of tick %d plus %d: tick %d", tick, delta, got)
+			}
+		}
+		if tick == MinTick {
+			continue
+		}
+		got, err := GetTickAtSqrtRatio(new(big.Int).Sub(ratio, big.NewInt(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tick-1 {
+			t.Errorf("ratio of tick %d minus 1: tick %d", tick, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+var ErrUint256Overflow = errors.New("value does not fit in 256 bits")
+
+// fromBig converts an unsigned big.Int into z, failing if it is negative or wider than 256 bits
+func fromBig(x *big.Int, z *uint256.Int) error {
+	if x.Sign() < 0 || z.SetFromBig(x) {
+		return ErrUint256Overflow
+	}
+	return nil
+}
+
+// fromBigSigned converts a signed big.Int into its int256 two's complement representation in z
+func fromBigSigned(x *big.Int, z *uint256.Int) error {
+	if x.BitLen() > 255 && !(x.Sign() < 0 && x.BitLen() == 256 && x.TrailingZeroBits() == 255) {
+		return ErrUint256Overflow
+	}
+	z.SetFromBig(x)
+	return nil
+}
+
+var (
+	maxUint256 = new(uint256.Int).SetAllOne()
+	maxUint160 = new(uint256.Int).Rsh(maxUint256, 96)
+	maxUint128 = new(uint256.Int).Rsh(maxUint256, 128)
+	q96        = new(uint256.Int).Lsh(uint256.NewInt(1), 96)
+)
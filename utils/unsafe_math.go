@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/holiman/uint256"
+)
+
+/**
+ * Returns ceil(x / y). Division by 0 returns 0, like the unchecked EVM division
+ * @param x The dividend
+ * @param y The divisor
+ */
+func DivRoundingUp(x, y *big.Int) *big.Int {
+	if y.Sign() == 0 {
+		return new(big.Int)
+	}
+	quotient, remainder := new(big.Int).QuoRem(x, y, new(big.Int))
+	if remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+// DivRoundingUpU256 is DivRoundingUp on uint256 values, the result is written to result
+// which must not alias the inputs
+func DivRoundingUpU256(x, y, result *uint256.Int) {
+	var remainder uint256.Int
+	result.DivMod(x, y, &remainder)
+	if !remainder.IsZero() {
+		result.AddUint64(result, 1)
+	}
+}
+
+/**
+ * Calculates floor(a×b÷denominator) without checking for overflow, the product wraps around at 2^256.
+ * Division by 0 returns 0, like the unchecked EVM division
+ * @param a The multiplicand
+ * @param b The multiplier
+ * @param denominator The divisor
+ */
+func SimpleMulDiv(a, b, denominator *big.Int) *big.Int {
+	if denominator.Sign() == 0 {
+		return new(big.Int)
+	}
+	product := new(big.Int).Mul(a, b)
+	product.And(product, entities.MaxUint256)
+	return product.Div(product, denominator)
+}